@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/adapters/secondary/repository"
+	"github.com/hashload/boss/internal/core/services/audit"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/spf13/cobra"
+)
+
+// auditFormatText, auditFormatJSON and auditFormatSarif are the values
+// accepted by 'boss audit --format'.
+const (
+	auditFormatText  = "text"
+	auditFormatJSON  = "json"
+	auditFormatSarif = "sarif"
+)
+
+// auditOptions carries the flags of 'boss audit'.
+type auditOptions struct {
+	format string
+	output string
+	failOn string
+	dbDir  string
+}
+
+// auditCmdRegister registers the audit command and its update sub-command.
+func auditCmdRegister(root *cobra.Command) {
+	options := auditOptions{}
+
+	auditCmd := &cobra.Command{
+		Use:   cmdNameAudit,
+		Short: "Check locked dependencies against a vulnerability advisory database",
+		Long: `Match the versions pinned in boss-lock.json against a local advisory database in OSV format.
+The database is fetched with 'boss audit update' and works offline afterwards.
+The command exits with status 1 when a finding reaches the --fail-on severity, so it can gate a CI job.`,
+		Example: `  Audit the project:
+  boss audit
+
+  Write a SARIF report and only fail on high or critical findings:
+  boss audit --format sarif --output audit.sarif --fail-on high
+
+  Refresh the advisory database:
+  boss audit update --source https://example.com/osv/all.zip`,
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			runAudit(options)
+		},
+	}
+
+	auditCmd.Flags().StringVar(&options.format, "format", auditFormatText,
+		fmt.Sprintf("Report format (%s, %s or %s)", auditFormatText, auditFormatJSON, auditFormatSarif))
+	auditCmd.Flags().StringVarP(&options.output, "output", "o", "", "Write the report to a file instead of stdout")
+	auditCmd.Flags().StringVar(&options.failOn, "fail-on", string(audit.SeverityLow),
+		"Lowest severity that makes the command fail (low, medium, high, critical or none)")
+	auditCmd.PersistentFlags().StringVar(&options.dbDir, "db", "",
+		"Advisory database directory (defaults to the Boss home)")
+
+	var source string
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Refresh the local advisory database",
+		Long: "Download or copy OSV advisories into the local database. The source is an http(s) URL " +
+			"serving a JSON document or a zip archive of advisories, or a local directory or file. " +
+			"Without --source the value set by 'boss config audit source' is used.",
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			runAuditUpdate(source, options.dbDir)
+		},
+	}
+	updateCmd.Flags().StringVar(&source, "source", "", "Advisory source URL or directory")
+
+	auditCmd.AddCommand(updateCmd)
+	root.AddCommand(auditCmd)
+}
+
+// runAudit matches the lock file against the advisory database and reports the findings.
+func runAudit(options auditOptions) {
+	format := strings.ToLower(strings.TrimSpace(options.format))
+	if format != auditFormatText && format != auditFormatJSON && format != auditFormatSarif {
+		msg.Die("❌ Unsupported report format %q. Supported formats: %s, %s, %s.",
+			options.format, auditFormatText, auditFormatJSON, auditFormatSarif)
+	}
+
+	threshold, err := audit.ParseSeverity(options.failOn)
+	if err != nil {
+		msg.Die("❌ Invalid --fail-on value: %s", err)
+	}
+
+	advisories, err := audit.LoadDatabase(auditDatabaseDir(options.dbDir))
+	if errors.Is(err, audit.ErrDatabaseMissing) {
+		msg.Die("❌ %s. Run 'boss audit update' first.", err)
+	}
+	if err != nil {
+		msg.Die("❌ Failed to load the advisory database: %s", err)
+	}
+
+	lock, err := repository.NewFileLockRepository(filesystem.NewOSFileSystem()).Load(consts.FilePackageLock)
+	if err != nil {
+		msg.Die("❌ Failed to read %s: %s", consts.FilePackageLock, err)
+	}
+	if len(lock.Installed) == 0 {
+		msg.Warn("⚠️ No installed dependencies in %s. Run 'boss install' first.", consts.FilePackageLock)
+	}
+
+	report := audit.Audit(lock, advisories)
+	writeAuditReport(report, format, options.output)
+
+	if failing := report.Failing(threshold); len(failing) > 0 {
+		if format != auditFormatText || options.output != "" {
+			msg.Err("❌ %d vulnerability(ies) at or above %s severity", len(failing), threshold)
+		}
+		os.Exit(1)
+	}
+}
+
+// writeAuditReport renders the report in the requested format to stdout or to a file.
+func writeAuditReport(report *audit.Report, format, output string) {
+	var out io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output) // #nosec G304 -- Writing the report path chosen by the user
+		if err != nil {
+			msg.Die("❌ Failed to create %s: %s", output, err)
+		}
+		defer func() { _ = file.Close() }()
+		out = file
+	}
+
+	var err error
+	switch format {
+	case auditFormatJSON:
+		err = audit.WriteJSON(out, report)
+	case auditFormatSarif:
+		err = audit.WriteSARIF(out, report, consts.FilePackageLock)
+	default:
+		printAuditText(out, report)
+	}
+	if err != nil {
+		msg.Die("❌ Failed to write the audit report: %s", err)
+	}
+	if output != "" {
+		msg.Info("📄 Audit report written to %s", output)
+	}
+}
+
+// printAuditText prints a human-readable summary of the report.
+func printAuditText(out io.Writer, report *audit.Report) {
+	_, _ = fmt.Fprintf(out, "🔍 Audited %d dependency(ies) against %d advisory(ies)\n",
+		report.Dependencies, report.Advisories)
+
+	for _, finding := range report.Findings {
+		severity := strings.ToUpper(string(finding.Severity))
+		if finding.Score > 0 {
+			severity = fmt.Sprintf("%s %.1f", severity, finding.Score)
+		}
+		_, _ = fmt.Fprintf(out, "  [%s] %s\n", severity, audit.FindingMessage(finding))
+	}
+
+	for _, unchecked := range report.Unchecked {
+		_, _ = fmt.Fprintf(out, "  ⚠️ %s is not locked to a semantic version; only exact version lists were checked\n",
+			unchecked)
+	}
+
+	if len(report.Findings) == 0 {
+		_, _ = fmt.Fprintln(out, "✅ No known vulnerabilities found")
+		return
+	}
+	_, _ = fmt.Fprintf(out, "❌ %d vulnerability(ies) found\n", len(report.Findings))
+}
+
+// runAuditUpdate refreshes the advisory database from the given or configured source.
+func runAuditUpdate(source, dbDir string) {
+	if source == "" {
+		source = env.GlobalConfiguration().AdvisorySource
+	}
+	if source == "" {
+		msg.Die("❌ No advisory source. Pass --source or run 'boss config audit source <url|dir>'.")
+	}
+
+	dir := auditDatabaseDir(dbDir)
+	msg.Info("⬇️ Fetching advisories from %s", source)
+	count, err := audit.Refresh(source, dir)
+	if err != nil {
+		msg.Die("❌ Failed to refresh the advisory database: %s", err)
+	}
+	msg.Success("✅ Stored %d advisory(ies) in %s", count, dir)
+}
+
+// auditDatabaseDir resolves the advisory database directory.
+func auditDatabaseDir(override string) string {
+	if override != "" {
+		return override
+	}
+	return env.GetAdvisoryDir()
+}
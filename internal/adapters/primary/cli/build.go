@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"os"
+	"strings"
+
+	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/adapters/secondary/repository"
+	"github.com/hashload/boss/internal/core/services/compiler"
+	lockService "github.com/hashload/boss/internal/core/services/lock"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/hashload/boss/pkg/pkgmanager"
+	"github.com/spf13/cobra"
+)
+
+// buildCmdRegister registers the build command.
+func buildCmdRegister(root *cobra.Command) {
+	var options compiler.BuildOptions
+
+	buildCmd := &cobra.Command{
+		Use:   cmdNameBuild + " [module...]",
+		Short: "Compile installed dependencies without reinstalling them",
+		Long: `Compile the installed dependencies in build order, the same way boss install does after
+installing them. By default only the modules changed since the last build are compiled, together with
+the modules that use them; name modules to build just those, or pass --all to rebuild everything.
+Independent modules are compiled in parallel, and modules already built are restored from the build
+cache. The command exits with status 1 when a project fails to build.`,
+		Example: `  Build the modules changed since the last build:
+  boss build
+
+  Rebuild every module and then the root projects:
+  boss build --all --root
+
+  Build selected modules for Win64:
+  boss build horse hashload/jhonson --platform=Win64
+
+  Build the changed modules in Release:
+  boss build --config=Release
+
+  Build every module for Win32 and Win64:
+  boss build --all --platform=Win32,Win64
+
+  Rebuild everything and report the compiler messages as SARIF:
+  boss build --all --diagnostics-sarif=build.sarif`,
+		Run: func(_ *cobra.Command, args []string) {
+			if options.All && len(args) > 0 {
+				msg.Die("❌ --all cannot be combined with module names")
+			}
+			options.Modules = args
+			runBuild(options)
+		},
+	}
+
+	buildCmd.Flags().BoolVar(&options.All, "all", false, "build every module, not only the changed ones")
+	buildCmd.Flags().BoolVar(&options.Root, "root", false, "also build the projects of the root boss.json afterwards")
+	buildCmd.Flags().StringVar(&options.Compiler, "compiler", "", "compiler version to use")
+	buildCmd.Flags().StringVar(&options.Platform, "platform", "",
+		"platform to use (e.g., Win32, Win64); several, separated by commas, build every module once per platform")
+	buildCmd.Flags().BoolVar(&options.Matrix, "matrix", false,
+		"build every module once per platform of engines.platforms")
+	buildCmd.Flags().StringVar(&options.Config, "config", "",
+		"build configuration to use (default: toolchain.config, then Debug); each keeps its own artifacts")
+	buildCmd.Flags().IntVarP(&options.Jobs, "jobs", "j", 0, "dependencies compiled in parallel (default: one per CPU)")
+	buildCmd.Flags().BoolVar(&options.NoCache, "no-cache", false,
+		"compile every module instead of restoring it from the build cache (see boss config build-cache)")
+	buildCmd.Flags().StringVar(&options.DiagnosticsJSON, "diagnostics-json", "",
+		"write the errors, warnings and hints of the compilers to a JSON file")
+	buildCmd.Flags().StringVar(&options.DiagnosticsSARIF, "diagnostics-sarif", "",
+		"write the errors, warnings and hints of the compilers to a SARIF file")
+	buildCmd.Flags().StringVar(&options.Profile, "profile", "",
+		"write the time spent on each step as a Chrome trace, for chrome://tracing or Perfetto")
+	root.AddCommand(buildCmd)
+}
+
+// runBuild compiles the selected modules and records the outcome in the lock.
+func runBuild(options compiler.BuildOptions) {
+	pkg, err := pkgmanager.LoadPackage()
+	if err != nil {
+		msg.Die("❌ Failed to load %s: %s", consts.FilePackage, err)
+	}
+
+	failed, err := compiler.Run(pkg, options)
+	if err != nil {
+		msg.Die("❌ %s", err)
+	}
+
+	fs := filesystem.NewOSFileSystem()
+	lockSvc := lockService.NewLockService(repository.NewFileLockRepository(fs), fs)
+	if err = lockSvc.Save(&pkg.Lock, env.GetCurrentDir()); err != nil {
+		msg.Warn("⚠️ Failed to save lock file: %v", err)
+	}
+
+	if len(failed) > 0 {
+		msg.Err("❌ Failed to build %d module(s) or project(s): %s", len(failed), strings.Join(failed, ", "))
+		os.Exit(1)
+	}
+}
@@ -118,8 +118,12 @@ func TestCommandHelp(t *testing.T) {
 	// Register all commands
 	versionCmdRegister(root)
 	installCmdRegister(root)
+	buildCmdRegister(root)
 	pubpascalCmdRegister(root)
 	craCmdRegister(root)
+	auditCmdRegister(root)
+	licensesCmdRegister(root)
+	verifyCmdRegister(root)
 
 	for _, cmd := range root.Commands() {
 		t.Run(cmd.Use, func(t *testing.T) {
@@ -133,6 +137,53 @@ func TestCommandHelp(t *testing.T) {
 	}
 }
 
+// TestAuditCommand tests the audit command registration.
+func TestAuditCommand(t *testing.T) {
+	root := &cobra.Command{Use: "boss"}
+	auditCmdRegister(root)
+
+	auditCmd := findCommand(root, cmdNameAudit)
+	if auditCmd == nil {
+		t.Fatal("Audit command not found")
+	}
+	assertSubcommands(t, auditCmd, "audit", []string{"update"})
+
+	for _, flag := range []string{"format", "output", "fail-on", "db"} {
+		if auditCmd.Flags().Lookup(flag) == nil && auditCmd.PersistentFlags().Lookup(flag) == nil {
+			t.Errorf("Audit command should have --%s flag", flag)
+		}
+	}
+	if got := auditCmd.Flags().Lookup("fail-on").DefValue; got != "low" {
+		t.Errorf("--fail-on default = %q, want low", got)
+	}
+}
+
+// TestCraVexCommand tests the cra vex command registration.
+func TestCraVexCommand(t *testing.T) {
+	root := &cobra.Command{Use: "boss"}
+	craCmdRegister(root)
+
+	craCmd := findCommand(root, cmdNameCRA)
+	if craCmd == nil {
+		t.Fatal("CRA command not found")
+	}
+	vexCmd := findCommand(craCmd, "vex")
+	if vexCmd == nil {
+		t.Fatal("CRA subcommand 'vex' not found")
+	}
+	assertSubcommands(t, vexCmd, "vex", []string{"set", "remove", "export"})
+
+	setCmd := findCommand(vexCmd, "set")
+	for _, flag := range []string{"status", "justification", "impact", "action"} {
+		if setCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("vex set should have --%s flag", flag)
+		}
+	}
+	if got := findCommand(vexCmd, "export").Flags().Lookup("format").DefValue; got != vexFormatCycloneDx {
+		t.Errorf("vex export --format default = %q, want %s", got, vexFormatCycloneDx)
+	}
+}
+
 // TestCommandOutput captures command output for testing.
 func captureOutput(cmd *cobra.Command, args []string) (string, error) {
 	buf := new(bytes.Buffer)
@@ -210,10 +261,91 @@ func TestPubPascalCommands(t *testing.T) {
 	if pkgCmd == nil {
 		t.Fatal("Pkg command not found")
 	}
-	if findCommand(root, "sbom") == nil {
-		t.Error("Root command 'sbom' not found")
+	sbomCmd := findCommand(root, "sbom")
+	if sbomCmd == nil {
+		t.Fatal("Root command 'sbom' not found")
 	}
+	assertSubcommands(t, sbomCmd, "Sbom", []string{"verify", "diff"})
 
 	// Check pkg subcommands
 	assertSubcommands(t, pkgCmd, "Pkg", []string{"spec"})
 }
+
+// TestBuildCommand tests the build command registration.
+func TestBuildCommand(t *testing.T) {
+	root := &cobra.Command{Use: "boss"}
+	buildCmdRegister(root)
+
+	buildCmd := findCommand(root, cmdNameBuild)
+	if buildCmd == nil {
+		t.Fatal("Build command not found")
+	}
+	for _, flag := range []string{"all", "root", "compiler", "platform", "profile"} {
+		if buildCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Build command should have --%s flag", flag)
+		}
+	}
+}
+
+// TestPackCommand tests the pack command registration.
+func TestPackCommand(t *testing.T) {
+	root := &cobra.Command{Use: "boss"}
+	packCmdRegister(root)
+
+	packCmd := findCommand(root, cmdNamePack)
+	if packCmd == nil {
+		t.Fatal("Pack command not found")
+	}
+	for _, flag := range []string{"binary", "no-build", "output", "compiler", "platform", "config"} {
+		if packCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Pack command should have --%s flag", flag)
+		}
+	}
+}
+
+// TestLogsCommand tests the logs command registration.
+func TestLogsCommand(t *testing.T) {
+	root := &cobra.Command{Use: "boss"}
+	logsCmdRegister(root)
+
+	logsCmd := findCommand(root, cmdNameLogs)
+	if logsCmd == nil {
+		t.Fatal("Logs command not found")
+	}
+	for _, flag := range []string{"run", "json", "prune", "clear"} {
+		if logsCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Logs command should have --%s flag", flag)
+		}
+	}
+}
+
+// TestGraphCommand tests the graph command registration.
+func TestGraphCommand(t *testing.T) {
+	root := &cobra.Command{Use: "boss"}
+	graphCmdRegister(root)
+
+	graphCmd := findCommand(root, cmdNameGraph)
+	if graphCmd == nil {
+		t.Fatal("Graph command not found")
+	}
+	for _, flag := range []string{"format", "output", "reverse", "depth"} {
+		if graphCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Graph command should have --%s flag", flag)
+		}
+	}
+}
+
+func TestDependenciesCommand(t *testing.T) {
+	root := &cobra.Command{Use: "boss"}
+	dependenciesCmdRegister(root)
+
+	dependenciesCmd := findCommand(root, "dependencies")
+	if dependenciesCmd == nil {
+		t.Fatal("Dependencies command not found")
+	}
+	for _, flag := range []string{flagNameVersion, "depth", flagNameJSON, "reverse", "flat", "no-fetch"} {
+		if dependenciesCmd.Flags().Lookup(flag) == nil {
+			t.Errorf("Dependencies command should have --%s flag", flag)
+		}
+	}
+}
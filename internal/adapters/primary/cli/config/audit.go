@@ -0,0 +1,43 @@
+package config
+
+import (
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/spf13/cobra"
+)
+
+// registryAuditCmd registers the audit configuration command.
+func registryAuditCmd(root *cobra.Command) {
+	auditCmd := &cobra.Command{
+		Use:     "audit",
+		Short:   "Configure the vulnerability audit",
+		Example: "boss config audit source https://example.com/osv/all.zip",
+	}
+
+	sourceCmd := &cobra.Command{
+		Use:   "source [url|dir]",
+		Short: "Configure where 'boss audit update' fetches advisories from",
+		Long: "Set the OSV advisory source used by 'boss audit update': an http(s) URL serving " +
+			"a JSON document or a zip archive of advisories, or a local directory or file.\n" +
+			"Run without arguments to print the current source.",
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			config := env.GlobalConfiguration()
+			if len(args) == 0 {
+				if config.AdvisorySource == "" {
+					msg.Info("No advisory source configured")
+					return
+				}
+				msg.Info("Current: %s", config.AdvisorySource)
+				return
+			}
+
+			config.AdvisorySource = args[0]
+			msg.Info("Advisory source set to %s", args[0])
+			config.SaveConfiguration()
+		},
+	}
+
+	root.AddCommand(auditCmd)
+	auditCmd.AddCommand(sourceCmd)
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/spf13/cobra"
+)
+
+// registryBuildCacheCmd registers the build cache configuration command.
+func registryBuildCacheCmd(root *cobra.Command) {
+	buildCacheCmd := &cobra.Command{
+		Use:   "build-cache",
+		Short: "Configure the cache of compiled dependencies",
+		Long: "Compiled dependencies are cached by their sources, compiler, platform, configuration and the " +
+			"artifacts of their own dependencies, and restored by 'boss build' and 'boss install' instead of " +
+			"being compiled again. Run without a subcommand to print the current settings.",
+		Example: "  boss config build-cache remote https://cache.example.com/boss --push",
+		Run: func(_ *cobra.Command, _ []string) {
+			config := env.GlobalConfiguration().GetBuildCache()
+			state := "enabled"
+			if config.Disabled {
+				state = "disabled"
+			}
+			msg.Info("Build cache: %s", state)
+			msg.Info("Directory: %s", env.GetBuildCacheDir())
+			if config.URL == "" {
+				msg.Info("Remote: none")
+				return
+			}
+			msg.Info("Remote: %s (push: %t)", config.URL, config.Push)
+		},
+	}
+
+	buildCacheCmd.AddCommand(
+		buildCacheRemoteCmd(),
+		buildCacheToggleCmd("enable", "Use the build cache", false),
+		buildCacheToggleCmd("disable", "Always compile dependencies", true),
+		&cobra.Command{
+			Use:     "rm",
+			Short:   "Remove the local build cache",
+			Aliases: []string{"purge", "clean"},
+			Args:    cobra.NoArgs,
+			RunE: func(_ *cobra.Command, _ []string) error {
+				if err := os.RemoveAll(env.GetBuildCacheDir()); err != nil {
+					return err
+				}
+				msg.Info("Removed %s", env.GetBuildCacheDir())
+				return nil
+			},
+		},
+	)
+	root.AddCommand(buildCacheCmd)
+}
+
+func buildCacheRemoteCmd() *cobra.Command {
+	var push, unset bool
+
+	remoteCmd := &cobra.Command{
+		Use:   "remote [url]",
+		Short: "Configure the shared HTTP build cache",
+		Long: "Set the URL of a shared build cache. Entries are read with GET <url>/<key>.zip, so any static " +
+			"file server can serve them; with --push the dependencies compiled locally are uploaded with PUT.\n" +
+			"Credentials can be given in the URL. Run without arguments to print the current remote.",
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			config := env.GlobalConfiguration()
+			buildCache := config.GetBuildCache()
+			switch {
+			case unset:
+				buildCache.URL, buildCache.Push = "", false
+				msg.Info("Remote build cache removed")
+			case len(args) == 0:
+				if buildCache.URL == "" {
+					msg.Info("No remote build cache configured")
+				} else {
+					msg.Info("Current: %s (push: %t)", buildCache.URL, buildCache.Push)
+				}
+				return
+			default:
+				buildCache.URL, buildCache.Push = args[0], push
+				msg.Info("Remote build cache set to %s (push: %t)", args[0], push)
+			}
+			config.SaveConfiguration()
+		},
+	}
+	remoteCmd.Flags().BoolVar(&push, "push", false, "upload the dependencies compiled locally")
+	remoteCmd.Flags().BoolVar(&unset, "unset", false, "stop using a remote build cache")
+	return remoteCmd
+}
+
+func buildCacheToggleCmd(use, short string, disabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			config := env.GlobalConfiguration()
+			config.GetBuildCache().Disabled = disabled
+			config.SaveConfiguration()
+			msg.Info("Build cache %sd", use)
+		},
+	}
+}
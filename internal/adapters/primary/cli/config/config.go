@@ -17,5 +17,9 @@ func RegisterConfigCommand(root *cobra.Command) {
 	root.AddCommand(configCmd)
 	delphiCmd(configCmd)
 	registryGitCmd(configCmd)
+	registryAuditCmd(configCmd)
+	registryTrustCmd(configCmd)
+	registryBuildCacheCmd(configCmd)
+	registryLogsCmd(configCmd)
 	RegisterCmd(configCmd)
 }
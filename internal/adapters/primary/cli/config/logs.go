@@ -0,0 +1,41 @@
+package config
+
+import (
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/spf13/cobra"
+)
+
+// registryLogsCmd registers the build log retention command.
+func registryLogsCmd(root *cobra.Command) {
+	var keep, maxAge int
+
+	logsCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Configure how long build logs are kept",
+		Long: "Every build keeps its logs in modules/.logs. After a build, the runs beyond the newest --keep " +
+			"and those older than --max-age days are removed; 0 restores the default. " +
+			"Run without flags to print the current settings.",
+		Example: "  boss config logs --keep 50 --max-age 90",
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			config := env.GlobalConfiguration()
+			retention := config.GetBuildLogs()
+			changed := false
+			if cmd.Flags().Changed("keep") {
+				retention.KeepRuns, changed = keep, true
+			}
+			if cmd.Flags().Changed("max-age") {
+				retention.MaxAgeDays, changed = maxAge, true
+			}
+			if changed {
+				config.SaveConfiguration()
+			}
+			msg.Info("Keep runs: %d", retention.Keep())
+			msg.Info("Max age: %d day(s)", int(retention.MaxAge().Hours()/24))
+		},
+	}
+	logsCmd.Flags().IntVar(&keep, "keep", 0, "number of runs to keep")
+	logsCmd.Flags().IntVar(&maxAge, "max-age", 0, "number of days to keep a run")
+	root.AddCommand(logsCmd)
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals // Read-only list of valid arguments
+var signaturePolicies = []string{string(env.SignatureRequire), string(env.SignatureWarn), string(env.SignatureOff)}
+
+// registryTrustCmd registers the signature trust configuration commands.
+func registryTrustCmd(root *cobra.Command) {
+	trustCmd := &cobra.Command{
+		Use:   "trust",
+		Short: "Configure the keys trusted to sign dependencies",
+		Long: "List the global signature trust rules. A rule scoped to a host (github.com) applies to every " +
+			"dependency on it; a rule scoped to a repository (github.com/hashload/horse) overrides it.\n" +
+			"Rules in the \"trust\" section of boss.json take precedence over the global ones.",
+		Example: "boss config trust add github.com ~/.ssh/allowed_signers --policy warn",
+		Args:    cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			listTrustRules(env.GlobalConfiguration().Trust)
+		},
+	}
+
+	var policy string
+	addCmd := &cobra.Command{
+		Use:   "add <host|repository> <key>",
+		Short: "Trust a key to sign the tags or commits of a host or repository",
+		Long: "Trust a key to sign the tags or commits of a host or repository. The key is an armored " +
+			"OpenPGP public key file, an SSH public key or allowed_signers file, or an inline SSH public key.",
+		Args: cobra.ExactArgs(2),
+		Run: func(_ *cobra.Command, args []string) {
+			if policy != "" && !slices.Contains(signaturePolicies, policy) {
+				msg.Die("❌ Unknown policy %q. Valid policies: %s", policy, strings.Join(signaturePolicies, ", "))
+			}
+			key := args[1]
+			// Global key paths are resolved against the Boss home, so a file
+			// named relative to the current directory is stored absolute.
+			if _, err := os.Stat(key); err == nil {
+				if abs, absErr := filepath.Abs(key); absErr == nil {
+					key = abs
+				}
+			}
+
+			config := env.GlobalConfiguration()
+			rule := trustRule(config, args[0], true)
+			if !slices.Contains(rule.Keys, key) {
+				rule.Keys = append(rule.Keys, key)
+			}
+			if policy != "" {
+				rule.Policy = env.SignaturePolicy(policy)
+			}
+			msg.Info("Trusting %s for %s", key, args[0])
+			config.SaveConfiguration()
+		},
+	}
+	addCmd.Flags().StringVar(&policy, "policy", "", "require (default), warn or off")
+
+	policyCmd := &cobra.Command{
+		Use:       "policy <host|repository> <require|warn|off>",
+		Short:     "Set what happens when a signature cannot be verified",
+		ValidArgs: signaturePolicies,
+		Args:      cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !slices.Contains(cmd.ValidArgs, args[1]) {
+				msg.Die("❌ Unknown policy %q. Valid policies: %s", args[1], strings.Join(cmd.ValidArgs, ", "))
+			}
+			config := env.GlobalConfiguration()
+			trustRule(config, args[0], true).Policy = env.SignaturePolicy(args[1])
+			msg.Info("Signature policy for %s set to %s", args[0], args[1])
+			config.SaveConfiguration()
+		},
+	}
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <host|repository>",
+		Short: "Remove the trust rule of a host or repository",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			config := env.GlobalConfiguration()
+			if trustRule(config, args[0], false) == nil {
+				msg.Die("❌ No trust rule for %s", args[0])
+			}
+			delete(trustRules(config, args[0]), args[0])
+			msg.Info("Removed the trust rule for %s", args[0])
+			config.SaveConfiguration()
+		},
+	}
+
+	root.AddCommand(trustCmd)
+	trustCmd.AddCommand(addCmd, policyCmd, removeCmd)
+}
+
+// trustRules returns the map a scope belongs to: repositories contain a slash,
+// hosts do not.
+func trustRules(config *env.Configuration, scope string) map[string]*env.TrustRule {
+	if config.Trust == nil {
+		config.Trust = &env.TrustConfig{}
+	}
+	if strings.Contains(scope, "/") {
+		if config.Trust.Dependencies == nil {
+			config.Trust.Dependencies = map[string]*env.TrustRule{}
+		}
+		return config.Trust.Dependencies
+	}
+	if config.Trust.Hosts == nil {
+		config.Trust.Hosts = map[string]*env.TrustRule{}
+	}
+	return config.Trust.Hosts
+}
+
+// trustRule returns the rule of a scope, creating it when create is set.
+func trustRule(config *env.Configuration, scope string, create bool) *env.TrustRule {
+	rules := trustRules(config, scope)
+	rule := rules[scope]
+	if rule == nil && create {
+		rule = &env.TrustRule{}
+		rules[scope] = rule
+	}
+	return rule
+}
+
+func listTrustRules(trust *env.TrustConfig) {
+	if trust == nil || len(trust.Hosts)+len(trust.Dependencies) == 0 {
+		msg.Info("No signature trust rules configured")
+		return
+	}
+	for _, rules := range []map[string]*env.TrustRule{trust.Hosts, trust.Dependencies} {
+		scopes := make([]string, 0, len(rules))
+		for scope := range rules {
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+		for _, scope := range scopes {
+			rule := rules[scope]
+			policy := rule.Policy
+			if policy == "" {
+				policy = env.SignatureRequire
+			}
+			msg.Info("%s (%s)", scope, policy)
+			for _, key := range rule.Keys {
+				msg.Info("  %s", key)
+			}
+		}
+	}
+}
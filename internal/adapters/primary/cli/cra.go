@@ -3,11 +3,15 @@ package cli
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/hashload/boss/internal/core/services/audit"
+	"github.com/hashload/boss/internal/core/services/vex"
+	"github.com/hashload/boss/pkg/consts"
 	"github.com/hashload/boss/pkg/msg"
 	"github.com/spf13/cobra"
 )
@@ -50,6 +54,7 @@ The check exits with status 1 when a required signal is missing, so it can gate
 	initCmd.Flags().StringVar(&securityEmail, "email", "", "Security contact email for reporting vulnerabilities")
 
 	craCmd.AddCommand(initCmd)
+	craVexCmdRegister(craCmd)
 	root.AddCommand(craCmd)
 }
 
@@ -96,7 +101,9 @@ func runCraCheck() {
 		msg.Warn("⚠️ boss.json: No boss.json found in current directory.")
 	}
 
-	if hasSecurity && hasSbom {
+	triaged := checkVulnerabilityTriage()
+
+	if hasSecurity && hasSbom && triaged {
 		msg.Info("\n🎉 Your local project is 100%% CRA compliant! Commit and push these files " +
 			"to GitHub to get the Gold badge in the portal.")
 
@@ -105,7 +112,8 @@ func runCraCheck() {
 
 	msg.Info("\n💡 Tips to get 100%% CRA badge:")
 	msg.Info("1. Run 'boss cra init' to let Boss generate the SECURITY.md and SBOM automatically.")
-	msg.Info("2. Commit and push the files to your repository.")
+	msg.Info("2. Record a VEX statement for each known vulnerability with 'boss cra vex set'.")
+	msg.Info("3. Commit and push the files to your repository.")
 
 	// A checker that always exits 0 cannot gate anything: a CI job would
 	// report success on a project that has neither a security policy nor an
@@ -113,6 +121,37 @@ func runCraCheck() {
 	os.Exit(1)
 }
 
+// checkVulnerabilityTriage reports the known vulnerabilities of the locked
+// dependencies that have no VEX statement. Without an advisory database there
+// is nothing to triage against, so the check passes.
+func checkVulnerabilityTriage() bool {
+	doc, err := vex.Load(consts.FileVex)
+	if err != nil {
+		msg.Warn("❌ Vulnerability triage: %s is invalid: %s", consts.FileVex, err)
+		return false
+	}
+
+	untriaged, err := untriagedFindings(doc)
+	switch {
+	case errors.Is(err, audit.ErrDatabaseMissing):
+		msg.Info("ℹ️ Vulnerability triage: No advisory database; run 'boss audit update' to check dependencies.")
+		return true
+	case err != nil:
+		msg.Warn("❌ Vulnerability triage: %s", err)
+		return false
+	case len(untriaged) == 0:
+		msg.Info("✅ Vulnerability triage: Every known vulnerability has a VEX statement in '%s'", consts.FileVex)
+		return true
+	}
+
+	msg.Warn("❌ Vulnerability triage: %d un-triaged vulnerability(ies):", len(untriaged))
+	for _, finding := range untriaged {
+		msg.Warn("   - %s in %s", finding.AdvisoryID, finding.Repository)
+	}
+	msg.Info("   -> To fix: Run 'boss cra vex set <vulnerability> <component> --status ...' for each of them.")
+	return false
+}
+
 // runCraInit runs the interactive wizard to generate compliance files.
 func runCraInit(securityEmail string) {
 	msg.Info("🚀 Cyber Resilience Act (CRA) Compliance Wizard\n")
@@ -2,8 +2,11 @@
 package cli
 
 import (
+	"cmp"
+	"maps"
 	"os"
-	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/hashload/boss/pkg/pkgmanager"
 
@@ -11,6 +14,8 @@ import (
 	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
 	"github.com/hashload/boss/internal/core/domain"
 	"github.com/hashload/boss/internal/core/services/cache"
+	"github.com/hashload/boss/internal/core/services/compiler"
+	"github.com/hashload/boss/internal/core/services/depgraph"
 	"github.com/hashload/boss/internal/core/services/installer"
 	"github.com/hashload/boss/pkg/consts"
 	"github.com/hashload/boss/pkg/env"
@@ -26,14 +31,44 @@ const (
 	outdated
 	usingBranch
 	branchOutdated
+	// unchecked is a dependency whose constraint is not valid, so newer
+	// versions cannot be looked for.
+	unchecked
 )
 
+// String returns the status as printed by 'boss dependencies --json', empty
+// for an up-to-date dependency.
+func (s dependencyStatus) String() string {
+	switch s {
+	case outdated:
+		return "outdated"
+	case usingBranch:
+		return "branch"
+	case branchOutdated:
+		return "branch-outdated"
+	case unchecked:
+		return "unchecked"
+	case updated:
+	}
+	return ""
+}
+
+// dependenciesOptions carries the flags of 'boss dependencies'.
+type dependenciesOptions struct {
+	showVersion bool
+	depth       int
+	asJSON      bool
+	reverse     string
+	flat        bool
+	noFetch     bool
+}
+
 // dependenciesCmdRegister registers the dependencies command.
 func dependenciesCmdRegister(root *cobra.Command) {
-	var showVersion bool
+	options := dependenciesOptions{}
 
 	var dependenciesCmd = &cobra.Command{
-		Use:     "dependencies",
+		Use:     "dependencies [pkg]",
 		Short:   "Print all project dependencies",
 		Long:    "Print all project dependencies with or without version control",
 		Aliases: []string{"dep", "ls", "list", "ll", "la", "dependency"},
@@ -47,19 +82,107 @@ func dependenciesCmdRegister(root *cobra.Command) {
   boss dependencies <pkg>
 
   List package dependencies with version control:
-  boss dependencies <pkg> --version`,
-		Run: func(_ *cobra.Command, _ []string) {
-			printDependencies(showVersion)
+  boss dependencies <pkg> --version
+
+  List what depends on a package, without fetching the repositories:
+  boss dependencies --reverse <pkg> --no-fetch
+
+  List every dependency once with its version, as JSON:
+  boss dependencies --flat --json`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			focus := ""
+			if len(args) == 1 {
+				focus = args[0]
+			}
+			printDependencies(focus, options)
 		},
 	}
 
 	root.AddCommand(dependenciesCmd)
-	dependenciesCmd.Flags().BoolVarP(&showVersion, flagNameVersion, "v", false, "show dependency version")
+	dependenciesCmd.Flags().BoolVarP(&options.showVersion, flagNameVersion, "v", false, "show dependency version")
+	dependenciesCmd.Flags().IntVar(&options.depth, "depth", 0, "levels of dependencies to print (default: all)")
+	dependenciesCmd.Flags().BoolVar(&options.asJSON, flagNameJSON, false, "print the dependencies as JSON")
+	dependenciesCmd.Flags().StringVar(&options.reverse, "reverse", "", "print what depends on a dependency")
+	dependenciesCmd.Flags().BoolVar(&options.flat, "flat", false, "print every dependency once, with its version")
+	dependenciesCmd.Flags().BoolVar(&options.noFetch, "no-fetch", false,
+		"check for newer versions in the local cache only, without fetching the repositories")
+}
+
+// dependencyEntry is a dependency in the output of 'boss dependencies', or
+// the root package among the dependents of a reverse tree.
+type dependencyEntry struct {
+	Repository string `json:"repository,omitempty"`
+	Name       string `json:"name"`
+	// Constraint is the version constraint of the declaration.
+	Constraint string `json:"constraint,omitempty"`
+	// Version is the locked version of a dependency, or the version of the
+	// root package.
+	Version string `json:"version,omitempty"`
+	Status  string `json:"status,omitempty"`
+	// Latest is the newest version Constraint allows, when it is outdated.
+	Latest       string             `json:"latest,omitempty"`
+	Root         bool               `json:"root,omitempty"`
+	Circular     bool               `json:"circular,omitempty"`
+	Dependencies []*dependencyEntry `json:"dependencies,omitempty"`
+	Dependents   []*dependencyEntry `json:"dependents,omitempty"`
+}
+
+// label returns the entry as printed in the tree.
+func (e *dependencyEntry) label(showVersion bool) string {
+	if e.Root {
+		return e.Name + " (root)"
+	}
+	if e.Circular {
+		return e.Name + " <- circular dependency"
+	}
+
+	output := e.Name
+	if showVersion {
+		output += "@" + e.Version
+	}
+	switch e.Status {
+	case outdated.String():
+		output += " <- outdated (" + e.Latest + ")"
+	case usingBranch.String():
+		output += " <- branch based"
+	case branchOutdated.String():
+		output += " <- branch outdated"
+	case unchecked.String():
+		output += " <- unchecked (invalid constraint)"
+	}
+	return output
+}
+
+// dependencyLister walks the dependency graph of a package, as selected by
+// depgraph. The status of every dependency is checked once, and its
+// repository fetched once.
+type dependencyLister struct {
+	pkg   *domain.Package
+	graph *depgraph.Graph
+	// root is the node of the root package.
+	root string
+	// deps holds the installed dependencies by node, with the constraint
+	// they were first declared with.
+	deps map[string]domain.Dependency
+	// next holds the edges followed from each node: to what it depends on,
+	// or from what depends on it with --reverse; the root package first,
+	// then by repository.
+	next    map[string][]depgraph.Edge
+	options dependenciesOptions
+	checked map[string]*dependencyEntry
+	fetched map[string]bool
 }
 
-// printDependencies prints the dependencies.
-func printDependencies(showVersion bool) {
-	var tree = treeprint.New()
+// printDependencies prints the dependencies of the package, or those of focus.
+func printDependencies(focus string, options dependenciesOptions) {
+	if focus != "" && options.reverse != "" {
+		msg.Die("❌ Use either a dependency or --reverse, not both")
+	}
+	if options.reverse != "" {
+		focus = options.reverse
+	}
+
 	pkg, err := pkgmanager.LoadPackage()
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -69,86 +192,211 @@ func printDependencies(showVersion bool) {
 		}
 	}
 
-	main := tree.AddBranch(pkg.Name + ":")
-	deps := pkg.GetParsedDependencies()
-	visited := make(map[string]bool)
-	visited[pkg.Name] = true
-	printDeps(nil, deps, pkg.Lock, main, showVersion, visited)
-	msg.Info(tree.String())
-}
+	lister := newDependencyLister(pkg, options)
+	start := lister.root
+	if focus != "" {
+		node, findErr := lister.graph.Find(focus)
+		if findErr != nil {
+			msg.Die("❌ %s", findErr)
+		}
+		start = node.ID
+	}
+	lister.graph, err = lister.graph.Select(depgraph.Options{
+		Focus:   focus,
+		Reverse: options.reverse != "",
+		Depth:   options.depth,
+	})
+	if err != nil {
+		msg.Die("❌ %s", err)
+	}
+	lister.next = lister.edges()
 
-// printDeps prints the dependencies recursively.
-func printDeps(dep *domain.Dependency,
-	deps []domain.Dependency,
-	lock domain.PackageLock,
-	tree treeprint.Tree,
-	showVersion bool,
-	visited map[string]bool) {
-	var localTree treeprint.Tree
+	if options.flat {
+		entries := lister.flat(start)
+		if options.asJSON {
+			printJSONPayload(entries)
+			return
+		}
+		var lines []string
+		for _, entry := range entries {
+			lines = append(lines, entry.label(true))
+		}
+		msg.Info(strings.Join(lines, "\n"))
+		return
+	}
 
-	if dep != nil {
-		localTree = printSingleDependency(dep, lock, tree, showVersion)
+	top := &dependencyEntry{Name: pkg.Name, Version: pkg.Version, Root: true}
+	if start != lister.root {
+		top = lister.entry(lister.deps[start])
+	}
+	children := lister.walk(start, map[string]bool{start: true}, 1)
+	if options.reverse != "" {
+		top.Dependents = children
 	} else {
-		localTree = tree
+		top.Dependencies = children
+	}
+	if options.asJSON {
+		printJSONPayload(top)
+		return
 	}
 
-	for _, dep := range deps {
-		name := dep.Name()
-		if visited[name] {
-			localTree.AddBranch(name + " <- circular dependency")
-			continue
-		}
+	tree := treeprint.New()
+	label := top.Name
+	if !top.Root {
+		label = top.label(options.showVersion)
+	}
+	addDependencyBranches(tree.AddBranch(label+":"), append(top.Dependencies, top.Dependents...), options.showVersion)
+	msg.Info(tree.String())
+}
 
-		// Copy visited map to avoid side effects across sibling branches
-		newVisited := make(map[string]bool)
-		for k, v := range visited {
-			newVisited[k] = v
-		}
-		newVisited[name] = true
+// newDependencyLister returns a lister of the whole dependency graph of pkg.
+func newDependencyLister(pkg *domain.Package, options dependenciesOptions) *dependencyLister {
+	loaded := compiler.LoadGraph(pkg)
+	deps := map[string]domain.Dependency{}
+	for _, node := range loaded.Nodes() {
+		deps[node.Dep.Repository] = node.Dep
+	}
+	graph := depgraph.New(pkg, loaded, func(domain.Dependency) depgraph.Status { return "" })
+	return &dependencyLister{
+		pkg:     pkg,
+		graph:   graph,
+		root:    graph.Nodes[0].ID,
+		deps:    deps,
+		options: options,
+		checked: map[string]*dependencyEntry{},
+		fetched: map[string]bool{},
+	}
+}
 
-		pkgModule, err := pkgmanager.LoadPackageOther(filepath.Join(env.GetModulesDir(), name, consts.FilePackage))
-		if err != nil {
-			printSingleDependency(&dep, lock, localTree, showVersion)
+// addDependencyBranches adds entries and the entries below them to tree.
+func addDependencyBranches(tree treeprint.Tree, entries []*dependencyEntry, showVersion bool) {
+	for _, entry := range entries {
+		branch := tree.AddBranch(entry.label(showVersion))
+		addDependencyBranches(branch, append(entry.Dependencies, entry.Dependents...), showVersion)
+	}
+}
+
+// edges returns the edges of the graph by the node they are followed from.
+func (l *dependencyLister) edges() map[string][]depgraph.Edge {
+	next := map[string][]depgraph.Edge{}
+	for _, edge := range l.graph.Edges {
+		if l.options.reverse != "" {
+			next[edge.To] = append(next[edge.To], edge)
 		} else {
-			subDeps := pkgModule.GetParsedDependencies()
-			printDeps(&dep, subDeps, lock, localTree, showVersion, newVisited)
+			next[edge.From] = append(next[edge.From], edge)
 		}
 	}
+	for _, edges := range next {
+		slices.SortFunc(edges, func(a, b depgraph.Edge) int {
+			first, second := l.far(a), l.far(b)
+			return cmp.Or(cmp.Compare(boolRank(first != l.root), boolRank(second != l.root)), cmp.Compare(first, second))
+		})
+	}
+	return next
 }
 
-// printSingleDependency prints a single dependency.
-func printSingleDependency(
-	dep *domain.Dependency,
-	lock domain.PackageLock,
-	tree treeprint.Tree,
-	showVersion bool) treeprint.Tree {
-	var output = dep.Name()
+// far returns the node an edge leads to: what depends on the other end with
+// --reverse, or what the other end depends on.
+func (l *dependencyLister) far(edge depgraph.Edge) string {
+	if l.options.reverse != "" {
+		return edge.From
+	}
+	return edge.To
+}
 
-	if showVersion {
-		output += "@"
-		output += lock.GetInstalled(*dep).Version
+func boolRank(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// walk returns the entries of the nodes below id, and of those below them
+// down to --depth. visited holds the nodes of the current path, which are
+// printed as circular instead of being walked again.
+func (l *dependencyLister) walk(id string, visited map[string]bool, depth int) []*dependencyEntry {
+	edges := l.next[id]
+	entries := make([]*dependencyEntry, 0, len(edges))
+	for _, edge := range edges {
+		child := l.far(edge)
+		entry := l.childEntry(child, edge.Constraint)
+		switch {
+		case entry.Root:
+		case visited[child]:
+			entry.Circular = true
+		case l.options.depth == 0 || depth < l.options.depth:
+			next := maps.Clone(visited)
+			next[child] = true
+			below := l.walk(child, next, depth+1)
+			if l.options.reverse != "" {
+				entry.Dependents = below
+			} else {
+				entry.Dependencies = below
+			}
+		}
+		entries = append(entries, entry)
 	}
+	return entries
+}
 
-	status, version := isOutdated(*dep, lock.GetInstalled(*dep).Version)
+// flat returns the entries of the dependencies selected from start, each
+// once, sorted by repository.
+func (l *dependencyLister) flat(start string) []*dependencyEntry {
+	var entries []*dependencyEntry
+	for _, node := range l.graph.Nodes {
+		if !node.Root && node.ID != start {
+			entries = append(entries, l.entry(l.deps[node.ID]))
+		}
+	}
+	return entries
+}
 
-	switch status {
-	case outdated:
-		output += " <- outdated (" + version + ")"
-	case usingBranch:
-		output += " <- branch based"
-	case branchOutdated:
-		output += " <- branch outdated"
-	case updated:
-		// Already up to date, no suffix needed
+// childEntry returns the entry of a node below another, with the constraint
+// of the edge between them.
+func (l *dependencyLister) childEntry(id, constraint string) *dependencyEntry {
+	if id == l.root {
+		return &dependencyEntry{Name: l.pkg.Name, Version: l.pkg.Version, Constraint: constraint, Root: true}
+	}
+	dep := l.deps[id]
+	if l.options.reverse == "" {
+		// The dependency is checked against the constraint it is declared
+		// with here.
+		dep = domain.ParseDependency(dep.Repository, constraint)
 	}
+	entry := l.entry(dep)
+	entry.Constraint = constraint
+	return entry
+}
 
-	return tree.AddBranch(output)
+// entry returns a new entry for dep with its locked version and status.
+func (l *dependencyLister) entry(dep domain.Dependency) *dependencyEntry {
+	key := dep.GetKey() + "@" + dep.GetVersion()
+	checked, ok := l.checked[key]
+	if !ok {
+		version := l.pkg.Lock.GetInstalled(dep).Version
+		fetch := !l.options.noFetch && !l.fetched[dep.GetKey()]
+		l.fetched[dep.GetKey()] = true
+		status, latest := isOutdated(dep, version, fetch)
+		checked = &dependencyEntry{
+			Repository: dep.Repository,
+			Name:       dep.Name(),
+			Version:    version,
+			Status:     status.String(),
+			Latest:     latest,
+		}
+		l.checked[key] = checked
+	}
+	entry := *checked
+	return &entry
 }
 
-// isOutdated checks if the dependency is outdated.
-func isOutdated(dependency domain.Dependency, version string) (dependencyStatus, string) {
-	if err := installer.GetDependency(dependency); err != nil { //nolint:staticcheck // TODO: migrate to DependencyManager
-		return updated, ""
+// isOutdated checks if the dependency is outdated against the versions in
+// the cache, fetched first when fetch is set.
+func isOutdated(dependency domain.Dependency, version string, fetch bool) (dependencyStatus, string) {
+	if fetch {
+		if err := installer.GetDependency(dependency); err != nil { //nolint:staticcheck // TODO: migrate to DependencyManager
+			return updated, ""
+		}
 	}
 	cacheService := cache.NewCacheService(filesystem.NewOSFileSystem())
 	info, err := cacheService.LoadRepositoryData(dependency.HashName())
@@ -160,7 +408,10 @@ func isOutdated(dependency domain.Dependency, version string) (dependencyStatus,
 	if err != nil {
 		return usingBranch, ""
 	}
-	constraint, _ := semver.NewConstraint(dependency.GetVersion())
+	constraint, err := semver.NewConstraint(dependency.GetVersion())
+	if err != nil {
+		return unchecked, ""
+	}
 	for _, value := range info.Versions {
 		version, err := semver.NewVersion(value)
 		if err == nil && version.GreaterThan(locked) && constraint.Check(version) {
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/adapters/secondary/repository"
+	"github.com/hashload/boss/internal/core/services/compiler"
+	"github.com/hashload/boss/internal/core/services/depgraph"
+	lockService "github.com/hashload/boss/internal/core/services/lock"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/hashload/boss/pkg/pkgmanager"
+	"github.com/spf13/cobra"
+)
+
+// graphFormatDOT, graphFormatMermaid and graphFormatJSON are the values
+// accepted by 'boss graph --format'.
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+	graphFormatJSON    = "json"
+)
+
+// graphOptions carries the flags of 'boss graph'.
+type graphOptions struct {
+	format string
+	output string
+	depgraph.Options
+}
+
+// graphCmdRegister registers the graph command.
+func graphCmdRegister(root *cobra.Command) {
+	options := graphOptions{}
+
+	graphCmd := &cobra.Command{
+		Use:   cmdNameGraph + " [dependency]",
+		Short: "Export the dependency graph",
+		Long: `Export the graph of the installed dependencies, as their boss.json files declare it, for documentation
+and code reviews. Nodes carry the version locked in boss-lock.json and their status: changed when the next
+install or build updates or compiles them again, failed when their latest logged build failed, missing when
+they are not installed. Edges carry the version constraint of the declaration.
+With a dependency (its repository, a suffix of it or its module folder), export only it and what it depends
+on, or what depends on it with --reverse.`,
+		Example: `  Render the graph with Graphviz:
+  boss graph | dot -Tsvg -o dependencies.svg
+
+  Embed the graph in a Markdown document:
+  boss graph --format mermaid --output docs/dependencies.mmd
+
+  Show what depends on horse, two levels up:
+  boss graph horse --reverse --depth 2`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			if len(args) == 1 {
+				options.Focus = args[0]
+			}
+			runGraph(options)
+		},
+	}
+
+	graphCmd.Flags().StringVar(&options.format, "format", graphFormatDOT,
+		fmt.Sprintf("Output format (%s, %s or %s)", graphFormatDOT, graphFormatMermaid, graphFormatJSON))
+	graphCmd.Flags().StringVarP(&options.output, "output", "o", "", "Write the graph to a file instead of stdout")
+	graphCmd.Flags().BoolVar(&options.Reverse, "reverse", false,
+		"Export what depends on the dependency instead of what it depends on")
+	graphCmd.Flags().IntVar(&options.Depth, "depth", 0, "Levels of dependencies to export (default: all)")
+	root.AddCommand(graphCmd)
+}
+
+// runGraph exports the dependency graph of the current project.
+func runGraph(options graphOptions) {
+	format := strings.ToLower(strings.TrimSpace(options.format))
+	if format != graphFormatDOT && format != graphFormatMermaid && format != graphFormatJSON {
+		msg.Die("❌ Unsupported graph format %q. Supported formats: %s, %s, %s.",
+			options.format, graphFormatDOT, graphFormatMermaid, graphFormatJSON)
+	}
+
+	pkg, err := pkgmanager.LoadPackage()
+	if err != nil {
+		msg.Die("❌ Failed to load %s: %s", consts.FilePackage, err)
+	}
+
+	failed, err := depgraph.FailedBuilds(env.GetBuildLogsDir())
+	if err != nil {
+		msg.Warn("⚠️ Failed to read build logs: %s", err)
+	}
+	fs := filesystem.NewOSFileSystem()
+	lockSvc := lockService.NewLockService(repository.NewFileLockRepository(fs), fs)
+	status := depgraph.LockStatus(&pkg.Lock, lockSvc, env.GetModulesDir(), failed)
+
+	graph, err := depgraph.New(pkg, compiler.LoadGraph(pkg), status).Select(options.Options)
+	if err != nil {
+		msg.Die("❌ %s", err)
+	}
+	writeGraph(graph, format, options.output)
+}
+
+// writeGraph renders the graph in the requested format to stdout or to a file.
+func writeGraph(graph *depgraph.Graph, format, output string) {
+	var out io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output) // #nosec G304 -- Writing the graph path chosen by the user
+		if err != nil {
+			msg.Die("❌ Failed to create %s: %s", output, err)
+		}
+		defer func() { _ = file.Close() }()
+		out = file
+	}
+
+	var err error
+	switch format {
+	case graphFormatMermaid:
+		err = depgraph.WriteMermaid(out, graph)
+	case graphFormatJSON:
+		err = depgraph.WriteJSON(out, graph)
+	default:
+		err = depgraph.WriteDOT(out, graph)
+	}
+	if err != nil {
+		msg.Die("❌ Failed to write the graph: %s", err)
+	}
+	if output != "" {
+		msg.Info("📄 Graph written to %s", output)
+	}
+}
@@ -11,6 +11,12 @@ func installCmdRegister(root *cobra.Command) {
 	var noSaveInstall bool
 	var compilerVersion string
 	var platform string
+	var matrix bool
+	var buildConfig string
+	var jobs int
+	var noCache bool
+	var diagnosticsJSON, diagnosticsSARIF string
+	var profile string
 	var strict bool
 
 	var installCmd = &cobra.Command{
@@ -31,15 +37,31 @@ func installCmdRegister(root *cobra.Command) {
   boss install --compiler=35.0
 
   Install using a specific platform:
-  boss install --platform=Win64`,
+  boss install --platform=Win64
+
+  Build the dependencies for Win32 and Win64:
+  boss install --platform=Win32,Win64
+
+  Build the dependencies in Release:
+  boss install --config=Release
+
+  Record where the time of the install goes:
+  boss install --profile=install-trace.json`,
 		Run: func(_ *cobra.Command, args []string) {
 			installer.InstallModules(installer.InstallOptions{
-				Args:          args,
-				LockedVersion: true,
-				NoSave:        noSaveInstall,
-				Compiler:      compilerVersion,
-				Platform:      platform,
-				Strict:        strict,
+				Args:             args,
+				LockedVersion:    true,
+				NoSave:           noSaveInstall,
+				Compiler:         compilerVersion,
+				Platform:         platform,
+				Matrix:           matrix,
+				Config:           buildConfig,
+				Jobs:             jobs,
+				NoCache:          noCache,
+				DiagnosticsJSON:  diagnosticsJSON,
+				DiagnosticsSARIF: diagnosticsSARIF,
+				Profile:          profile,
+				Strict:           strict,
 			})
 		},
 	}
@@ -47,6 +69,15 @@ func installCmdRegister(root *cobra.Command) {
 	root.AddCommand(installCmd)
 	installCmd.Flags().BoolVar(&noSaveInstall, "no-save", false, "prevents saving to dependencies")
 	installCmd.Flags().StringVar(&compilerVersion, "compiler", "", "compiler version to use")
-	installCmd.Flags().StringVar(&platform, "platform", "", "platform to use (e.g., Win32, Win64)")
-	installCmd.Flags().BoolVar(&strict, "strict", false, "strict mode for compiler selection")
+	installCmd.Flags().StringVar(&platform, "platform", "", "platform to use (e.g., Win32, Win64 or Win32,Win64)")
+	installCmd.Flags().BoolVar(&matrix, "matrix", false, "build every platform of engines.platforms")
+	installCmd.Flags().StringVar(&buildConfig, "config", "", "build configuration to use (e.g., Debug, Release)")
+	installCmd.Flags().IntVarP(&jobs, "jobs", "j", 0, "dependencies compiled in parallel (default: one per CPU)")
+	installCmd.Flags().BoolVar(&noCache, "no-cache", false, "compile every dependency instead of using the build cache")
+	installCmd.Flags().StringVar(&diagnosticsJSON, "diagnostics-json", "", "write the compiler diagnostics to a JSON file")
+	installCmd.Flags().StringVar(&diagnosticsSARIF, "diagnostics-sarif", "",
+		"write the compiler diagnostics to a SARIF file")
+	installCmd.Flags().StringVar(&profile, "profile", "", "write a Chrome trace of the install to a file")
+	installCmd.Flags().BoolVar(&strict, "strict", false,
+		"strict mode for compiler selection, platforms and unit name collisions")
 }
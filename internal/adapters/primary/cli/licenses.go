@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/adapters/secondary/repository"
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/licenses"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/hashload/boss/pkg/pkgmanager"
+	"github.com/spf13/cobra"
+)
+
+// licenseEntry is one row of the 'boss licenses' report.
+type licenseEntry struct {
+	Repository string          `json:"repository"`
+	Version    string          `json:"version"`
+	License    string          `json:"license"`
+	Source     string          `json:"source,omitempty"`
+	Status     licenses.Status `json:"status"`
+}
+
+// licensesCmdRegister registers the licenses command.
+func licensesCmdRegister(root *cobra.Command) {
+	var asJSON bool
+
+	licensesCmd := &cobra.Command{
+		Use:   cmdNameLicenses,
+		Short: "List the licenses of installed dependencies and check the license policy",
+		Long: `List the license of every dependency in boss-lock.json, taken from the "license" field of its
+boss.json (an SPDX expression) or identified from its LICENSE/COPYING file.
+The result is checked against the "licensePolicy" allow/deny lists of the project's boss.json, and the
+command exits with status 1 when a dependency breaks the policy.`,
+		Example: `  List dependency licenses:
+  boss licenses
+
+  Print the inventory as JSON:
+  boss licenses --json`,
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			runLicenses(asJSON)
+		},
+	}
+
+	licensesCmd.Flags().BoolVar(&asJSON, flagNameJSON, false, "Print the inventory as JSON")
+	root.AddCommand(licensesCmd)
+}
+
+// runLicenses prints the license inventory and fails on policy violations.
+func runLicenses(asJSON bool) {
+	pkg, err := pkgmanager.LoadPackage()
+	if err != nil {
+		msg.Die("❌ Failed to load %s: %s", consts.FilePackage, err)
+	}
+	policy := pkg.Licenses
+
+	entries := collectLicenseEntries(policy)
+
+	if asJSON {
+		printJSONPayload(entries)
+	} else {
+		printLicenseEntries(entries)
+	}
+
+	violations := 0
+	for _, entry := range entries {
+		if entry.Status.Violation(policy) {
+			violations++
+		}
+	}
+	if violations > 0 {
+		msg.Err("❌ %d dependency(ies) break the license policy", violations)
+		os.Exit(1)
+	}
+}
+
+// collectLicenseEntries detects the license of every locked dependency.
+func collectLicenseEntries(policy *domain.PackageLicensePolicy) []licenseEntry {
+	lock, err := repository.NewFileLockRepository(filesystem.NewOSFileSystem()).Load(consts.FilePackageLock)
+	if err != nil {
+		msg.Die("❌ Failed to read %s: %s", consts.FilePackageLock, err)
+	}
+
+	entries := make([]licenseEntry, 0, len(lock.Installed))
+	for key, locked := range lock.Installed {
+		info := detectModuleLicense(key)
+		entries = append(entries, licenseEntry{
+			Repository: key,
+			Version:    locked.Version,
+			License:    info.Expression,
+			Source:     info.Source,
+			Status:     licenses.Check(info.Expression, policy),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Repository < entries[j].Repository
+	})
+	return entries
+}
+
+// detectModuleLicense detects the license of an installed dependency.
+func detectModuleLicense(repo string) licenses.Info {
+	return licenses.Detect(installedModuleDir(repo))
+}
+
+// installedModuleDir returns the modules folder a dependency is installed in.
+func installedModuleDir(repo string) string {
+	dep := domain.ParseDependency(repo, "")
+	return licenses.ModuleDir(env.GetModulesDir(), dep.Name())
+}
+
+// printLicenseEntries prints the inventory as an aligned table.
+func printLicenseEntries(entries []licenseEntry) {
+	if len(entries) == 0 {
+		msg.Info("No installed dependencies in %s. Run 'boss install' first.", consts.FilePackageLock)
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "DEPENDENCY\tVERSION\tLICENSE\tSOURCE\tPOLICY")
+	for _, entry := range entries {
+		license := entry.License
+		if license == "" {
+			license = "-"
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			entry.Repository, entry.Version, license, entry.Source, strings.ToUpper(string(entry.Status)))
+	}
+	_ = writer.Flush()
+}
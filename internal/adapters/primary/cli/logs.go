@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hashload/boss/internal/core/services/buildlog"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/spf13/cobra"
+)
+
+// logsCmdRegister registers the logs command.
+func logsCmdRegister(root *cobra.Command) {
+	var runID string
+	var asJSON, prune, clearAll bool
+
+	logsCmd := &cobra.Command{
+		Use:   cmdNameLogs + " [dependency]",
+		Short: "Show the logs of previous builds",
+		Long: `Every build keeps the compiler output of each project in modules/.logs, one folder per run, with
+the compiler, platform, configuration, duration and exit status of the build.
+Without arguments, list the runs, newest first. With a dependency (its repository, a suffix of it, its
+module folder or a project name), print its logs from the latest run that built it.
+Old runs are removed after each build; see "boss config logs" for the retention settings.`,
+		Example: `  List the builds:
+  boss logs
+
+  List the projects built by a run:
+  boss logs --run 20260101-120000.000
+
+  Print the logs of a dependency:
+  boss logs horse
+
+  Remove every log:
+  boss logs --clear`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			switch {
+			case clearAll:
+				clearLogs()
+			case prune:
+				pruneLogs()
+			case len(args) == 1:
+				printDependencyLogs(args[0], runID, asJSON)
+			case runID != "":
+				printRunEntries(runID, asJSON)
+			default:
+				printRuns(asJSON)
+			}
+		},
+	}
+
+	logsCmd.Flags().StringVar(&runID, "run", "", "run to show instead of the latest")
+	logsCmd.Flags().BoolVar(&asJSON, flagNameJSON, false, "print the metadata as JSON")
+	logsCmd.Flags().BoolVar(&prune, "prune", false, "remove the runs the retention settings no longer keep")
+	logsCmd.Flags().BoolVar(&clearAll, "clear", false, "remove the logs of every run")
+	root.AddCommand(logsCmd)
+}
+
+// printRuns lists the logged runs, newest first.
+func printRuns(asJSON bool) {
+	runs, err := buildlog.Runs(env.GetBuildLogsDir())
+	if err != nil {
+		msg.Die("❌ Failed to read build logs: %s", err)
+	}
+	if asJSON {
+		printJSONPayload(runs)
+		return
+	}
+	if len(runs) == 0 {
+		msg.Info("No build logs. Run 'boss build' first.")
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "RUN\tSTARTED\tCOMPILER\tPLATFORM\tCONFIG\tPROJECTS\tFAILED\tSTATUS\tDURATION")
+	for _, run := range runs {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			run.ID, run.Started.Format(time.DateTime), orDash(run.Compiler), run.Platform, run.Config,
+			run.Projects, run.Failed, run.Status, run.Duration.Round(time.Second))
+	}
+	_ = writer.Flush()
+}
+
+// printRunEntries lists the projects built by a run.
+func printRunEntries(runID string, asJSON bool) {
+	entries, err := buildlog.Entries(env.GetBuildLogsDir(), runID)
+	if err != nil {
+		msg.Die("❌ Failed to read build logs: %s", err)
+	}
+	if asJSON {
+		printJSONPayload(entries)
+		return
+	}
+	if len(entries) == 0 {
+		msg.Die("❌ No build logs for run %s", runID)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "MODULE\tPROJECT\tSTATUS\tEXIT\tDURATION\tLOG")
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%s\t%s\n", entryModule(entry), entry.Project,
+			entry.Status, entry.ExitCode, entry.Duration.Round(time.Millisecond), entry.Log)
+	}
+	_ = writer.Flush()
+}
+
+// printDependencyLogs prints the logs of a dependency from the given run, or
+// from the latest run that built it.
+func printDependencyLogs(name, runID string, asJSON bool) {
+	logsDir := env.GetBuildLogsDir()
+	runIDs := []string{runID}
+	if runID == "" {
+		runs, err := buildlog.Runs(logsDir)
+		if err != nil {
+			msg.Die("❌ Failed to read build logs: %s", err)
+		}
+		runIDs = runIDs[:0]
+		for _, run := range runs {
+			runIDs = append(runIDs, run.ID)
+		}
+	}
+
+	for _, id := range runIDs {
+		entries, err := buildlog.Entries(logsDir, id)
+		if err != nil {
+			msg.Die("❌ Failed to read build logs: %s", err)
+		}
+		var matched []buildlog.Entry
+		for _, entry := range entries {
+			if entry.Matches(name) {
+				matched = append(matched, entry)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		if asJSON {
+			printJSONPayload(matched)
+			return
+		}
+		for _, entry := range matched {
+			printEntryLog(os.Stdout, id, entry)
+		}
+		return
+	}
+	msg.Die("❌ No build logs for %s", name)
+}
+
+// printEntryLog prints the metadata of a project build followed by its log.
+func printEntryLog(out io.Writer, runID string, entry buildlog.Entry) {
+	_, _ = fmt.Fprintf(out, "==> %s/%s (run %s)\n", entryModule(entry), entry.Project, runID)
+	_, _ = fmt.Fprintf(out, "    compiler %s, platform %s, config %s\n",
+		orDash(entry.Compiler), orDash(entry.Platform), orDash(entry.Config))
+	_, _ = fmt.Fprintf(out, "    %s, exit status %d, %s\n",
+		entry.Status, entry.ExitCode, entry.Duration.Round(time.Millisecond))
+
+	file, err := os.Open(entry.Log) // #nosec G304 -- Reading a build log written by Boss
+	if err != nil {
+		msg.Warn("⚠️ Failed to read %s: %s", entry.Log, err)
+		return
+	}
+	defer func() { _ = file.Close() }()
+	_, _ = io.Copy(out, file)
+	_, _ = fmt.Fprintln(out)
+}
+
+// pruneLogs applies the retention settings now.
+func pruneLogs() {
+	retention := env.GlobalConfiguration().GetBuildLogs()
+	removed, err := buildlog.Prune(env.GetBuildLogsDir(), retention.Keep(), retention.MaxAge())
+	if err != nil {
+		msg.Die("❌ Failed to remove build logs: %s", err)
+	}
+	msg.Success("✅ Removed %d run(s)", removed)
+}
+
+// clearLogs removes every build log.
+func clearLogs() {
+	if err := os.RemoveAll(env.GetBuildLogsDir()); err != nil {
+		msg.Die("❌ Failed to remove build logs: %s", err)
+	}
+	msg.Success("✅ Build logs removed")
+}
+
+func entryModule(entry buildlog.Entry) string {
+	if entry.Module == "" {
+		return "(root)"
+	}
+	return entry.Module
+}
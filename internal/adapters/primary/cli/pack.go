@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/adapters/secondary/repository"
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/binarypkg"
+	"github.com/hashload/boss/internal/core/services/compiler"
+	lockService "github.com/hashload/boss/internal/core/services/lock"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/hashload/boss/pkg/pkgmanager"
+	"github.com/spf13/cobra"
+)
+
+// packCmdRegister registers the pack command.
+func packCmdRegister(root *cobra.Command) {
+	var options compiler.BuildOptions
+	var binary, noBuild bool
+	var output string
+
+	packCmd := &cobra.Command{
+		Use:   cmdNamePack,
+		Short: "Package the compiled artifacts of the project",
+		Long: `Build the projects of boss.json and package their artifacts (.bpl, .dcp, .dcu and programs) as a
+binary package for one compiler version and build configuration, one archive per platform. List the
+archives in the "binaries" section of boss.json, and projects that depend on the package install these
+binaries instead of compiling it when their compiler, platform and configuration match.`,
+		Example: `  Package the Release binaries for Win64:
+  boss pack --binary --platform=Win64 --config=Release
+
+  Package one archive per platform:
+  boss pack --binary --platform=Win32,Win64
+
+  Package what the last build produced:
+  boss pack --binary --no-build --output=dist`,
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			if !binary {
+				msg.Die("❌ Only binary packages are supported, pass --binary")
+			}
+			runPackBinary(options, noBuild, output)
+		},
+	}
+
+	packCmd.Flags().BoolVar(&binary, "binary", false, "package the compiled artifacts")
+	packCmd.Flags().BoolVar(&noBuild, "no-build", false, "package the artifacts of the last build without building")
+	packCmd.Flags().StringVarP(&output, "output", "o", ".", "directory the archive is written to")
+	packCmd.Flags().StringVar(&options.Compiler, "compiler", "", "compiler version to use")
+	packCmd.Flags().StringVar(&options.Platform, "platform", "",
+		"platform to use (e.g., Win32, Win64); several, separated by commas, write one archive each")
+	packCmd.Flags().StringVar(&options.Config, "config", "", "build configuration to use (e.g., Debug, Release)")
+	root.AddCommand(packCmd)
+}
+
+// runPackBinary writes the binary package and prints the boss.json entry that
+// publishes it.
+func runPackBinary(options compiler.BuildOptions, noBuild bool, output string) {
+	pkg, err := pkgmanager.LoadPackage()
+	if err != nil {
+		msg.Die("❌ Failed to load %s: %s", consts.FilePackage, err)
+	}
+
+	packages, err := compiler.PackBinary(pkg, options, noBuild)
+	if !noBuild {
+		fs := filesystem.NewOSFileSystem()
+		lockSvc := lockService.NewLockService(repository.NewFileLockRepository(fs), fs)
+		if saveErr := lockSvc.Save(&pkg.Lock, env.GetCurrentDir()); saveErr != nil {
+			msg.Warn("⚠️ Failed to save lock file: %v", saveErr)
+		}
+	}
+	if err != nil {
+		msg.Die("❌ %s", err)
+	}
+
+	if err = os.MkdirAll(output, 0755); err != nil {
+		msg.Die("❌ Failed to create %s: %s", output, err)
+	}
+	entries := make([]domain.PackageBinary, 0, len(packages))
+	for _, binary := range packages {
+		archivePath := filepath.Join(output, binary.Manifest.FileName())
+		if err = os.WriteFile(archivePath, binary.Data, 0600); err != nil {
+			msg.Die("❌ Failed to write %s: %s", archivePath, err)
+		}
+		msg.Success("✅ Wrote %s", archivePath)
+		entries = append(entries, domain.PackageBinary{
+			Compiler: binary.Manifest.Engines.Compiler,
+			Platform: binary.Manifest.Platform(),
+			Config:   binary.Manifest.Config,
+			URL:      filepath.ToSlash(archivePath),
+			SHA256:   binarypkg.Digest(binary.Data),
+		})
+	}
+
+	listing, _ := json.MarshalIndent(entries, "", "  ")
+	msg.Info("Publish them and list them in the \"binaries\" section of %s (url: an http(s) URL or a path "+
+		"relative to the package):\n%s", consts.FilePackage, listing)
+}
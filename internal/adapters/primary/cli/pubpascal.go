@@ -13,6 +13,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,6 +22,7 @@ import (
 	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
 	"github.com/hashload/boss/internal/adapters/secondary/repository"
 	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/licenses"
 	"github.com/hashload/boss/pkg/consts"
 	"github.com/hashload/boss/pkg/msg"
 	"github.com/spf13/cobra"
@@ -63,21 +65,91 @@ type bossManifest struct {
 	Version      string            `json:"version"`
 	Description  string            `json:"description"`
 	Homepage     string            `json:"homepage"`
+	License      string            `json:"license"`
 	Dependencies map[string]string `json:"dependencies"`
 }
 
-// sbomComponent is one resolved dependency, ready to be emitted in any format.
+// sbomComponent is one module of the dependency tree, ready to be emitted in any format.
 type sbomComponent struct {
 	Name string
+	// Key is the canonical "host/owner/repo" form of the module, used to match
+	// the names different boss.json files give the same repository.
+	Key string
+	// Ref identifies the component inside the document: its purl, or Key when
+	// no purl can be built. CycloneDX uses it as the bom-ref.
+	Ref string
 	// Version is the exact version from the lock file, empty when unresolved.
 	Version string
-	// Constraint is the range declared in boss.json, kept for reference.
+	// Constraint is the range declared in boss.json, kept for reference. Only
+	// direct dependencies carry one.
 	Constraint string
 	Purl       string
-	Hash       string
+	// Commit and Hash are the checked-out commit and the "sha256:" digest of
+	// the module's file manifest recorded in the lock file.
+	Commit string
+	Hash   string
+	// License is the SPDX expression detected in the installed module, empty
+	// when it is not installed or carries no recognisable license.
+	License string
 	// Resolved reports whether Version came from the lock file (an exact
 	// version) rather than from a boss.json constraint such as "^3.0.0".
 	Resolved bool
+	// Direct reports whether the project's boss.json declares the module;
+	// the others are pulled in by another module.
+	Direct bool
+	// Dir is the installed module directory, read for its own dependencies.
+	Dir string
+	// DependsOn holds the Refs of the components the module declares in its
+	// own boss.json.
+	DependsOn []string
+}
+
+// cdxProperty is a CycloneDX name/value property.
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// cdxLicenseChoice carries a license as an SPDX expression: the "license.id"
+// form is validated against the SPDX list bundled with the schema, and a
+// declared license may be a compound expression or a LicenseRef.
+type cdxLicenseChoice struct {
+	Expression string `json:"expression"`
+}
+
+// cdxComponent is a CycloneDX component.
+type cdxComponent struct {
+	BomRef      string             `json:"bom-ref,omitempty"`
+	Type        string             `json:"type"`
+	Name        string             `json:"name"`
+	Version     string             `json:"version,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Licenses    []cdxLicenseChoice `json:"licenses,omitempty"`
+	Purl        string             `json:"purl"`
+	Properties  []cdxProperty      `json:"properties,omitempty"`
+}
+
+// cdxDependency is one node of the CycloneDX dependency graph.
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// cdxMetadata describes the project the SBOM is about.
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Component cdxComponent `json:"component"`
+}
+
+// cdxDocument is a CycloneDX 1.5 JSON document.
+type cdxDocument struct {
+	BomFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
 }
 
 // PubPascalConfig represents the configuration stored in ~/.pubpascal/config.json.
@@ -281,6 +353,7 @@ func pkgCmdRegister(root *cobra.Command) {
 	sbomCmd.Flags().StringVar(&format, "format", sbomFormatCycloneDx,
 		fmt.Sprintf("SBOM format (%s or %s)", sbomFormatCycloneDx, sbomFormatSpdx))
 	sbomCmd.Flags().StringVar(&sbomOutputDir, "output", "./sbom", "Directory to write the SBOM to")
+	sbomSubCmdsRegister(sbomCmd)
 
 	var specID string
 	var specVersion string
@@ -1028,14 +1101,7 @@ func runPkgSbom(projectFile string, format string, outputDir string) {
 			format, sbomFormatCycloneDx, sbomFormatSpdx)
 	}
 
-	if projectFile == "" {
-		// Try to find a .dproj file in the current directory
-		files, err := filepath.Glob("*.dproj")
-		if err != nil || len(files) == 0 {
-			msg.Die("❌ No Delphi project (.dproj) file specified, and none found in the current directory.")
-		}
-		projectFile = files[0]
-	}
+	projectFile = findSbomProject(projectFile)
 
 	msg.Info("Generating %s SBOM for Delphi project: %s", strings.ToUpper(normalizedFormat), projectFile)
 
@@ -1046,17 +1112,11 @@ func runPkgSbom(projectFile string, format string, outputDir string) {
 		msg.Die("❌ No boss.json manifest found. Cannot generate SBOM without package manifest.")
 	}
 
-	data, err := os.ReadFile(bossManifestFile)
+	manifest, err := readBossManifest(bossManifestFile)
 	if err != nil {
 		msg.Die("❌ Failed to read boss.json: %s", err)
 	}
 
-	var manifest bossManifest
-
-	if err := json.Unmarshal(data, &manifest); err != nil {
-		msg.Die("❌ Failed to parse boss.json: %s", err)
-	}
-
 	components := resolveSbomComponents(manifest)
 
 	// Create output directory
@@ -1071,16 +1131,55 @@ func runPkgSbom(projectFile string, format string, outputDir string) {
 	} else {
 		generateCycloneDxSbom(projectName, manifest, components, outputDir)
 	}
+
+	// SPDX has no VEX profile, so its companion document is OpenVEX.
+	vexFormat := vexFormatCycloneDx
+	if normalizedFormat == sbomFormatSpdx {
+		vexFormat = vexFormatOpenVex
+	}
+	writeProjectVex(vexFormat, projectName, manifest, components, outputDir, false)
 }
 
-// resolveSbomComponents turns the declared dependencies into SBOM components,
-// preferring the exact versions recorded in the lock file. boss.json carries
-// constraints ("^3.0.0"), which are not valid component versions in CycloneDX
-// or SPDX, so the lock is the correct source -- the same reason cyclonedx-npm
-// reads package-lock.json rather than package.json.
+// findSbomProject returns the Delphi project an SBOM is generated for: the
+// given file, or the first .dproj in the current directory.
+func findSbomProject(projectFile string) string {
+	if projectFile != "" {
+		return projectFile
+	}
+	files, err := filepath.Glob("*.dproj")
+	if err != nil || len(files) == 0 {
+		msg.Die("❌ No Delphi project (.dproj) file specified, and none found in the current directory.")
+	}
+	return files[0]
+}
+
+// resolveSbomComponents builds the SBOM components of the whole dependency
+// tree: the dependencies declared in boss.json plus every module recorded in
+// the lock file, linked by the dependencies each module declares in its own
+// boss.json.
+//
+// Versions come from the lock file. boss.json carries constraints ("^3.0.0"),
+// which are not valid component versions in CycloneDX or SPDX, so the lock is
+// the correct source -- the same reason cyclonedx-npm reads package-lock.json
+// rather than package.json.
 func resolveSbomComponents(manifest bossManifest) []sbomComponent {
-	locked := loadLockedVersions()
+	locked := loadLockedModules()
 
+	components, unresolved := directSbomComponents(manifest, locked)
+	components = append(components, transitiveSbomComponents(components, locked)...)
+	linkSbomComponents(components)
+
+	if unresolved > 0 {
+		msg.Warn("  %d dependency(ies) not found in the lock file and are reported without a version. "+
+			"Run 'boss install' so the SBOM can state exact versions.", unresolved)
+	}
+
+	return components
+}
+
+// directSbomComponents resolves the dependencies declared in boss.json and
+// returns them with the number that are missing from the lock file.
+func directSbomComponents(manifest bossManifest, locked map[string]lockedModule) ([]sbomComponent, int) {
 	names := make([]string, 0, len(manifest.Dependencies))
 	for name := range manifest.Dependencies {
 		names = append(names, name)
@@ -1092,34 +1191,127 @@ func resolveSbomComponents(manifest bossManifest) []sbomComponent {
 	unresolved := 0
 	components := make([]sbomComponent, 0, len(names))
 	for _, name := range names {
-		component := sbomComponent{Name: name, Constraint: manifest.Dependencies[name]}
-		if dep, ok := locked[normalizeDepKey(name)]; ok && dep.Version != "" {
-			component.Version = dep.Version
-			component.Hash = dep.Hash
+		component := sbomComponent{Name: name, Constraint: manifest.Dependencies[name], Direct: true}
+		repository := name
+		if module, ok := locked[domain.RepositoryKey(name)]; ok && module.Version != "" {
+			component.Version = module.Version
+			component.Commit = module.Commit
+			component.Hash = module.Hash
 			component.Resolved = true
+			repository = module.Repository
 		} else {
 			// Version stays empty on purpose. A constraint is not a version,
 			// and stating one as if it were is exactly what makes an SBOM
 			// dangerous: a scanner would read it as the version in the tree.
 			unresolved++
 		}
-		component.Purl = buildPurl(name, component.Version)
-		components = append(components, component)
+		components = append(components, newSbomComponent(component, repository))
 	}
 
-	if unresolved > 0 {
-		msg.Warn("  %d dependency(ies) not found in the lock file and are reported without a version. "+
-			"Run 'boss install' so the SBOM can state exact versions.", unresolved)
+	return components, unresolved
+}
+
+// transitiveSbomComponents returns the locked modules that are not direct
+// dependencies, sorted by repository.
+func transitiveSbomComponents(direct []sbomComponent, locked map[string]lockedModule) []sbomComponent {
+	seen := make(map[string]bool, len(direct))
+	for _, component := range direct {
+		seen[component.Key] = true
+	}
+
+	keys := make([]string, 0, len(locked))
+	for key := range locked {
+		if !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	components := make([]sbomComponent, 0, len(keys))
+	for _, key := range keys {
+		module := locked[key]
+		components = append(components, newSbomComponent(sbomComponent{
+			Name:     module.Repository,
+			Version:  module.Version,
+			Commit:   module.Commit,
+			Hash:     module.Hash,
+			Resolved: module.Version != "",
+		}, module.Repository))
 	}
 
 	return components
 }
 
-// loadLockedVersions reads the lock file, keyed by normalized dependency name.
+// newSbomComponent fills in the identity, purl, module directory and license
+// of a component installed from repository.
+func newSbomComponent(component sbomComponent, repository string) sbomComponent {
+	component.Key = domain.RepositoryKey(component.Name)
+	component.Purl = buildPurl(component.Name, component.Version)
+	component.Ref = component.Purl
+	if component.Ref == "" {
+		component.Ref = component.Key
+	}
+	component.Dir = installedModuleDir(repository)
+	if info := licenses.Detect(component.Dir); info.Known() {
+		component.License = info.Expression
+	}
+	return component
+}
+
+// linkSbomComponents sets the dependency edges of every component from the
+// dependencies its installed boss.json declares. Edges to modules that are
+// not part of the tree are dropped: a document must not reference a
+// component it does not describe.
+func linkSbomComponents(components []sbomComponent) {
+	refs := make(map[string]string, len(components))
+	for _, component := range components {
+		refs[component.Key] = component.Ref
+	}
+
+	for i := range components {
+		manifest, err := readBossManifest(filepath.Join(components[i].Dir, consts.FilePackage))
+		if err != nil {
+			continue
+		}
+
+		names := make([]string, 0, len(manifest.Dependencies))
+		for name := range manifest.Dependencies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			ref, ok := refs[domain.RepositoryKey(name)]
+			if ok && ref != components[i].Ref && !slices.Contains(components[i].DependsOn, ref) {
+				components[i].DependsOn = append(components[i].DependsOn, ref)
+			}
+		}
+	}
+}
+
+// readBossManifest reads the subset of a boss.json file needed for an SBOM.
+func readBossManifest(path string) (bossManifest, error) {
+	var manifest bossManifest
+
+	data, err := os.ReadFile(path) // #nosec G304 -- Reading a project or module boss.json
+	if err != nil {
+		return manifest, err
+	}
+	err = json.Unmarshal(data, &manifest)
+	return manifest, err
+}
+
+// lockedModule is a lock file entry together with the repository it is keyed by.
+type lockedModule struct {
+	Repository string
+	domain.LockedDependency
+}
+
+// loadLockedModules reads the lock file, keyed by canonical dependency name.
 // A missing or unreadable lock is not fatal: the SBOM still gets built from
 // boss.json, with a warning emitted by the caller.
-func loadLockedVersions() map[string]domain.LockedDependency {
-	result := make(map[string]domain.LockedDependency)
+func loadLockedModules() map[string]lockedModule {
+	result := make(map[string]lockedModule)
 
 	lockRepo := repository.NewFileLockRepository(filesystem.NewOSFileSystem())
 	lock, err := lockRepo.Load(consts.FilePackageLock)
@@ -1128,7 +1320,7 @@ func loadLockedVersions() map[string]domain.LockedDependency {
 	}
 
 	for key, dep := range lock.Installed {
-		result[normalizeDepKey(key)] = dep
+		result[domain.RepositoryKey(key)] = lockedModule{Repository: key, LockedDependency: dep}
 	}
 
 	return result
@@ -1210,83 +1402,66 @@ func buildPurl(name, version string) string {
 }
 
 func generateCycloneDxSbom(projectName string, manifest bossManifest, components []sbomComponent, outputDir string) {
-	// A standard, conformant CycloneDX v1.5 JSON schema
-	// We read the fields and build the CycloneDX struct
-	type Property struct {
-		Name  string `json:"name"`
-		Value string `json:"value"`
-	}
-
-	type Component struct {
-		Type        string     `json:"type"`
-		Name        string     `json:"name"`
-		Version     string     `json:"version,omitempty"`
-		Description string     `json:"description,omitempty"`
-		Purl        string     `json:"purl"`
-		Properties  []Property `json:"properties,omitempty"`
-	}
-
-	type Metadata struct {
-		Timestamp string    `json:"timestamp"`
-		Component Component `json:"component"`
-	}
-
-	type CycloneDX struct {
-		BomFormat    string      `json:"bomFormat"`
-		SpecVersion  string      `json:"specVersion"`
-		SerialNumber string      `json:"serialNumber"`
-		Version      int         `json:"version"`
-		Metadata     Metadata    `json:"metadata"`
-		Components   []Component `json:"components"`
+	licenseChoices := func(expression string) []cdxLicenseChoice {
+		if expression == "" {
+			return nil
+		}
+		return []cdxLicenseChoice{{Expression: expression}}
 	}
 
-	mName := manifest.Name
-	if mName == "" {
-		mName = "my-delphi-app"
-	}
-	mVersion := manifest.Version
-	if mVersion == "" {
-		mVersion = defaultPackageVersion
+	mName, mVersion := sbomSubject(manifest)
+	rootPurl := buildPurl(mName, mVersion)
+	rootRef := rootPurl
+	if rootRef == "" {
+		rootRef = mName
 	}
-	mDesc := manifest.Description
 
-	cdx := CycloneDX{
+	// A standard, conformant CycloneDX v1.5 JSON document
+	cdx := cdxDocument{
 		BomFormat:    "CycloneDX",
 		SpecVersion:  "1.5",
 		SerialNumber: "urn:uuid:" + generateUUID(),
 		Version:      1,
-		Metadata: Metadata{
+		Metadata: cdxMetadata{
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
-			Component: Component{
+			Component: cdxComponent{
+				BomRef:      rootRef,
 				Type:        "application",
 				Name:        mName,
 				Version:     mVersion,
-				Description: mDesc,
-				Purl:        buildPurl(mName, mVersion),
+				Description: manifest.Description,
+				Licenses:    licenseChoices(manifest.License),
+				Purl:        rootPurl,
 			},
 		},
-		Components: []Component{},
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{},
 	}
 
+	rootDependency := cdxDependency{Ref: rootRef, DependsOn: []string{}}
 	for _, dep := range components {
-		// Deliberately no "hashes" entry: the digest boss stores in the lock is
-		// a directory-change fingerprint (utils.HashDir), not a cryptographic
-		// hash of a distributed artifact, so it must not be presented as one.
-		properties := []Property{
-			{Name: "boss:resolved", Value: strconv.FormatBool(dep.Resolved)},
-		}
-		if dep.Constraint != "" {
-			properties = append(properties, Property{Name: "boss:constraint", Value: dep.Constraint})
-		}
-
-		cdx.Components = append(cdx.Components, Component{
+		cdx.Components = append(cdx.Components, cdxComponent{
+			BomRef:     dep.Ref,
 			Type:       "library",
 			Name:       dep.Name,
 			Version:    dep.Version,
+			Licenses:   licenseChoices(dep.License),
 			Purl:       dep.Purl,
-			Properties: properties,
+			Properties: cdxProperties(dep),
 		})
+
+		// Every component gets a node, so an empty dependsOn states that the
+		// module has no dependencies rather than that they are unknown.
+		dependsOn := dep.DependsOn
+		if dependsOn == nil {
+			dependsOn = []string{}
+		}
+		cdx.Dependencies = append(cdx.Dependencies, cdxDependency{Ref: dep.Ref, DependsOn: dependsOn})
+		if dep.Direct {
+			rootDependency.DependsOn = append(rootDependency.DependsOn, dep.Ref)
+		}
 	}
+	cdx.Dependencies = append([]cdxDependency{rootDependency}, cdx.Dependencies...)
 
 	outputFile := filepath.Join(outputDir, fmt.Sprintf("%s.cdx.json", projectName))
 	data, err := json.MarshalIndent(cdx, "", "  ")
@@ -1301,16 +1476,32 @@ func generateCycloneDxSbom(projectName string, manifest bossManifest, components
 	msg.Info("  SBOM successfully generated: %s", outputFile)
 }
 
-func generateSpdxSbom(projectName string, manifest bossManifest, components []sbomComponent, outputDir string) {
-	outputFile := filepath.Join(outputDir, fmt.Sprintf("%s.spdx", projectName))
-	mName := manifest.Name
-	if mName == "" {
-		mName = "my-delphi-app"
+// cdxProperties returns the boss-specific properties of a component.
+//
+// Deliberately no "hashes" entry: boss:hash is the "sha256:" digest of the
+// file manifest boss records in the lock for the installed module checkout
+// (minus VCS metadata and build outputs), not the hash of a distributed
+// artifact, so it must not be presented as one.
+func cdxProperties(dep sbomComponent) []cdxProperty {
+	properties := []cdxProperty{
+		{Name: "boss:resolved", Value: strconv.FormatBool(dep.Resolved)},
+		{Name: "boss:direct", Value: strconv.FormatBool(dep.Direct)},
 	}
-	mVersion := manifest.Version
-	if mVersion == "" {
-		mVersion = defaultPackageVersion
+	if dep.Constraint != "" {
+		properties = append(properties, cdxProperty{Name: "boss:constraint", Value: dep.Constraint})
 	}
+	if dep.Commit != "" {
+		properties = append(properties, cdxProperty{Name: "boss:commit", Value: dep.Commit})
+	}
+	if dep.Hash != "" {
+		properties = append(properties, cdxProperty{Name: "boss:hash", Value: dep.Hash})
+	}
+	return properties
+}
+
+func generateSpdxSbom(projectName string, manifest bossManifest, components []sbomComponent, outputDir string) {
+	outputFile := filepath.Join(outputDir, fmt.Sprintf("%s.spdx", projectName))
+	mName, mVersion := sbomSubject(manifest)
 
 	// Simple SPDX format writer
 	var buf bytes.Buffer
@@ -1328,19 +1519,38 @@ func generateSpdxSbom(projectName string, manifest bossManifest, components []sb
 	buf.WriteString("PackageDownloadLocation: NOASSERTION\n")
 	buf.WriteString("FilesAnalyzed: false\n")
 	buf.WriteString("PackageLicenseConcluded: NOASSERTION\n")
-	buf.WriteString("PackageLicenseDeclared: NOASSERTION\n\n")
+	fmt.Fprintf(&buf, "PackageLicenseDeclared: %s\n", spdxLicenseValue(manifest.License))
+	buf.WriteString("Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-Root\n")
 
+	spdxIDs := make(map[string]string, len(components))
 	for i, dep := range components {
-		depRef := fmt.Sprintf("SPDXRef-Package-Dep-%d", i+1)
+		spdxIDs[dep.Ref] = fmt.Sprintf("SPDXRef-Package-Dep-%d", i+1)
+	}
+	for _, dep := range components {
+		if dep.Direct {
+			fmt.Fprintf(&buf, "Relationship: SPDXRef-Package-Root DEPENDS_ON %s\n", spdxIDs[dep.Ref])
+		}
+	}
+	buf.WriteString("\n")
+
+	for _, dep := range components {
+		depRef := spdxIDs[dep.Ref]
 		fmt.Fprintf(&buf, "PackageName: %s\n", dep.Name)
 		fmt.Fprintf(&buf, "SPDXID: %s\n", depRef)
 		fmt.Fprintf(&buf, "PackageVersion: %s\n", dep.Version)
-		buf.WriteString("PackageDownloadLocation: NOASSERTION\n")
+		fmt.Fprintf(&buf, "PackageDownloadLocation: %s\n", spdxDownloadLocation(dep))
 		buf.WriteString("FilesAnalyzed: false\n")
 		buf.WriteString("PackageLicenseConcluded: NOASSERTION\n")
-		buf.WriteString("PackageLicenseDeclared: NOASSERTION\n")
+		fmt.Fprintf(&buf, "PackageLicenseDeclared: %s\n", spdxLicenseValue(dep.License))
+		if dep.Hash != "" {
+			// Not a PackageChecksum, for the reason given in cdxProperties.
+			fmt.Fprintf(&buf, "PackageComment: <text>boss lock hash: %s</text>\n", dep.Hash)
+		}
 		fmt.Fprintf(&buf, "ExternalRef: PACKAGE-MANAGER purl %s\n", dep.Purl)
-		fmt.Fprintf(&buf, "Relationship: SPDXRef-Package-Root DEPENDS_ON %s\n\n", depRef)
+		for _, target := range dep.DependsOn {
+			fmt.Fprintf(&buf, "Relationship: %s DEPENDS_ON %s\n", depRef, spdxIDs[target])
+		}
+		buf.WriteString("\n")
 	}
 
 	if err := os.WriteFile(outputFile, buf.Bytes(), 0600); err != nil {
@@ -1350,6 +1560,38 @@ func generateSpdxSbom(projectName string, manifest bossManifest, components []sb
 	msg.Info("  SBOM successfully generated: %s", outputFile)
 }
 
+// spdxDownloadLocation pins a module to the commit recorded in the lock file,
+// in the "git+https://host/repo@commit" form of the SPDX specification.
+func spdxDownloadLocation(dep sbomComponent) string {
+	if dep.Commit == "" || dep.Key == "" {
+		return licenses.NoAssertion
+	}
+	return "git+https://" + dep.Key + "@" + dep.Commit
+}
+
+// sbomSubject returns the name and version the SBOM describes, with
+// placeholders for a boss.json that does not declare them.
+func sbomSubject(manifest bossManifest) (string, string) {
+	name := manifest.Name
+	if name == "" {
+		name = "my-delphi-app"
+	}
+	version := manifest.Version
+	if version == "" {
+		version = defaultPackageVersion
+	}
+	return name, version
+}
+
+// spdxLicenseValue returns the SPDX license field value for an expression,
+// NOASSERTION when no license is known.
+func spdxLicenseValue(expression string) string {
+	if expression == "" {
+		return licenses.NoAssertion
+	}
+	return expression
+}
+
 // generateUUID returns a random RFC 4122 version 4 UUID.
 //
 // CycloneDX constrains serialNumber to
@@ -28,6 +28,13 @@ const (
 	cmdNameWorkspace  = "workspace"
 	cmdNameContribute = "contribute"
 	cmdNameCRA        = "cra"
+	cmdNameAudit      = "audit"
+	cmdNameLicenses   = "licenses"
+	cmdNameVerify     = "verify"
+	cmdNameBuild      = "build"
+	cmdNamePack       = "pack"
+	cmdNameLogs       = "logs"
+	cmdNameGraph      = "graph"
 	cmdNameVersion    = "version"
 )
 
@@ -124,15 +131,22 @@ func registerCommands(root *cobra.Command) {
 	initCmdRegister(root)
 	newCmdRegister(root)
 	installCmdRegister(root)
+	buildCmdRegister(root)
+	packCmdRegister(root)
+	logsCmdRegister(root)
 	loginCmdRegister(root)
 	runCmdRegister(root)
 	uninstallCmdRegister(root)
 	updateCmdRegister(root)
 	upgradeCmdRegister(root)
 	dependenciesCmdRegister(root)
+	graphCmdRegister(root)
 	versionCmdRegister(root)
 	pubpascalCmdRegister(root)
 	craCmdRegister(root)
+	auditCmdRegister(root)
+	licensesCmdRegister(root)
+	verifyCmdRegister(root)
 	contributeCmdRegister(root)
 
 	// Registered before the grouping pass in applyCommandGroups: any command
@@ -153,11 +167,12 @@ func applyCommandGroups(root *cobra.Command) {
 
 	for _, cmd := range root.Commands() {
 		switch cmd.Name() {
-		case cmdNameNew, projectTypePkg, cmdNameRun:
+		case cmdNameNew, projectTypePkg, cmdNameRun, cmdNameBuild, cmdNamePack, cmdNameLogs,
+			cmdNameGraph:
 			cmd.GroupID = groupIDProject
 		case cmdNameLogin, cmdNameWorkspace, cmdNameContribute:
 			cmd.GroupID = groupIDPubPascal
-		case cmdNameCRA, sbomBaseName:
+		case cmdNameCRA, cmdNameAudit, cmdNameLicenses, sbomBaseName:
 			cmd.GroupID = groupIDCRA
 		default:
 			cmd.GroupID = groupIDLegacy
@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/spf13/cobra"
+)
+
+// Kinds of problems reported by 'boss sbom verify'.
+const (
+	sbomIssueInvalid = "invalid"
+	sbomIssueMissing = "missing"
+	sbomIssueExtra   = "extra"
+	sbomIssueVersion = "version"
+)
+
+// Kinds of changes reported by 'boss sbom diff'.
+const (
+	sbomChangeAdded      = "added"
+	sbomChangeRemoved    = "removed"
+	sbomChangeUpgraded   = "upgraded"
+	sbomChangeDowngraded = "downgraded"
+	sbomChangeChanged    = "changed"
+)
+
+// sbomIssue is one problem found by 'boss sbom verify'.
+type sbomIssue struct {
+	Kind      string `json:"kind"`
+	Component string `json:"component,omitempty"`
+	Detail    string `json:"detail"`
+}
+
+// sbomVerifyReport is the JSON output of 'boss sbom verify'.
+type sbomVerifyReport struct {
+	File       string      `json:"file"`
+	Format     string      `json:"format"`
+	Components int         `json:"components"`
+	Valid      bool        `json:"valid"`
+	Issues     []sbomIssue `json:"issues"`
+}
+
+// sbomChange is one difference found by 'boss sbom diff'.
+type sbomChange struct {
+	Change    string `json:"change"`
+	Component string `json:"component"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+}
+
+// sbomSubCmdsRegister registers the verify and diff sub-commands of 'boss sbom'.
+func sbomSubCmdsRegister(sbomCmd *cobra.Command) {
+	var verifyJSON bool
+	verifyCmd := &cobra.Command{
+		Use:   "verify <file>",
+		Short: "Check the structure of an SBOM and compare it with the lock file",
+		Long: `Check the structure of a CycloneDX 1.5 (JSON) or SPDX 2.3 (JSON or tag-value) document, then compare
+its components with boss-lock.json: modules missing from the SBOM, components that are not installed and
+version mismatches are reported. The command exits with status 1 when a problem is found.
+
+The structure is checked against partial schemas bundled with Boss, covering the components and their
+dependencies; they are not the official CycloneDX and SPDX schemas, so use a full validator for conformance.`,
+		Example: `  Check the SBOM of a release against the installed tree:
+  boss sbom verify sbom/MyApp.cdx.json`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			runSbomVerify(args[0], verifyJSON)
+		},
+	}
+	verifyCmd.Flags().BoolVar(&verifyJSON, flagNameJSON, false, "Print the result as JSON")
+
+	var diffJSON bool
+	diffCmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Show the components added, removed or upgraded between two SBOMs",
+		Long: `Compare the components of two SBOMs, for example those of two releases. Components are matched by
+repository, so documents in different formats can be compared.`,
+		Example: `  Compare two releases:
+  boss sbom diff v1.0.0.cdx.json v1.1.0.cdx.json`,
+		Args: cobra.ExactArgs(2),
+		Run: func(_ *cobra.Command, args []string) {
+			runSbomDiff(args[0], args[1], diffJSON)
+		},
+	}
+	diffCmd.Flags().BoolVar(&diffJSON, flagNameJSON, false, "Print the changes as JSON")
+
+	sbomCmd.AddCommand(verifyCmd, diffCmd)
+}
+
+// runSbomVerify validates an SBOM and compares it with the lock file.
+func runSbomVerify(path string, asJSON bool) {
+	doc, err := readSbomDocument(path)
+	if err != nil {
+		msg.Die("❌ Failed to read SBOM %s: %s", path, err)
+	}
+
+	issues := make([]sbomIssue, 0, len(doc.Problems))
+	for _, problem := range doc.Problems {
+		issues = append(issues, sbomIssue{Kind: sbomIssueInvalid, Detail: problem})
+	}
+
+	if _, statErr := os.Stat(consts.FilePackageLock); errors.Is(statErr, os.ErrNotExist) {
+		msg.Warn("⚠️ No %s found, only the schema was checked.", consts.FilePackageLock)
+	} else {
+		issues = append(issues, compareSbomWithLock(doc.Components, loadLockedModules())...)
+	}
+
+	if asJSON {
+		printJSONPayload(sbomVerifyReport{
+			File:       path,
+			Format:     doc.Format,
+			Components: len(doc.Components),
+			Valid:      len(issues) == 0,
+			Issues:     issues,
+		})
+	} else {
+		for _, issue := range issues {
+			if issue.Component != "" {
+				msg.Warn("  %s %s: %s", issue.Kind, issue.Component, issue.Detail)
+			} else {
+				msg.Warn("  %s: %s", issue.Kind, issue.Detail)
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		msg.Err("❌ %s: %d problem(s) in %d component(s)", path, len(issues), len(doc.Components))
+		os.Exit(1)
+	}
+	if !asJSON {
+		msg.Success("✅ %s is a valid %s SBOM matching %s (%d components)",
+			path, strings.ToUpper(doc.Format), consts.FilePackageLock, len(doc.Components))
+	}
+}
+
+// compareSbomWithLock reports the locked modules missing from the SBOM, the
+// components that are not locked and the versions that differ.
+func compareSbomWithLock(components []sbomComponent, locked map[string]lockedModule) []sbomIssue {
+	var issues []sbomIssue
+	seen := make(map[string]bool, len(components))
+
+	for _, component := range components {
+		seen[component.Key] = true
+		module, ok := locked[component.Key]
+		switch {
+		case !ok:
+			issues = append(issues, sbomIssue{Kind: sbomIssueExtra, Component: component.Key,
+				Detail: "not installed according to " + consts.FilePackageLock})
+		case !sameVersion(component.Version, module.Version):
+			issues = append(issues, sbomIssue{Kind: sbomIssueVersion, Component: component.Key,
+				Detail: fmt.Sprintf("SBOM states %q, lock has %q", component.Version, module.Version)})
+		}
+	}
+
+	for key, module := range locked {
+		if !seen[key] {
+			issues = append(issues, sbomIssue{Kind: sbomIssueMissing, Component: key,
+				Detail: fmt.Sprintf("%s is locked but not in the SBOM", module.Version)})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Component < issues[j].Component
+	})
+	return issues
+}
+
+// sameVersion compares versions, ignoring a leading "v" that some tools drop.
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+// runSbomDiff prints the component changes between two SBOMs.
+func runSbomDiff(oldPath, newPath string, asJSON bool) {
+	oldDoc, err := readSbomDocument(oldPath)
+	if err != nil {
+		msg.Die("❌ Failed to read SBOM %s: %s", oldPath, err)
+	}
+	newDoc, err := readSbomDocument(newPath)
+	if err != nil {
+		msg.Die("❌ Failed to read SBOM %s: %s", newPath, err)
+	}
+
+	changes := diffSbomComponents(oldDoc.Components, newDoc.Components)
+	if asJSON {
+		printJSONPayload(changes)
+		return
+	}
+
+	if len(changes) == 0 {
+		msg.Info("No component changes between %s and %s.", oldPath, newPath)
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "CHANGE\tCOMPONENT\tFROM\tTO")
+	counts := map[string]int{}
+	for _, change := range changes {
+		counts[change.Change]++
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+			strings.ToUpper(change.Change), change.Component, orDash(change.From), orDash(change.To))
+	}
+	_ = writer.Flush()
+
+	msg.Info("%d added, %d removed, %d upgraded, %d downgraded, %d changed",
+		counts[sbomChangeAdded], counts[sbomChangeRemoved], counts[sbomChangeUpgraded],
+		counts[sbomChangeDowngraded], counts[sbomChangeChanged])
+}
+
+// diffSbomComponents matches components by repository and classifies the
+// differences. A version change between two valid semantic versions is an
+// upgrade or a downgrade; any other change of version or commit is "changed".
+func diffSbomComponents(oldComponents, newComponents []sbomComponent) []sbomChange {
+	oldByKey := make(map[string]sbomComponent, len(oldComponents))
+	for _, component := range oldComponents {
+		oldByKey[component.Key] = component
+	}
+
+	changes := []sbomChange{}
+	for _, component := range newComponents {
+		previous, ok := oldByKey[component.Key]
+		delete(oldByKey, component.Key)
+		if !ok {
+			changes = append(changes, sbomChange{Change: sbomChangeAdded, Component: component.Key, To: component.Version})
+			continue
+		}
+		if change, changed := compareSbomVersions(previous, component); changed {
+			changes = append(changes, change)
+		}
+	}
+	for key, component := range oldByKey {
+		changes = append(changes, sbomChange{Change: sbomChangeRemoved, Component: key, From: component.Version})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Component < changes[j].Component
+	})
+	return changes
+}
+
+func compareSbomVersions(previous, current sbomComponent) (sbomChange, bool) {
+	change := sbomChange{Component: current.Key, From: previous.Version, To: current.Version}
+
+	if !sameVersion(previous.Version, current.Version) {
+		change.Change = sbomChangeChanged
+		oldVersion, oldErr := semver.NewVersion(previous.Version)
+		newVersion, newErr := semver.NewVersion(current.Version)
+		if oldErr == nil && newErr == nil {
+			if newVersion.GreaterThan(oldVersion) {
+				change.Change = sbomChangeUpgraded
+			} else if newVersion.LessThan(oldVersion) {
+				change.Change = sbomChangeDowngraded
+			}
+		}
+		return change, true
+	}
+
+	// Same version, different commit: the tag was moved or a branch advanced.
+	if previous.Commit != "" && current.Commit != "" && previous.Commit != current.Commit {
+		change.Change = sbomChangeChanged
+		change.From = previous.Version + "@" + shortCommit(previous.Commit)
+		change.To = current.Version + "@" + shortCommit(current.Commit)
+		return change, true
+	}
+	return change, false
+}
+
+func shortCommit(commit string) string {
+	const shortCommitLength = 12
+	if len(commit) > shortCommitLength {
+		return commit[:shortCommitLength]
+	}
+	return commit
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
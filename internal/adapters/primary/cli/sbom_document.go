@@ -0,0 +1,508 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// The bundled schemas 'boss sbom verify' validates documents against. They
+// are partial structural checks written for Boss, not the official CycloneDX
+// and SPDX schemas: a document they accept may still be invalid against those.
+const (
+	cycloneDxSchemaFile = "schema/cyclonedx-1.5-structure.schema.json"
+	spdxSchemaFile      = "schema/spdx-2.3-structure.schema.json"
+)
+
+// spdxNone and spdxNoAssertion may stand in for an element in an SPDX
+// relationship without being defined in the document.
+const (
+	spdxNone        = "NONE"
+	spdxNoAssertion = "NOASSERTION"
+)
+
+// sbomSchemas holds the bundled structural checks, so verification works offline.
+//
+//go:embed schema/*.json
+var sbomSchemas embed.FS //nolint:gochecknoglobals // go:embed requires a package-level variable
+
+// purlForgeHosts maps the purl types of Git forges to their host.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var purlForgeHosts = map[string]string{
+	"github":    defaultForgeHost,
+	"gitlab":    "gitlab.com",
+	"bitbucket": "bitbucket.org",
+}
+
+// sbomDocument is an SBOM read back from disk.
+type sbomDocument struct {
+	// Format is sbomFormatCycloneDx or sbomFormatSpdx.
+	Format     string
+	Components []sbomComponent
+	// Problems lists schema violations and references to undefined elements.
+	Problems []string
+}
+
+// spdxDocument is the JSON form of an SPDX 2.3 document, limited to the
+// fields boss reads. Tag-value documents are converted to it, so both forms
+// are validated against the same schema.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion,omitempty"`
+	DataLicense       string             `json:"dataLicense,omitempty"`
+	SPDXID            string             `json:"SPDXID,omitempty"`
+	Name              string             `json:"name,omitempty"`
+	DocumentNamespace string             `json:"documentNamespace,omitempty"`
+	DocumentDescribes []string           `json:"documentDescribes,omitempty"`
+	CreationInfo      *spdxCreationInfo  `json:"creationInfo,omitempty"`
+	Packages          []spdxPackage      `json:"packages,omitempty"`
+	Relationships     []spdxRelationship `json:"relationships,omitempty"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created,omitempty"`
+	Creators []string `json:"creators,omitempty"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name,omitempty"`
+	SPDXID           string            `json:"SPDXID,omitempty"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation,omitempty"`
+	FilesAnalyzed    *bool             `json:"filesAnalyzed,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded,omitempty"`
+	LicenseDeclared  string            `json:"licenseDeclared,omitempty"`
+	Comment          string            `json:"comment,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SpdxElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+// readSbomDocument reads a CycloneDX JSON, SPDX JSON or SPDX tag-value
+// document, validates it against the bundled schema and extracts its
+// components. An error means the file could not be read as an SBOM at all;
+// a document that parses but breaks the schema is returned with Problems.
+func readSbomDocument(path string) (*sbomDocument, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- Reading an SBOM named on the command line
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		spdx, parseErr := parseSpdxTagValue(trimmed)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		return spdxSbomDocument(spdx)
+	}
+
+	var probe struct {
+		BomFormat   string `json:"bomFormat"`
+		SPDXVersion string `json:"spdxVersion"`
+	}
+	if err = json.Unmarshal(trimmed, &probe); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	switch {
+	case probe.BomFormat != "":
+		return cycloneDxSbomDocument(trimmed)
+	case probe.SPDXVersion != "":
+		var spdx spdxDocument
+		if err = json.Unmarshal(trimmed, &spdx); err != nil {
+			return nil, fmt.Errorf("invalid SPDX document: %w", err)
+		}
+		return spdxSbomDocument(&spdx)
+	}
+	return nil, errors.New("not a CycloneDX or SPDX document: neither bomFormat nor spdxVersion is set")
+}
+
+// validateSbomSchema validates a JSON document against a bundled schema and
+// returns one line per violation.
+func validateSbomSchema(schemaFile string, data []byte) ([]string, error) {
+	schemaData, err := sbomSchemas.ReadFile(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+	schemaDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaData))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err = compiler.AddResource(schemaFile, schemaDoc); err != nil {
+		return nil, err
+	}
+	schema, err := compiler.Compile(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if err = schema.Validate(instance); !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	var problems []string
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		location := unit.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		problems = append(problems, fmt.Sprintf("schema: %s: %s", location, unit.Error))
+	}
+	return problems, nil
+}
+
+// cycloneDxSbomDocument validates and reads a CycloneDX JSON document.
+func cycloneDxSbomDocument(data []byte) (*sbomDocument, error) {
+	problems, err := validateSbomSchema(cycloneDxSchemaFile, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var cdx cdxDocument
+	if err = json.Unmarshal(data, &cdx); err != nil {
+		// The schema already reported why; keep what could be validated.
+		return &sbomDocument{Format: sbomFormatCycloneDx, Problems: problems}, nil
+	}
+
+	doc := &sbomDocument{Format: sbomFormatCycloneDx, Problems: problems}
+	refs := map[string]bool{}
+	if ref := cdx.Metadata.Component.BomRef; ref != "" {
+		refs[ref] = true
+	}
+	for _, component := range cdx.Components {
+		if component.BomRef != "" {
+			if refs[component.BomRef] {
+				doc.Problems = append(doc.Problems, fmt.Sprintf("duplicate bom-ref %q", component.BomRef))
+			}
+			refs[component.BomRef] = true
+		}
+		doc.Components = append(doc.Components, cdxSbomComponent(component))
+	}
+
+	edges := map[string][]string{}
+	for _, dependency := range cdx.Dependencies {
+		for _, ref := range append([]string{dependency.Ref}, dependency.DependsOn...) {
+			if !refs[ref] {
+				doc.Problems = append(doc.Problems, fmt.Sprintf("dependency graph references unknown bom-ref %q", ref))
+			}
+		}
+		edges[dependency.Ref] = dependency.DependsOn
+	}
+
+	direct := map[string]bool{}
+	for _, ref := range edges[cdx.Metadata.Component.BomRef] {
+		direct[ref] = true
+	}
+	for i := range doc.Components {
+		doc.Components[i].Direct = direct[doc.Components[i].Ref]
+		doc.Components[i].DependsOn = edges[doc.Components[i].Ref]
+	}
+
+	return doc, nil
+}
+
+// cdxSbomComponent converts a CycloneDX component back into the SBOM model,
+// reading the boss properties written by generateCycloneDxSbom.
+func cdxSbomComponent(component cdxComponent) sbomComponent {
+	result := sbomComponent{
+		Name:    component.Name,
+		Ref:     component.BomRef,
+		Version: component.Version,
+		Purl:    component.Purl,
+		Key:     sbomComponentKey(component.Name, component.Purl),
+	}
+	for _, property := range component.Properties {
+		switch property.Name {
+		case "boss:constraint":
+			result.Constraint = property.Value
+		case "boss:commit":
+			result.Commit = property.Value
+		case "boss:hash":
+			result.Hash = property.Value
+		case "boss:resolved":
+			result.Resolved = property.Value == "true"
+		}
+	}
+	if len(component.Licenses) > 0 {
+		result.License = component.Licenses[0].Expression
+	}
+	return result
+}
+
+// spdxSbomDocument validates and reads an SPDX document. The packages the
+// document DESCRIBES are the subject of the SBOM; every other package is a
+// component.
+func spdxSbomDocument(spdx *spdxDocument) (*sbomDocument, error) {
+	data, err := json.Marshal(spdx)
+	if err != nil {
+		return nil, err
+	}
+	problems, err := validateSbomSchema(spdxSchemaFile, data)
+	if err != nil {
+		return nil, err
+	}
+	doc := &sbomDocument{Format: sbomFormatSpdx, Problems: problems}
+
+	ids := map[string]bool{spdx.SPDXID: true}
+	for _, pkg := range spdx.Packages {
+		if ids[pkg.SPDXID] {
+			doc.Problems = append(doc.Problems, fmt.Sprintf("duplicate SPDXID %q", pkg.SPDXID))
+		}
+		ids[pkg.SPDXID] = true
+	}
+
+	roots := map[string]bool{}
+	for _, id := range spdx.DocumentDescribes {
+		roots[id] = true
+	}
+	edges := map[string][]string{}
+	for _, relationship := range spdx.Relationships {
+		for _, id := range []string{relationship.SpdxElementID, relationship.RelatedSpdxElement} {
+			if !ids[id] && id != spdxNone && id != spdxNoAssertion {
+				doc.Problems = append(doc.Problems, fmt.Sprintf("relationship references unknown SPDXID %q", id))
+			}
+		}
+		switch relationship.RelationshipType {
+		case "DESCRIBES":
+			roots[relationship.RelatedSpdxElement] = true
+		case "DESCRIBED_BY":
+			roots[relationship.SpdxElementID] = true
+		case "DEPENDS_ON":
+			edges[relationship.SpdxElementID] = append(edges[relationship.SpdxElementID], relationship.RelatedSpdxElement)
+		case "DEPENDENCY_OF":
+			edges[relationship.RelatedSpdxElement] = append(edges[relationship.RelatedSpdxElement], relationship.SpdxElementID)
+		}
+	}
+
+	direct := map[string]bool{}
+	for root := range roots {
+		for _, id := range edges[root] {
+			direct[id] = true
+		}
+	}
+	for _, pkg := range spdx.Packages {
+		if roots[pkg.SPDXID] {
+			continue
+		}
+		doc.Components = append(doc.Components, spdxSbomComponent(pkg, direct[pkg.SPDXID], edges[pkg.SPDXID]))
+	}
+
+	return doc, nil
+}
+
+func spdxSbomComponent(pkg spdxPackage, direct bool, dependsOn []string) sbomComponent {
+	component := sbomComponent{
+		Name:      pkg.Name,
+		Ref:       pkg.SPDXID,
+		Version:   pkg.VersionInfo,
+		Direct:    direct,
+		DependsOn: dependsOn,
+		License:   pkg.LicenseDeclared,
+	}
+	for _, ref := range pkg.ExternalRefs {
+		if ref.ReferenceType == "purl" {
+			component.Purl = ref.ReferenceLocator
+			break
+		}
+	}
+	if _, commit, ok := strings.Cut(pkg.DownloadLocation, "@"); ok && strings.HasPrefix(pkg.DownloadLocation, "git+") {
+		component.Commit = commit
+	}
+	component.Key = sbomComponentKey(component.Name, component.Purl)
+	return component
+}
+
+// parseSpdxTagValue converts an SPDX tag-value document into its JSON form.
+// File and snippet sections are skipped: boss documents packages only.
+func parseSpdxTagValue(data []byte) (*spdxDocument, error) {
+	doc := &spdxDocument{}
+	// pkg is the package section being read; nil while in the document
+	// header, and also inside file or snippet sections, which are skipped.
+	var pkg *spdxPackage
+	inHeader := true
+
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for lines.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tag, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"Tag: value\", got %q", lineNumber, line)
+		}
+		value = strings.TrimSpace(value)
+
+		// <text> values may span several lines.
+		if strings.HasPrefix(value, "<text>") {
+			for !strings.Contains(value, "</text>") && lines.Scan() {
+				lineNumber++
+				value += "\n" + lines.Text()
+			}
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "<text>"), "</text>")
+		}
+
+		switch tag {
+		case "PackageName":
+			doc.Packages = append(doc.Packages, spdxPackage{Name: value})
+			pkg = &doc.Packages[len(doc.Packages)-1]
+			inHeader = false
+		case "FileName", "SnippetSPDXID", "LicenseID":
+			pkg = nil
+			inHeader = false
+		case "Relationship":
+			fields := strings.Fields(value)
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("line %d: relationship must be \"<id> <type> <id>\"", lineNumber)
+			}
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SpdxElementID: fields[0], RelationshipType: fields[1], RelatedSpdxElement: fields[2],
+			})
+		default:
+			if pkg != nil {
+				setSpdxPackageTag(pkg, tag, value)
+			} else if inHeader {
+				setSpdxDocumentTag(doc, tag, value)
+			}
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+	if doc.SPDXVersion == "" && len(doc.Packages) == 0 {
+		return nil, errors.New("not a CycloneDX or SPDX document")
+	}
+	return doc, nil
+}
+
+func setSpdxDocumentTag(doc *spdxDocument, tag, value string) {
+	switch tag {
+	case "SPDXVersion":
+		doc.SPDXVersion = value
+	case "DataLicense":
+		doc.DataLicense = value
+	case "SPDXID":
+		doc.SPDXID = value
+	case "DocumentName":
+		doc.Name = value
+	case "DocumentNamespace":
+		doc.DocumentNamespace = value
+	case "Creator", "Created":
+		if doc.CreationInfo == nil {
+			doc.CreationInfo = &spdxCreationInfo{}
+		}
+		if tag == "Created" {
+			doc.CreationInfo.Created = value
+		} else {
+			doc.CreationInfo.Creators = append(doc.CreationInfo.Creators, value)
+		}
+	}
+}
+
+func setSpdxPackageTag(pkg *spdxPackage, tag, value string) {
+	switch tag {
+	case "SPDXID":
+		pkg.SPDXID = value
+	case "PackageVersion":
+		pkg.VersionInfo = value
+	case "PackageDownloadLocation":
+		pkg.DownloadLocation = value
+	case "FilesAnalyzed":
+		analyzed := strings.EqualFold(value, "true")
+		pkg.FilesAnalyzed = &analyzed
+	case "PackageLicenseConcluded":
+		pkg.LicenseConcluded = value
+	case "PackageLicenseDeclared":
+		pkg.LicenseDeclared = value
+	case "PackageComment":
+		pkg.Comment = value
+	case "ExternalRef":
+		if fields := strings.Fields(value); len(fields) == 3 {
+			pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{
+				ReferenceCategory: fields[0], ReferenceType: fields[1], ReferenceLocator: fields[2],
+			})
+		}
+	}
+}
+
+// sbomComponentKey identifies a component across documents and the lock
+// file: the repository named by its purl, or its name when the purl does
+// not name one.
+func sbomComponentKey(name, purl string) string {
+	if repo := purlRepository(purl); repo != "" {
+		return domain.RepositoryKey(repo)
+	}
+	return domain.RepositoryKey(name)
+}
+
+// purlRepository returns the repository a package URL points at: the forge
+// path of pkg:github, pkg:gitlab and pkg:bitbucket, or the vcs_url qualifier
+// of any other type (what buildPurl writes for pkg:generic).
+func purlRepository(purl string) string {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return ""
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, qualifiers, _ := strings.Cut(rest, "?")
+
+	purlType, path, _ := strings.Cut(rest, "/")
+	if at := strings.LastIndex(path, "@"); at != -1 {
+		path = path[:at]
+	}
+
+	if host, known := purlForgeHosts[strings.ToLower(purlType)]; known {
+		unescaped, err := url.PathUnescape(path)
+		if err != nil || unescaped == "" {
+			return ""
+		}
+		return host + "/" + unescaped
+	}
+
+	values, err := url.ParseQuery(qualifiers)
+	if err != nil {
+		return ""
+	}
+	vcs := values.Get("vcs_url")
+	if vcs == "" {
+		return ""
+	}
+	vcs = strings.TrimPrefix(vcs, "git+")
+	if at := strings.LastIndex(vcs, "@"); at > strings.LastIndex(vcs, "/") {
+		vcs = vcs[:at]
+	}
+	return normalizeDepKey(vcs)
+}
@@ -0,0 +1,231 @@
+//nolint:testpackage // exercises unexported SBOM helpers
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+// setupSbomProject lays out a project whose direct dependency on horse pulls
+// in jhonson, which boss.json does not mention.
+func setupSbomProject(t *testing.T) bossManifest {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	lock := `{"hash": "x", "installedModules": {
+		"hashload/horse": {"name": "hashload_horse", "version": "v3.1.0", "commit": "abc123", "hash": "h1"},
+		"github.com/hashload/jhonson": {"name": "github_com_hashload_jhonson", "version": "1.0.0", "hash": "h2"}
+	}}`
+	writeTestFile(t, filepath.Join(dir, "boss-lock.json"), lock)
+	writeTestFile(t, filepath.Join(dir, "modules", "hashload_horse", "boss.json"),
+		`{"name": "horse", "dependencies": {"github.com/HashLoad/jhonson": "^1.0.0", "acme/missing": "^1"}}`)
+	writeTestFile(t, filepath.Join(dir, "modules", "github_com_hashload_jhonson", "boss.json"),
+		`{"name": "jhonson", "license": "MIT"}`)
+
+	return bossManifest{Name: "app", Version: "1.0.0", Dependencies: map[string]string{"hashload/horse": "^3.0.0"}}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveSbomComponents_IncludesTransitiveModules(t *testing.T) {
+	components := resolveSbomComponents(setupSbomProject(t))
+
+	if len(components) != 2 {
+		t.Fatalf("expected 2 components, got %+v", components)
+	}
+	horse, jhonson := components[0], components[1]
+	if !horse.Direct || horse.Version != "v3.1.0" || horse.Commit != "abc123" || horse.Constraint != "^3.0.0" {
+		t.Errorf("unexpected direct component %+v", horse)
+	}
+	if jhonson.Direct || jhonson.Name != "github.com/hashload/jhonson" || jhonson.License != "MIT" {
+		t.Errorf("unexpected transitive component %+v", jhonson)
+	}
+	if len(horse.DependsOn) != 1 || horse.DependsOn[0] != jhonson.Ref {
+		t.Errorf("horse should depend on jhonson only, got %v", horse.DependsOn)
+	}
+	if len(jhonson.DependsOn) != 0 {
+		t.Errorf("jhonson declares no dependencies, got %v", jhonson.DependsOn)
+	}
+}
+
+func TestGenerateCycloneDxSbom_DependencyGraph(t *testing.T) {
+	manifest := setupSbomProject(t)
+	generateCycloneDxSbom("app", manifest, resolveSbomComponents(manifest), ".")
+
+	data, err := os.ReadFile("app.cdx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc cdxDocument
+	if err = json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid CycloneDX JSON: %v", err)
+	}
+
+	if len(doc.Dependencies) != 3 {
+		t.Fatalf("expected a graph node for the root and each component, got %+v", doc.Dependencies)
+	}
+	root := doc.Dependencies[0]
+	if root.Ref != doc.Metadata.Component.BomRef || len(root.DependsOn) != 1 ||
+		root.DependsOn[0] != "pkg:github/hashload/horse@v3.1.0" {
+		t.Errorf("root should depend on the direct dependency only, got %+v", root)
+	}
+	for _, component := range doc.Components {
+		if component.BomRef == "" {
+			t.Errorf("component %s has no bom-ref", component.Name)
+		}
+	}
+}
+
+func TestGenerateSpdxSbom_Relationships(t *testing.T) {
+	manifest := setupSbomProject(t)
+	generateSpdxSbom("app", manifest, resolveSbomComponents(manifest), ".")
+
+	data, err := os.ReadFile("app.spdx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+
+	for _, want := range []string{
+		"Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-Root\n",
+		"Relationship: SPDXRef-Package-Root DEPENDS_ON SPDXRef-Package-Dep-1\n",
+		"Relationship: SPDXRef-Package-Dep-1 DEPENDS_ON SPDXRef-Package-Dep-2\n",
+		"PackageDownloadLocation: git+https://github.com/hashload/horse@abc123\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("SPDX document is missing %q", want)
+		}
+	}
+	if strings.Contains(text, "SPDXRef-Package-Root DEPENDS_ON SPDXRef-Package-Dep-2") {
+		t.Error("the transitive module must not be a direct dependency of the root")
+	}
+}
+
+func TestReadSbomDocument_GeneratedDocumentsMatchTheLock(t *testing.T) {
+	manifest := setupSbomProject(t)
+	components := resolveSbomComponents(manifest)
+	generateCycloneDxSbom("app", manifest, components, ".")
+	generateSpdxSbom("app", manifest, components, ".")
+
+	for _, file := range []string{"app.cdx.json", "app.spdx"} {
+		doc, err := readSbomDocument(file)
+		if err != nil {
+			t.Fatalf("readSbomDocument(%s) error = %v", file, err)
+		}
+		if len(doc.Problems) != 0 {
+			t.Errorf("%s: unexpected problems %v", file, doc.Problems)
+		}
+		if len(doc.Components) != 2 || !doc.Components[0].Direct || doc.Components[1].Direct {
+			t.Errorf("%s: unexpected components %+v", file, doc.Components)
+		}
+		if issues := compareSbomWithLock(doc.Components, loadLockedModules()); len(issues) != 0 {
+			t.Errorf("%s: unexpected lock issues %+v", file, issues)
+		}
+	}
+}
+
+func TestReadSbomDocument_ReportsSchemaViolations(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.cdx.json")
+	writeTestFile(t, path, `{"bomFormat": "CycloneDX", "specVersion": "1.5", "serialNumber": "urn:uuid:nope",
+		"components": [{"type": "gadget", "name": "x", "bom-ref": "a"}],
+		"dependencies": [{"ref": "a", "dependsOn": ["b"]}]}`)
+
+	doc, err := readSbomDocument(path)
+	if err != nil {
+		t.Fatalf("readSbomDocument() error = %v", err)
+	}
+	joined := strings.Join(doc.Problems, "\n")
+	for _, want := range []string{"/serialNumber", "/components/0/type", `unknown bom-ref "b"`} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("problems should mention %s, got:\n%s", want, joined)
+		}
+	}
+}
+
+func TestCompareSbomWithLock(t *testing.T) {
+	locked := map[string]lockedModule{
+		"github.com/hashload/horse": {
+			Repository: "hashload/horse", LockedDependency: domain.LockedDependency{Version: "v3.1.0"},
+		},
+		"github.com/hashload/jhonson": {Repository: "github.com/hashload/jhonson"},
+	}
+
+	issues := compareSbomWithLock([]sbomComponent{
+		{Key: "github.com/hashload/horse", Version: "3.0.0"},
+		{Key: "github.com/acme/extra", Version: "1.0.0"},
+	}, locked)
+
+	kinds := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		kinds = append(kinds, issue.Kind+" "+issue.Component)
+	}
+	want := "extra github.com/acme/extra,version github.com/hashload/horse,missing github.com/hashload/jhonson"
+	if got := strings.Join(kinds, ","); got != want {
+		t.Errorf("issues = %s, want %s", got, want)
+	}
+}
+
+func TestDiffSbomComponents(t *testing.T) {
+	oldComponents := []sbomComponent{
+		{Key: "github.com/hashload/horse", Version: "v3.0.0"},
+		{Key: "github.com/hashload/jhonson", Version: "1.2.0"},
+		{Key: "github.com/acme/gone", Version: "1.0.0"},
+		{Key: "github.com/acme/branch", Version: "main", Commit: "aaaaaaaaaaaaaaaa"},
+	}
+	newComponents := []sbomComponent{
+		{Key: "github.com/hashload/horse", Version: "3.1.0"},
+		{Key: "github.com/hashload/jhonson", Version: "1.1.0"},
+		{Key: "github.com/acme/new", Version: "0.1.0"},
+		{Key: "github.com/acme/branch", Version: "main", Commit: "bbbbbbbbbbbbbbbb"},
+	}
+
+	got := map[string]string{}
+	for _, change := range diffSbomComponents(oldComponents, newComponents) {
+		got[change.Component] = change.Change
+	}
+	want := map[string]string{
+		"github.com/hashload/horse":   sbomChangeUpgraded,
+		"github.com/hashload/jhonson": sbomChangeDowngraded,
+		"github.com/acme/gone":        sbomChangeRemoved,
+		"github.com/acme/new":         sbomChangeAdded,
+		"github.com/acme/branch":      sbomChangeChanged,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+	for key, change := range want {
+		if got[key] != change {
+			t.Errorf("%s: change = %q, want %q", key, got[key], change)
+		}
+	}
+}
+
+func TestPurlRepository(t *testing.T) {
+	tests := map[string]string{
+		"pkg:github/hashload/horse@v3.1.0":                                     "github.com/hashload/horse",
+		"pkg:bitbucket/acme/lib":                                               "bitbucket.org/acme/lib",
+		buildPurl("gitlab.com/acme/lib", "1.0.0"):                              "gitlab.com/acme/lib",
+		"pkg:generic/lib?vcs_url=git%2Bhttps%3A%2F%2Fgit.acme.com%2Flib%40abc": "git.acme.com/lib",
+		"pkg:npm/left-pad@1.0.0":                                               "",
+	}
+	for purl, want := range tests {
+		if got := purlRepository(purl); got != want {
+			t.Errorf("purlRepository(%q) = %q, want %q", purl, got, want)
+		}
+	}
+}
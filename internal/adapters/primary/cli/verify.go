@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/adapters/secondary/repository"
+	lockService "github.com/hashload/boss/internal/core/services/lock"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/spf13/cobra"
+)
+
+// verifyCmdRegister registers the verify command.
+func verifyCmdRegister(root *cobra.Command) {
+	var asJSON bool
+
+	verifyCmd := &cobra.Command{
+		Use:   cmdNameVerify,
+		Short: "Check installed modules against the file manifests in boss-lock.json",
+		Long: `Compare every module under modules/ with the SHA-256 file manifest recorded in boss-lock.json
+and list the files that were modified, added or deleted since it was installed.
+VCS metadata (.git) and build outputs (.dcu, .bpl, ...) are not part of the manifest.
+The command exits with status 1 when a module does not match the lock.`,
+		Example: `  Check the installed modules:
+  boss verify
+
+  Print the report as JSON:
+  boss verify --json`,
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			runVerify(asJSON)
+		},
+	}
+
+	verifyCmd.Flags().BoolVar(&asJSON, flagNameJSON, false, "Print the report as JSON")
+	root.AddCommand(verifyCmd)
+}
+
+// runVerify prints the modules that differ from the lock and fails if any does.
+func runVerify(asJSON bool) {
+	fs := filesystem.NewOSFileSystem()
+	lockRepo := repository.NewFileLockRepository(fs)
+	lock, err := lockRepo.Load(consts.FilePackageLock)
+	if err != nil {
+		msg.Die("❌ Failed to read %s: %s", consts.FilePackageLock, err)
+	}
+
+	reports, err := lockService.NewLockService(lockRepo, fs).Verify(lock, env.GetModulesDir())
+	if err != nil {
+		msg.Die("❌ Failed to verify the installed modules: %s", err)
+	}
+
+	if asJSON {
+		printJSONPayload(reports)
+	} else {
+		printVerifyReports(reports)
+	}
+
+	dirty := 0
+	for _, report := range reports {
+		if !report.Clean() {
+			dirty++
+		}
+	}
+	if dirty > 0 {
+		msg.Err("❌ %d module(s) do not match %s", dirty, consts.FilePackageLock)
+		os.Exit(1)
+	}
+}
+
+// printVerifyReports prints one block per module that differs from the lock.
+func printVerifyReports(reports []lockService.ModuleReport) {
+	if len(reports) == 0 {
+		msg.Info("No dependencies in %s", consts.FilePackageLock)
+		return
+	}
+
+	for _, report := range reports {
+		switch {
+		case report.Clean():
+			continue
+		case report.Missing:
+			msg.Warn("%s: module %s is not installed", report.Repository, report.Module)
+			continue
+		case report.NoManifest:
+			msg.Warn("%s: no file manifest in the lock, run boss install to record one", report.Repository)
+			continue
+		}
+
+		msg.Warn("%s (%s)", report.Repository, report.Module)
+		for _, path := range report.Modified {
+			msg.Info("  M %s", path)
+		}
+		for _, path := range report.Added {
+			msg.Info("  A %s", path)
+		}
+		for _, path := range report.Deleted {
+			msg.Info("  D %s", path)
+		}
+	}
+
+	for _, report := range reports {
+		if !report.Clean() {
+			return
+		}
+	}
+	msg.Success("✅ %d module(s) match %s", len(reports), consts.FilePackageLock)
+}
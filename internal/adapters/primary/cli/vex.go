@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/adapters/secondary/repository"
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/audit"
+	"github.com/hashload/boss/internal/core/services/vex"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/spf13/cobra"
+)
+
+// vexFormatCycloneDx and vexFormatOpenVex are the values accepted by
+// 'boss cra vex export --format'.
+const (
+	vexFormatCycloneDx = sbomFormatCycloneDx
+	vexFormatOpenVex   = "openvex"
+)
+
+// craVexCmdRegister registers 'boss cra vex' and its sub-commands.
+func craVexCmdRegister(craCmd *cobra.Command) {
+	vexCmd := &cobra.Command{
+		Use:   "vex",
+		Short: "Record whether known vulnerabilities affect the product (VEX)",
+		Long: `List the VEX (Vulnerability Exploitability eXchange) statements kept in boss-vex.json and the
+vulnerabilities reported by 'boss audit' that have no statement yet.
+Statements are published as CycloneDX VEX or OpenVEX next to the SBOM written by 'boss sbom'.`,
+		Example: `  Record that a vulnerability does not affect the product:
+  boss cra vex set CVE-2024-0001 github.com/hashload/horse --status not_affected \
+    --justification vulnerable_code_not_in_execute_path
+
+  Export an OpenVEX document:
+  boss cra vex export --format openvex`,
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			runVexList()
+		},
+	}
+
+	var statement vex.Statement
+	var status, justification string
+	setCmd := &cobra.Command{
+		Use:   "set <vulnerability> <component>",
+		Short: "Add or replace the statement about a vulnerability in a dependency",
+		Long: `Add or replace the statement about a vulnerability (advisory ID or CVE) in a dependency.
+Status is not_affected, affected, fixed or under_investigation. A not_affected statement needs a
+--justification or an --impact statement; an affected statement needs an --action statement.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(_ *cobra.Command, args []string) {
+			statement.Vulnerability, statement.Component = args[0], args[1]
+			runVexSet(statement, status, justification)
+		},
+	}
+	setCmd.Flags().StringVar(&status, "status", "", "not_affected, affected, fixed or under_investigation")
+	setCmd.Flags().StringVar(&justification, "justification", "",
+		"Why the product is not affected (component_not_present, vulnerable_code_not_present, "+
+			"vulnerable_code_not_in_execute_path, vulnerable_code_cannot_be_controlled_by_adversary, "+
+			"inline_mitigations_already_exist)")
+	setCmd.Flags().StringVar(&statement.Impact, "impact", "", "How the product avoids the vulnerability")
+	setCmd.Flags().StringVar(&statement.Action, "action", "", "What users of an affected product should do")
+	_ = setCmd.MarkFlagRequired("status")
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <vulnerability> <component>",
+		Short: "Remove the statement about a vulnerability in a dependency",
+		Long:  "Remove the statement about a vulnerability in a dependency from boss-vex.json.",
+		Args:  cobra.ExactArgs(2),
+		Run: func(_ *cobra.Command, args []string) {
+			runVexRemove(args[0], args[1])
+		},
+	}
+
+	vexCmd.AddCommand(setCmd, removeCmd, vexExportCmd())
+	craCmd.AddCommand(vexCmd)
+}
+
+func vexExportCmd() *cobra.Command {
+	var format, projectFile, outputDir string
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the statements as a CycloneDX VEX or OpenVEX document",
+		Long: `Write the statements of boss-vex.json as a CycloneDX 1.5 VEX (<project>.vex.cdx.json) or
+OpenVEX (<project>.openvex.json) document, in the directory 'boss sbom' writes the SBOM to.`,
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			runVexExport(format, projectFile, outputDir)
+		},
+	}
+	exportCmd.Flags().StringVar(&format, "format", vexFormatCycloneDx,
+		fmt.Sprintf("VEX format (%s or %s)", vexFormatCycloneDx, vexFormatOpenVex))
+	exportCmd.Flags().StringVar(&projectFile, "project", "", "Path to the Delphi .dproj file")
+	exportCmd.Flags().StringVar(&outputDir, "output", "./sbom", "Directory to write the document to")
+	return exportCmd
+}
+
+// loadVexDocument reads boss-vex.json from the project root.
+func loadVexDocument() *vex.Document {
+	doc, err := vex.Load(consts.FileVex)
+	if err != nil {
+		msg.Die("❌ Failed to read %s: %s", consts.FileVex, err)
+	}
+	return doc
+}
+
+// runVexSet validates and stores a statement.
+func runVexSet(statement vex.Statement, status, justification string) {
+	parsedStatus, err := vex.ParseStatus(status)
+	if err != nil {
+		msg.Die("❌ %s", err)
+	}
+	statement.Status = parsedStatus
+	if justification != "" {
+		if statement.Justification, err = vex.ParseJustification(justification); err != nil {
+			msg.Die("❌ %s", err)
+		}
+	}
+	if err = statement.Validate(); err != nil {
+		msg.Die("❌ %s", err)
+	}
+
+	if _, locked := loadLockedModules()[domain.RepositoryKey(statement.Component)]; !locked {
+		msg.Warn("⚠️ %s is not in %s; the statement is kept anyway.", statement.Component, consts.FilePackageLock)
+	}
+
+	doc := loadVexDocument()
+	doc.Set(statement)
+	if err = doc.Save(consts.FileVex); err != nil {
+		msg.Die("❌ Failed to write %s: %s", consts.FileVex, err)
+	}
+	msg.Success("✅ %s in %s: %s", statement.Vulnerability, statement.Component, statement.Status)
+}
+
+// runVexRemove deletes a statement.
+func runVexRemove(vulnerability, component string) {
+	doc := loadVexDocument()
+	if !doc.Remove(vulnerability, component) {
+		msg.Die("❌ No statement about %s in %s", vulnerability, component)
+	}
+	if err := doc.Save(consts.FileVex); err != nil {
+		msg.Die("❌ Failed to write %s: %s", consts.FileVex, err)
+	}
+	msg.Success("✅ Removed the statement about %s in %s", vulnerability, component)
+}
+
+// runVexList prints the statements and the vulnerabilities still to triage.
+func runVexList() {
+	doc := loadVexDocument()
+
+	if len(doc.Statements) == 0 {
+		msg.Info("No VEX statements in %s.", consts.FileVex)
+	} else {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(writer, "VULNERABILITY\tCOMPONENT\tSTATUS\tDETAIL")
+		for _, statement := range doc.Statements {
+			detail := string(statement.Justification)
+			if detail == "" {
+				detail = statement.Impact + statement.Action
+			}
+			_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+				statement.Vulnerability, statement.Component, statement.Status, orDash(detail))
+		}
+		_ = writer.Flush()
+	}
+
+	untriaged, err := untriagedFindings(doc)
+	if errors.Is(err, audit.ErrDatabaseMissing) {
+		msg.Info("Run 'boss audit update' to check for vulnerabilities without a statement.")
+		return
+	}
+	if err != nil {
+		msg.Die("❌ %s", err)
+	}
+	reportUntriaged(untriaged)
+}
+
+// untriagedFindings audits the lock file against the local advisory database
+// and returns the findings that have no final VEX statement.
+func untriagedFindings(doc *vex.Document) ([]audit.Finding, error) {
+	advisories, err := audit.LoadDatabase(auditDatabaseDir(""))
+	if err != nil {
+		return nil, err
+	}
+	lock, err := repository.NewFileLockRepository(filesystem.NewOSFileSystem()).Load(consts.FilePackageLock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", consts.FilePackageLock, err)
+	}
+	return doc.Untriaged(audit.Audit(lock, advisories).Findings), nil
+}
+
+// reportUntriaged prints the vulnerabilities that still need a statement.
+func reportUntriaged(untriaged []audit.Finding) {
+	if len(untriaged) == 0 {
+		msg.Info("✅ Every known vulnerability has a VEX statement.")
+		return
+	}
+	msg.Warn("⚠️ %d vulnerability(ies) without a VEX statement:", len(untriaged))
+	for _, finding := range untriaged {
+		msg.Warn("   - [%s] %s", strings.ToUpper(string(finding.Severity)), audit.FindingMessage(finding))
+	}
+	msg.Info("   -> To fix: Run 'boss cra vex set <vulnerability> <component> --status ...' for each of them.")
+}
+
+// runVexExport writes the VEX document of the project.
+func runVexExport(format, projectFile, outputDir string) {
+	normalizedFormat := strings.ToLower(strings.TrimSpace(format))
+	if normalizedFormat != vexFormatCycloneDx && normalizedFormat != vexFormatOpenVex {
+		msg.Die("❌ Unsupported VEX format %q. Supported formats: %s, %s.", format, vexFormatCycloneDx, vexFormatOpenVex)
+	}
+
+	projectName := strings.TrimSuffix(filepath.Base(findSbomProject(projectFile)), ".dproj")
+	manifest, err := readBossManifest(bossManifestFile)
+	if err != nil {
+		msg.Die("❌ Failed to read boss.json: %s", err)
+	}
+	if err = os.MkdirAll(outputDir, 0750); err != nil {
+		msg.Die("❌ Failed to create output directory: %s", err)
+	}
+
+	writeProjectVex(normalizedFormat, projectName, manifest, resolveSbomComponents(manifest), outputDir, true)
+}
+
+// writeProjectVex writes the statements of boss-vex.json next to the SBOM.
+// Unless always is set, nothing is written for a project without statements.
+func writeProjectVex(
+	format, projectName string,
+	manifest bossManifest,
+	components []sbomComponent,
+	outputDir string,
+	always bool,
+) {
+	doc := loadVexDocument()
+	if len(doc.Statements) == 0 && !always {
+		return
+	}
+
+	name, version := sbomSubject(manifest)
+	product := vex.Product{Name: name, Version: version, ID: buildPurl(name, version),
+		Components: make(map[string]vex.Component, len(components))}
+	for _, component := range components {
+		product.Components[domain.RepositoryKey(component.Key)] = vex.Component{
+			Name: component.Name, Version: component.Version, Purl: component.Purl,
+		}
+	}
+	options := vex.Options{DocumentID: generateUUID(), Author: name, Timestamp: time.Now()}
+
+	outputFile := filepath.Join(outputDir, projectName+".vex.cdx.json")
+	write := vex.WriteCycloneDX
+	if format == vexFormatOpenVex {
+		outputFile = filepath.Join(outputDir, projectName+".openvex.json")
+		write = vex.WriteOpenVEX
+	}
+
+	file, err := os.Create(outputFile) // #nosec G304 -- Writing into the SBOM output directory
+	if err != nil {
+		msg.Die("❌ Failed to create %s: %s", outputFile, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if err = write(file, product, doc.Statements, options); err != nil {
+		msg.Die("❌ Failed to write the VEX document: %s", err)
+	}
+	msg.Info("  VEX document generated: %s (%d statement(s))", outputFile, len(doc.Statements))
+}
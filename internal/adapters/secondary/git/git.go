@@ -3,6 +3,7 @@
 package gitadapter
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/go-git/go-billy/v5/osfs"
@@ -167,6 +168,21 @@ func GetRepository(dep domain.Dependency) *goGit.Repository {
 	return repository
 }
 
+// HeadCommit returns the commit currently checked out for the dependency.
+func HeadCommit(dep domain.Dependency) (string, error) {
+	cache := makeStorageCacheWithoutEnsure(dep)
+	dir := osfs.New(filepath.Join(env.GetModulesDir(), dep.Name()))
+	repository, err := goGit.Open(cache, dir)
+	if err != nil {
+		return "", fmt.Errorf("open repository %s: %w", dep.Repository, err)
+	}
+	head, err := repository.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
 // Checkout switches the dependency repository to the given reference.
 func Checkout(config env.ConfigProvider, dep domain.Dependency, referenceName plumbing.ReferenceName) error {
 	if config.GetGitEmbedded() {
@@ -168,3 +168,40 @@ func NeedsVersionUpdate(currentVersion, newVersion string) bool {
 
 	return parsedNew.GreaterThan(parsedCurrent)
 }
+
+// NormalizeRepository reduces a repository URL or path to the lowercase
+// host/owner/name form used as key in boss-lock.json.
+func NormalizeRepository(raw string) string {
+	repo := strings.ToLower(strings.TrimSpace(raw))
+	if repo == "" {
+		return ""
+	}
+	if idx := strings.Index(repo, "://"); idx >= 0 {
+		repo = repo[idx+3:]
+	}
+	// user@host credentials precede the first slash; anything after an "@"
+	// past that point is a revision (vcs_url=git+https://host/o/r@v1.0).
+	if at, slash := strings.Index(repo, "@"), strings.Index(repo, "/"); at >= 0 && (slash < 0 || at < slash) {
+		repo = repo[at+1:]
+	}
+	if idx := strings.Index(repo, "@"); idx >= 0 {
+		repo = repo[:idx]
+	}
+	// scp-like SSH syntax: github.com:owner/repo
+	if idx := strings.Index(repo, ":"); idx >= 0 && !strings.Contains(repo[:idx], "/") {
+		repo = repo[:idx] + "/" + repo[idx+1:]
+	}
+	repo = strings.TrimSuffix(strings.TrimSuffix(repo, "/"), ".git")
+	return repo
+}
+
+// RepositoryKey is NormalizeRepository with the implied github.com host added
+// to owner/name shorthands, so that "hashload/horse" and
+// "https://github.com/HashLoad/horse.git" compare equal.
+func RepositoryKey(repository string) string {
+	key := NormalizeRepository(repository)
+	if strings.Count(key, "/") == 1 {
+		key = "github.com/" + key
+	}
+	return key
+}
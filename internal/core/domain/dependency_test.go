@@ -392,3 +392,31 @@ func TestDependency_SSHUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeRepository(t *testing.T) {
+	tests := map[string]string{
+		"github.com/HashLoad/horse":                "github.com/hashload/horse",
+		"https://github.com/HashLoad/horse.git":    "github.com/hashload/horse",
+		"git@github.com:HashLoad/horse.git":        "github.com/hashload/horse",
+		"https://user@gitlab.com/acme/lib/":        "gitlab.com/acme/lib",
+		"https://github.com/hashload/horse@v3.0.0": "github.com/hashload/horse",
+	}
+	for input, want := range tests {
+		if got := domain.NormalizeRepository(input); got != want {
+			t.Errorf("NormalizeRepository(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestRepositoryKey(t *testing.T) {
+	tests := map[string]string{
+		"hashload/horse":                        "github.com/hashload/horse",
+		"https://github.com/HashLoad/horse.git": "github.com/hashload/horse",
+		"gitlab.com/acme/lib":                   "gitlab.com/acme/lib",
+	}
+	for input, want := range tests {
+		if got := domain.RepositoryKey(input); got != want {
+			t.Errorf("RepositoryKey(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
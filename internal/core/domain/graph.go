@@ -1,11 +1,11 @@
 package domain
 
 import (
+	"container/heap"
+	"slices"
 	"strings"
 	"sync"
 
-	"slices"
-
 	"github.com/hashload/boss/pkg/msg"
 )
 
@@ -24,14 +24,23 @@ func (n *Node) String() string {
 	return n.Dep.Name()
 }
 
-// GraphItem represents a dependency graph.
+// GraphItem represents a dependency graph. Nodes keep the order they were
+// added in, which breaks ties in the build order; edges are kept both ways so
+// that dependencies and consumers are found without scanning the graph.
 type GraphItem struct {
 	nodes     []*Node
+	index     map[string]int
+	edges     map[graphEdge]bool
 	depends   map[string][]*Node
 	usedBy    map[string][]*Node
 	lockMutex sync.RWMutex
 }
 
+// graphEdge is the edge from the node of value from to the node of value to.
+type graphEdge struct {
+	from, to string
+}
+
 func (g *GraphItem) lock() {
 	g.lockMutex.Lock()
 }
@@ -40,61 +49,128 @@ func (g *GraphItem) unlock() {
 	g.lockMutex.Unlock()
 }
 
+// init creates the maps of a zero GraphItem; it is called with the lock held.
+func (g *GraphItem) init() {
+	if g.index == nil {
+		g.index = make(map[string]int)
+		g.edges = make(map[graphEdge]bool)
+		g.depends = make(map[string][]*Node)
+		g.usedBy = make(map[string][]*Node)
+	}
+}
+
 // AddNode adds a node to the graph.
 func (g *GraphItem) AddNode(n *Node) {
 	g.lock()
-	if !contains(g.nodes, n) {
+	g.init()
+	if _, ok := g.index[n.Value]; !ok {
+		g.index[n.Value] = len(g.nodes)
 		g.nodes = append(g.nodes, n)
 	}
 	g.unlock()
 }
 
-func contains(a []*Node, x *Node) bool {
-	for _, n := range a {
-		if x.Value == n.Value {
-			return true
-		}
+// AddEdge adds a directed edge from nLeft to nRight.
+func (g *GraphItem) AddEdge(nLeft, nRight *Node) {
+	g.lock()
+	g.init()
+	edge := graphEdge{from: nLeft.Value, to: nRight.Value}
+	if !g.edges[edge] {
+		g.edges[edge] = true
+		g.depends[nLeft.Value] = append(g.depends[nLeft.Value], nRight)
+		g.usedBy[nRight.Value] = append(g.usedBy[nRight.Value], nLeft)
 	}
-	return false
+	g.unlock()
 }
 
-func containsOne(a []*Node, b []*Node) bool {
-	for _, n := range a {
-		for _, x := range b {
-			if x.Value == n.Value {
-				return true
-			}
-		}
+// RemoveEdge removes the edge from nLeft to nRight, if any.
+func (g *GraphItem) RemoveEdge(nLeft, nRight *Node) {
+	g.lock()
+	edge := graphEdge{from: nLeft.Value, to: nRight.Value}
+	if g.edges[edge] {
+		delete(g.edges, edge)
+		g.depends[nLeft.Value] = slices.DeleteFunc(g.depends[nLeft.Value], func(n *Node) bool {
+			return n.Value == nRight.Value
+		})
+		g.usedBy[nRight.Value] = slices.DeleteFunc(g.usedBy[nRight.Value], func(n *Node) bool {
+			return n.Value == nLeft.Value
+		})
 	}
-	return false
+	g.unlock()
 }
 
-func containsAll(list []*Node, in []*Node) bool {
-	var check = 0
-	for _, n := range in {
-		for _, x := range list {
-			if x.Value == n.Value {
-				check++
+// Cycles returns the cycles of the graph, each as the path of its nodes back
+// to the first one: A, B, C, A for A depending on B, B on C and C on A. Each
+// starts at its lowest node, whatever the order dependencies were added in.
+func (g *GraphItem) Cycles() [][]*Node {
+	g.lockMutex.RLock()
+	defer g.lockMutex.RUnlock()
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(g.nodes))
+	var path []*Node
+	var cycles [][]*Node
+	var visit func(n *Node)
+	visit = func(n *Node) {
+		state[n.Value] = visiting
+		path = append(path, n)
+		for _, next := range g.depends[n.Value] {
+			switch state[next.Value] {
+			case visiting:
+				start := slices.IndexFunc(path, func(p *Node) bool { return p.Value == next.Value })
+				cycles = append(cycles, rotateCycle(path[start:]))
+			case 0:
+				visit(next)
 			}
 		}
+		path = path[:len(path)-1]
+		state[n.Value] = visited
 	}
-	return check == len(in)
+	for _, node := range g.nodes {
+		if state[node.Value] == 0 {
+			visit(node)
+		}
+	}
+	return cycles
 }
 
-// AddEdge adds a directed edge from nLeft to nRight.
-func (g *GraphItem) AddEdge(nLeft, nRight *Node) {
-	g.lock()
-	if g.depends == nil {
-		g.depends = make(map[string][]*Node)
-		g.usedBy = make(map[string][]*Node)
-	}
-	if !contains(g.depends[nLeft.Value], nRight) {
-		g.depends[nLeft.Value] = append(g.depends[nLeft.Value], nRight)
-	}
-	if !contains(g.usedBy[nRight.Value], nLeft) {
-		g.usedBy[nRight.Value] = append(g.usedBy[nRight.Value], nLeft)
+// rotateCycle returns the cycle of nodes starting at its lowest node, and
+// back to it.
+func rotateCycle(nodes []*Node) []*Node {
+	lowest := 0
+	for i, node := range nodes {
+		if node.Value < nodes[lowest].Value {
+			lowest = i
+		}
 	}
-	g.unlock()
+	cycle := slices.Concat(nodes[lowest:], nodes[:lowest])
+	return append(cycle, cycle[0])
+}
+
+// Nodes returns the nodes of the graph in the order they were added.
+func (g *GraphItem) Nodes() []*Node {
+	g.lockMutex.RLock()
+	defer g.lockMutex.RUnlock()
+	return slices.Clone(g.nodes)
+}
+
+// DependsOn returns the nodes n has an edge to, the dependencies it is built
+// against.
+func (g *GraphItem) DependsOn(n *Node) []*Node {
+	g.lockMutex.RLock()
+	defer g.lockMutex.RUnlock()
+	return slices.Clone(g.depends[n.Value])
+}
+
+// UsedBy returns the nodes with an edge to n, the dependencies built against
+// it.
+func (g *GraphItem) UsedBy(n *Node) []*Node {
+	g.lockMutex.RLock()
+	defer g.lockMutex.RUnlock()
+	return slices.Clone(g.usedBy[n.Value])
 }
 
 func (g *GraphItem) String() {
@@ -122,66 +198,125 @@ func (g *GraphItem) String() {
 	g.unlock()
 }
 
-func removeNode(nodes []*Node, key int) []*Node {
-	if key == len(nodes) {
-		return nodes[:key]
-	}
-	return slices.Delete(nodes, key, key+1)
-}
-
-// Queue creates a queue of nodes to be processed.
+// Queue creates a queue of nodes to be processed: all of them with allDeps,
+// or else those whose lock entry changed and, transitively, their consumers,
+// which are marked as changed in pkg.Lock. Nodes come after the nodes they
+// depend on.
 func (g *GraphItem) Queue(pkg *Package, allDeps bool) *NodeQueue {
 	g.lock()
+	defer g.unlock()
 	queue := NodeQueue{}
 	queue.New()
-	nodes := g.nodes
-	for key := 0; key < len(nodes); key++ {
-		if !pkg.Lock.GetInstalled(nodes[key].Dep).Changed && !allDeps {
-			nodes = removeNode(nodes, key)
-			key--
+
+	selected := make(map[string]bool, len(g.nodes))
+	for _, node := range g.nodes {
+		if allDeps || pkg.Lock.GetInstalled(node.Dep).Changed {
+			selected[node.Value] = true
 		}
 	}
-
-	nodes = g.expandGraphNodes(nodes, pkg)
-
-	g.processNodes(nodes, &queue)
-	g.unlock()
+	if !allDeps {
+		g.expandConsumers(selected, pkg)
+	}
+	g.processNodes(selected, &queue)
 	return &queue
 }
 
-func (g *GraphItem) processNodes(nodes []*Node, queue *NodeQueue) {
-	for len(nodes) > 0 {
-		for key := 0; key < len(nodes); key++ {
-			node := nodes[key]
-			if !containsOne(g.depends[node.Value], nodes) {
-				queue.Enqueue(*node)
-				nodes = removeNode(nodes, key)
-				key--
+// expandConsumers adds to selected the nodes depending on a selected node,
+// directly or not, and marks them as changed in the lock of pkg.
+func (g *GraphItem) expandConsumers(selected map[string]bool, pkg *Package) {
+	pending := make([]*Node, 0, len(selected))
+	for _, node := range g.nodes {
+		if selected[node.Value] {
+			pending = append(pending, node)
+		}
+	}
+	for len(pending) > 0 {
+		node := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, consumer := range g.usedBy[node.Value] {
+			if selected[consumer.Value] {
+				continue
 			}
+			installed := pkg.Lock.GetInstalled(consumer.Dep)
+			installed.Changed = true
+			pkg.Lock.SetInstalled(consumer.Dep, installed)
+			selected[consumer.Value] = true
+			pending = append(pending, consumer)
 		}
 	}
 }
 
-func (g *GraphItem) expandGraphNodes(nodes []*Node, pkg *Package) []*Node {
-	redo := true
-	for redo {
-		redo = false
-		for _, node := range nodes {
-			usedBy := g.usedBy[node.Value]
-			if !containsAll(nodes, usedBy) {
-				for _, consumerNode := range usedBy {
-					installed := pkg.Lock.GetInstalled(consumerNode.Dep)
-					installed.Changed = true
-					pkg.Lock.SetInstalled(consumerNode.Dep, installed)
-					if !contains(nodes, consumerNode) {
-						redo = true
-						nodes = append(nodes, consumerNode)
-					}
-				}
+// processNodes enqueues the selected nodes after the nodes they depend on with
+// Kahn's algorithm, the ready node added first going first. Nodes left in a
+// cycle, which the graph should not have by then, are enqueued as they are
+// rather than waited for forever.
+func (g *GraphItem) processNodes(selected map[string]bool, queue *NodeQueue) {
+	pending := make(map[string]int, len(selected))
+	ready := &nodeHeap{}
+	for position, node := range g.nodes {
+		if !selected[node.Value] {
+			continue
+		}
+		for _, dependency := range g.depends[node.Value] {
+			if selected[dependency.Value] {
+				pending[node.Value]++
+			}
+		}
+		if pending[node.Value] == 0 {
+			heap.Push(ready, position)
+		}
+	}
+
+	enqueued := make(map[string]bool, len(selected))
+	for ready.Len() > 0 {
+		position, _ := heap.Pop(ready).(int)
+		node := g.nodes[position]
+		queue.Enqueue(*node)
+		enqueued[node.Value] = true
+		for _, consumer := range g.usedBy[node.Value] {
+			if !selected[consumer.Value] {
+				continue
+			}
+			pending[consumer.Value]--
+			if pending[consumer.Value] == 0 {
+				heap.Push(ready, g.index[consumer.Value])
 			}
 		}
 	}
-	return nodes
+
+	if len(enqueued) == len(selected) {
+		return
+	}
+	var cycle []*Node
+	for _, node := range g.nodes {
+		if selected[node.Value] && !enqueued[node.Value] {
+			cycle = append(cycle, node)
+		}
+	}
+	msg.Warn("⚠️ Dependency cycle between %v, build order is not guaranteed", cycle)
+	for _, node := range cycle {
+		queue.Enqueue(*node)
+	}
+}
+
+// nodeHeap holds the positions of the nodes ready to be enqueued, lowest
+// first.
+type nodeHeap []int
+
+func (h nodeHeap) Len() int           { return len(h) }
+func (h nodeHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h nodeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *nodeHeap) Push(x any) {
+	position, _ := x.(int)
+	*h = append(*h, position)
+}
+
+func (h *nodeHeap) Pop() any {
+	old := *h
+	position := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return position
 }
 
 // NodeQueue represents a queue of nodes.
@@ -1,6 +1,8 @@
 package domain_test
 
 import (
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/hashload/boss/internal/core/domain"
@@ -145,3 +147,151 @@ func TestNodeQueue_Operations(t *testing.T) {
 		t.Error("Queue should be empty after all dequeues")
 	}
 }
+
+func cycleNodes(t *testing.T, repositories ...string) (*domain.GraphItem, []*domain.Node) {
+	t.Helper()
+	g := &domain.GraphItem{}
+	nodes := make([]*domain.Node, 0, len(repositories))
+	for _, repository := range repositories {
+		dep := domain.Dependency{Repository: repository}
+		node := domain.NewNode(&dep)
+		g.AddNode(node)
+		nodes = append(nodes, node)
+	}
+	return g, nodes
+}
+
+// TestGraphItem_Cycles tests that cycles are reported as paths from their lowest node.
+func TestGraphItem_Cycles(t *testing.T) {
+	g, nodes := cycleNodes(t, "github.com/test/c", "github.com/test/a", "github.com/test/b", "github.com/test/d")
+	c, a, b, d := nodes[0], nodes[1], nodes[2], nodes[3]
+	g.AddEdge(c, a)
+	g.AddEdge(a, b)
+	g.AddEdge(b, c)
+	g.AddEdge(d, a)
+
+	cycles := g.Cycles()
+	if len(cycles) != 1 {
+		t.Fatalf("Cycles() = %v, want 1 cycle", cycles)
+	}
+	var path []string
+	for _, node := range cycles[0] {
+		path = append(path, node.String())
+	}
+	want := []string{"github_com_test_a", "github_com_test_b", "github_com_test_c", "github_com_test_a"}
+	if !slices.Equal(path, want) {
+		t.Errorf("Cycles()[0] = %v, want %v", path, want)
+	}
+
+	g.RemoveEdge(b, c)
+	if cycles := g.Cycles(); len(cycles) != 0 {
+		t.Errorf("Cycles() after RemoveEdge = %v, want none", cycles)
+	}
+	if got := g.DependsOn(b); len(got) != 0 {
+		t.Errorf("DependsOn(b) after RemoveEdge = %v, want none", got)
+	}
+}
+
+// TestGraphItem_Queue_Cycle tests that a cycle left in the graph does not hang the queue.
+func TestGraphItem_Queue_Cycle(t *testing.T) {
+	g, nodes := cycleNodes(t, "github.com/test/a", "github.com/test/b", "github.com/test/c")
+	a, b, c := nodes[0], nodes[1], nodes[2]
+	g.AddEdge(a, b)
+	g.AddEdge(b, a)
+	g.AddEdge(a, c)
+
+	queue := g.Queue(&domain.Package{}, true)
+	if queue.Size() != 3 {
+		t.Fatalf("Queue() size = %d, want 3", queue.Size())
+	}
+	if first := queue.Dequeue(); first.Value != c.Value {
+		t.Errorf("Queue() first = %s, want %s", first.Value, c.Value)
+	}
+}
+
+// TestGraphItem_Queue_Changed tests that changed nodes are queued with their consumers, in build order.
+func TestGraphItem_Queue_Changed(t *testing.T) {
+	g, nodes := cycleNodes(t,
+		"github.com/test/app", "github.com/test/web", "github.com/test/core", "github.com/test/other")
+	app, web, core, other := nodes[0], nodes[1], nodes[2], nodes[3]
+	g.AddEdge(app, web)
+	g.AddEdge(web, core)
+	g.AddEdge(app, other)
+
+	pkg := &domain.Package{Lock: domain.PackageLock{Installed: map[string]domain.LockedDependency{
+		core.Dep.GetKey():  {Name: "core", Changed: true},
+		other.Dep.GetKey(): {Name: "other"},
+	}}}
+
+	var order []string
+	queue := g.Queue(pkg, false)
+	for !queue.IsEmpty() {
+		order = append(order, queue.Dequeue().Value)
+	}
+	want := []string{core.Value, web.Value, app.Value}
+	if !slices.Equal(order, want) {
+		t.Errorf("Queue() = %v, want %v", order, want)
+	}
+	if !pkg.Lock.GetInstalled(app.Dep).Changed || !pkg.Lock.GetInstalled(web.Dep).Changed {
+		t.Error("Queue() should mark the consumers of changed nodes as changed")
+	}
+	if pkg.Lock.GetInstalled(other.Dep).Changed {
+		t.Error("Queue() should not mark unrelated nodes as changed")
+	}
+}
+
+// syntheticGraph returns a graph of size nodes in layers of width, each node
+// depending on every fourth node of the layer below.
+func syntheticGraph(size, width int) (*domain.GraphItem, []*domain.Node) {
+	g := &domain.GraphItem{}
+	nodes := make([]*domain.Node, 0, size)
+	for i := range size {
+		dep := domain.Dependency{Repository: fmt.Sprintf("github.com/bench/pkg%05d", i)}
+		node := domain.NewNode(&dep)
+		g.AddNode(node)
+		nodes = append(nodes, node)
+		if i >= width {
+			layer := i/width*width - width
+			for j := i % 4; j < width; j += 4 {
+				g.AddEdge(node, nodes[layer+j])
+			}
+		}
+	}
+	return g, nodes
+}
+
+func BenchmarkGraphItem_QueueAll(b *testing.B) {
+	for _, size := range []int{1000, 5000} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			g, _ := syntheticGraph(size, 50)
+			pkg := &domain.Package{}
+			for b.Loop() {
+				g.Queue(pkg, true)
+			}
+		})
+	}
+}
+
+func BenchmarkGraphItem_QueueChanged(b *testing.B) {
+	for _, size := range []int{1000, 5000} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			g, nodes := syntheticGraph(size, 50)
+			for b.Loop() {
+				b.StopTimer()
+				pkg := &domain.Package{Lock: domain.PackageLock{Installed: map[string]domain.LockedDependency{}}}
+				for i := 0; i < size; i += 100 {
+					pkg.Lock.SetInstalled(nodes[i].Dep, domain.LockedDependency{Changed: true})
+				}
+				b.StartTimer()
+				g.Queue(pkg, false)
+			}
+		})
+	}
+}
+
+func BenchmarkGraphItem_Cycles(b *testing.B) {
+	g, _ := syntheticGraph(5000, 50)
+	for b.Loop() {
+		g.Cycles()
+	}
+}
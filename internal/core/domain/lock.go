@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"maps"
+	"path/filepath"
 	"strings"
 
 	"github.com/hashload/boss/utils"
@@ -10,18 +12,89 @@ import (
 type DependencyArtifacts struct {
 	Bin []string `json:"bin,omitempty"`
 	Dcp []string `json:"dcp,omitempty"`
+	// Dcu holds the compiled units: .dcu files, and the .ppu and .o files of
+	// native fpc builds.
 	Dcu []string `json:"dcu,omitempty"`
 	Bpl []string `json:"bpl,omitempty"`
 }
 
+// DefaultBuildConfig is the build configuration used when none is chosen.
+const DefaultBuildConfig = "Debug"
+
+// IsDefaultBuildConfig reports whether config names DefaultBuildConfig;
+// configuration names are case-insensitive, as in MSBuild.
+func IsDefaultBuildConfig(config string) bool {
+	return config == "" || strings.EqualFold(config, DefaultBuildConfig)
+}
+
+// ArtifactDir returns the shared artifact folder (.bpl, .dcp, .dcu or .bin
+// under modulesDir) of a build configuration. The default configuration uses
+// the folder itself, so existing library paths keep working; any other gets
+// a subfolder, so configurations never overwrite each other's artifacts.
+func ArtifactDir(modulesDir, folder, config string) string {
+	if IsDefaultBuildConfig(config) {
+		return filepath.Join(modulesDir, folder)
+	}
+	return filepath.Join(modulesDir, folder, config)
+}
+
+// PlatformArtifactDir returns the shared artifact folder of a platform of a
+// multi-platform build: the folder of ArtifactDir within a subfolder named
+// after the platform. An empty platform is a single-platform build, which
+// uses ArtifactDir itself.
+func PlatformArtifactDir(modulesDir, folder, platform, config string) string {
+	if platform == "" {
+		return ArtifactDir(modulesDir, folder, config)
+	}
+	return ArtifactDir(modulesDir, filepath.Join(folder, platform), config)
+}
+
+// PlatformArtifacts are the artifacts of a dependency for one platform of a
+// multi-platform build.
+type PlatformArtifacts struct {
+	// Config is the build configuration Artifacts were built with, empty for
+	// DefaultBuildConfig.
+	Config    string              `json:"config,omitempty"`
+	Artifacts DependencyArtifacts `json:"artifacts"`
+}
+
 // LockedDependency represents a locked dependency in the lock file.
 type LockedDependency struct {
-	Name      string              `json:"name"`
-	Version   string              `json:"version"`
-	Hash      string              `json:"hash"`
+	Name    string        `json:"name"`
+	Version string        `json:"version"`
+	Commit  string        `json:"commit,omitempty"`
+	Signer  *LockedSigner `json:"signer,omitempty"`
+	Hash    string        `json:"hash"`
+	// Files is the manifest Hash was computed from, so a change can be traced
+	// to the files that were modified, added or deleted.
+	Files FileManifest `json:"files,omitempty"`
+	// Config is the build configuration Artifacts were built with, empty for
+	// DefaultBuildConfig.
+	Config string `json:"config,omitempty"`
+	// Options is the digest of the build options the root package set for the
+	// dependency when it was built, empty for none.
+	Options string `json:"options,omitempty"`
+	// Prebuilt is the SHA-256 of the binary package Artifacts were installed
+	// from, empty when they were compiled.
+	Prebuilt  string              `json:"prebuilt,omitempty"`
 	Artifacts DependencyArtifacts `json:"artifacts"`
-	Failed    bool                `json:"-"`
-	Changed   bool                `json:"-"`
+	// Platforms holds the artifacts of each platform of a multi-platform
+	// build, keyed by platform; Artifacts holds those of single-platform
+	// builds.
+	Platforms map[string]PlatformArtifacts `json:"platforms,omitempty"`
+	Failed    bool                         `json:"-"`
+	Changed   bool                         `json:"-"`
+}
+
+// LockedSigner identifies the trusted key that signed the tag or commit a
+// dependency was installed from.
+type LockedSigner struct {
+	// Object is "tag" or "commit".
+	Object string `json:"object"`
+	// Format is "openpgp" or "ssh".
+	Format      string `json:"format"`
+	Fingerprint string `json:"fingerprint"`
+	Identity    string `json:"identity,omitempty"`
 }
 
 // PackageLock represents the lock file for a package.
@@ -56,11 +129,36 @@ func (p *PackageLock) AddDependency(dep Dependency, version, hash string) {
 	}
 }
 
+// SetCommit records the commit a locked dependency was checked out at.
+func (p *PackageLock) SetCommit(dep Dependency, commit string) {
+	if locked, ok := p.Installed[dep.GetKey()]; ok {
+		locked.Commit = commit
+		p.Installed[dep.GetKey()] = locked
+	}
+}
+
+// SetSigner records the key that signed the installed tag or commit; nil
+// clears a signer recorded for a previous version.
+func (p *PackageLock) SetSigner(dep Dependency, signer *LockedSigner) {
+	if locked, ok := p.Installed[dep.GetKey()]; ok {
+		locked.Signer = signer
+		p.Installed[dep.GetKey()] = locked
+	}
+}
+
 // GetInstalled returns the locked dependency for the given dependency.
 func (p *PackageLock) GetInstalled(dep Dependency) LockedDependency {
 	return p.Installed[dep.GetKey()]
 }
 
+// Snapshot returns a copy of the lock whose Installed map can be read while
+// the original is being updated.
+func (p *PackageLock) Snapshot() PackageLock {
+	snapshot := *p
+	snapshot.Installed = maps.Clone(p.Installed)
+	return snapshot
+}
+
 // SetInstalled sets a locked dependency without performing any I/O operations.
 func (p *PackageLock) SetInstalled(dep Dependency, locked LockedDependency) {
 	p.Installed[dep.GetKey()] = locked
@@ -104,5 +202,38 @@ func (p *LockedDependency) GetArtifacts() []string {
 	result = append(result, p.Artifacts.Dcu...)
 	result = append(result, p.Artifacts.Bin...)
 	result = append(result, p.Artifacts.Bpl...)
+	for _, platform := range p.Platforms {
+		result = append(result, platform.Artifacts.Dcp...)
+		result = append(result, platform.Artifacts.Dcu...)
+		result = append(result, platform.Artifacts.Bin...)
+		result = append(result, platform.Artifacts.Bpl...)
+	}
 	return result
 }
+
+// ArtifactsFor returns the artifacts of a platform of a multi-platform
+// build and the configuration they were built with; for an empty platform,
+// those of single-platform builds.
+func (p *LockedDependency) ArtifactsFor(platform string) PlatformArtifacts {
+	if platform == "" {
+		return PlatformArtifacts{Config: p.Config, Artifacts: p.Artifacts}
+	}
+	return p.Platforms[platform]
+}
+
+// SetArtifactsFor records the artifacts of a platform of a multi-platform
+// build; for an empty platform, those of single-platform builds. Platforms is
+// replaced rather than updated, as copies of the entry share it.
+func (p *LockedDependency) SetArtifactsFor(platform string, artifacts PlatformArtifacts) {
+	if platform == "" {
+		p.Config = artifacts.Config
+		p.Artifacts = artifacts.Artifacts
+		return
+	}
+	platforms := maps.Clone(p.Platforms)
+	if platforms == nil {
+		platforms = map[string]PlatformArtifacts{}
+	}
+	platforms[platform] = artifacts
+	p.Platforms = platforms
+}
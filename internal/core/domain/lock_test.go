@@ -3,6 +3,7 @@ package domain_test
 import (
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -407,3 +408,41 @@ func TestLockedDependency_GetArtifacts_Order(t *testing.T) {
 		}
 	}
 }
+
+func TestPlatformArtifactDir(t *testing.T) {
+	tests := []struct {
+		platform, config, want string
+	}{
+		{"", "Debug", filepath.Join("modules", ".bpl")},
+		{"", "Release", filepath.Join("modules", ".bpl", "Release")},
+		{"Win64", "Debug", filepath.Join("modules", ".bpl", "Win64")},
+		{"Win64", "Release", filepath.Join("modules", ".bpl", "Win64", "Release")},
+	}
+	for _, tt := range tests {
+		if got := domain.PlatformArtifactDir("modules", ".bpl", tt.platform, tt.config); got != tt.want {
+			t.Errorf("PlatformArtifactDir(%q, %q) = %q, want %q", tt.platform, tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestLockedDependency_ArtifactsFor(t *testing.T) {
+	locked := domain.LockedDependency{Artifacts: domain.DependencyArtifacts{Bpl: []string{"Single.bpl"}}}
+	shared := locked
+	locked.SetArtifactsFor("Win64", domain.PlatformArtifacts{
+		Config:    "Release",
+		Artifacts: domain.DependencyArtifacts{Bpl: []string{"Win64.bpl"}},
+	})
+
+	if got := locked.ArtifactsFor("Win64"); got.Config != "Release" || got.Artifacts.Bpl[0] != "Win64.bpl" {
+		t.Errorf("ArtifactsFor(Win64) = %+v", got)
+	}
+	if got := locked.ArtifactsFor(""); got.Artifacts.Bpl[0] != "Single.bpl" {
+		t.Errorf("ArtifactsFor(\"\") = %+v, want the single-platform artifacts", got)
+	}
+	if shared.Platforms != nil {
+		t.Error("SetArtifactsFor() must not modify copies of the entry")
+	}
+	if artifacts := locked.GetArtifacts(); len(artifacts) != 2 {
+		t.Errorf("GetArtifacts() = %v, want the artifacts of every platform", artifacts)
+	}
+}
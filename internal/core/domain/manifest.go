@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// ManifestDigestPrefix marks a lock hash computed from a file manifest.
+const ManifestDigestPrefix = "sha256:"
+
+// FileManifest maps the slash-separated path of every file of an installed
+// module, relative to the module directory, to its SHA-256 digest.
+type FileManifest map[string]string
+
+// Digest returns a single SHA-256 over the manifest, used as the lock hash so
+// an unchanged module is recognized without comparing every entry.
+func (m FileManifest) Digest() string {
+	hasher := sha256.New()
+	for _, path := range m.paths() {
+		hasher.Write([]byte(path))
+		hasher.Write([]byte{0})
+		hasher.Write([]byte(m[path]))
+		hasher.Write([]byte{'\n'})
+	}
+	return ManifestDigestPrefix + hex.EncodeToString(hasher.Sum(nil))
+}
+
+func (m FileManifest) paths() []string {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// ManifestChanges lists the files that differ between two manifests.
+type ManifestChanges struct {
+	Modified []string `json:"modified,omitempty"`
+	Added    []string `json:"added,omitempty"`
+	Deleted  []string `json:"deleted,omitempty"`
+}
+
+// Empty reports whether the manifests are identical.
+func (c ManifestChanges) Empty() bool {
+	return len(c.Modified)+len(c.Added)+len(c.Deleted) == 0
+}
+
+// Compare returns what changed from m, the recorded manifest, to current.
+func (m FileManifest) Compare(current FileManifest) ManifestChanges {
+	var changes ManifestChanges
+	for _, path := range m.paths() {
+		digest, ok := current[path]
+		switch {
+		case !ok:
+			changes.Deleted = append(changes.Deleted, path)
+		case digest != m[path]:
+			changes.Modified = append(changes.Modified, path)
+		}
+	}
+	for _, path := range current.paths() {
+		if _, ok := m[path]; !ok {
+			changes.Added = append(changes.Added, path)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+func TestFileManifest_Digest(t *testing.T) {
+	manifest := domain.FileManifest{"a.pas": "01", "b.pas": "02"}
+	digest := manifest.Digest()
+	if !strings.HasPrefix(digest, domain.ManifestDigestPrefix) {
+		t.Errorf("Digest() = %q, want the %q prefix", digest, domain.ManifestDigestPrefix)
+	}
+	if same := (domain.FileManifest{"b.pas": "02", "a.pas": "01"}).Digest(); same != digest {
+		t.Error("Digest() should not depend on map order")
+	}
+	if renamed := (domain.FileManifest{"c.pas": "01", "b.pas": "02"}).Digest(); renamed == digest {
+		t.Error("Digest() should change when a file is renamed")
+	}
+}
+
+func TestFileManifest_Compare(t *testing.T) {
+	locked := domain.FileManifest{"same.pas": "1", "edited.pas": "2", "gone.pas": "3"}
+	current := domain.FileManifest{"same.pas": "1", "edited.pas": "9", "new.pas": "4"}
+
+	got := locked.Compare(current)
+	want := domain.ManifestChanges{
+		Modified: []string{"edited.pas"},
+		Added:    []string{"new.pas"},
+		Deleted:  []string{"gone.pas"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() = %+v, want %+v", got, want)
+	}
+	if got.Empty() || !locked.Compare(locked).Empty() {
+		t.Error("Empty() should only hold for identical manifests")
+	}
+}
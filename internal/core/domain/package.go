@@ -3,25 +3,35 @@
 package domain
 
 import (
+	"slices"
 	"strings"
+
+	"github.com/hashload/boss/pkg/env"
 )
 
 // Package represents the boss.json file structure.
 // This is a pure domain entity containing only business data and logic.
 // Use PackageRepository (ports.PackageRepository) for persistence operations.
 type Package struct {
-	Name         string            `json:"name"`
-	Description  string            `json:"description"`
-	Version      string            `json:"version"`
-	Homepage     string            `json:"homepage"`
-	MainSrc      string            `json:"mainsrc"`
-	BrowsingPath string            `json:"browsingpath"`
-	Projects     []string          `json:"projects"`
-	Scripts      map[string]string `json:"scripts,omitempty"`
-	Dependencies map[string]string `json:"dependencies"`
-	Engines      *PackageEngines   `json:"engines,omitempty"`
-	Toolchain    *PackageToolchain `json:"toolchain,omitempty"`
-	Lock         PackageLock       `json:"-"`
+	Name         string                `json:"name"`
+	Description  string                `json:"description"`
+	Version      string                `json:"version"`
+	Homepage     string                `json:"homepage"`
+	License      string                `json:"license,omitempty"`
+	MainSrc      string                `json:"mainsrc"`
+	BrowsingPath string                `json:"browsingpath"`
+	Projects     []string              `json:"projects"`
+	Scripts      map[string]string     `json:"scripts,omitempty"`
+	Dependencies map[string]string     `json:"dependencies"`
+	Engines      *PackageEngines       `json:"engines,omitempty"`
+	Binaries     []PackageBinary       `json:"binaries,omitempty"`
+	Build        *PackageBuild         `json:"build,omitempty"`
+	Fpc          *PackageFpc           `json:"fpc,omitempty"`
+	Toolchain    *PackageToolchain     `json:"toolchain,omitempty"`
+	Cycles       *PackageCycles        `json:"cycles,omitempty"`
+	Licenses     *PackageLicensePolicy `json:"licensePolicy,omitempty"`
+	Trust        *env.TrustConfig      `json:"trust,omitempty"`
+	Lock         PackageLock           `json:"-"`
 }
 
 // PackageEngines represents the engines configuration in boss.json.
@@ -30,12 +40,125 @@ type PackageEngines struct {
 	Platforms []string `json:"platforms,omitempty"`
 }
 
+// PackageBinary is a prebuilt binary package of a release, installed instead
+// of compiling Projects when the compiler, platform and configuration match.
+type PackageBinary struct {
+	// Compiler is the compiler version ("37.0") the binaries were built with.
+	Compiler string `json:"compiler"`
+	Platform string `json:"platform"`
+	// Config is the build configuration; empty matches any.
+	Config string `json:"config,omitempty"`
+	// URL locates the archive made by 'boss pack --binary': an http(s) URL or
+	// a path relative to the package directory.
+	URL string `json:"url"`
+	// SHA256 is the hex digest of the archive, checked before it is used.
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// PackageBuild holds the compiler inputs of the projects of a package, passed
+// to dcc through the generated boss.cfg and to FPC through lazbuild.
+type PackageBuild struct {
+	// Defines are conditional symbols, such as USE_FIREDAC.
+	Defines []string `json:"defines,omitempty"`
+	// UnitScopes are unit scope names, such as System or Vcl.
+	UnitScopes       []string `json:"unitScopes,omitempty"`
+	WarningsAsErrors bool     `json:"warningsAsErrors,omitempty"`
+	// DccFlags and FpcFlags are passed as they are to dcc and to FPC.
+	DccFlags []string `json:"dccFlags,omitempty"`
+	FpcFlags []string `json:"fpcFlags,omitempty"`
+	// Dependencies adds settings to the builds of dependencies, keyed by
+	// repository, a suffix of it or "*" for all of them. Only the root
+	// package's are read.
+	Dependencies map[string]*PackageBuild `json:"dependencies,omitempty"`
+}
+
+// Merge returns the settings of b followed by those of other, without
+// Dependencies; either may be nil, and nil is returned when both are empty.
+func (b *PackageBuild) Merge(other *PackageBuild) *PackageBuild {
+	merged := &PackageBuild{}
+	for _, part := range []*PackageBuild{b, other} {
+		if part == nil {
+			continue
+		}
+		merged.Defines = append(merged.Defines, part.Defines...)
+		merged.UnitScopes = append(merged.UnitScopes, part.UnitScopes...)
+		merged.WarningsAsErrors = merged.WarningsAsErrors || part.WarningsAsErrors
+		merged.DccFlags = append(merged.DccFlags, part.DccFlags...)
+		merged.FpcFlags = append(merged.FpcFlags, part.FpcFlags...)
+	}
+	if len(merged.Defines) == 0 && len(merged.UnitScopes) == 0 && !merged.WarningsAsErrors &&
+		len(merged.DccFlags) == 0 && len(merged.FpcFlags) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// PackageFpc declares what fpc compiles for a package without Lazarus: a main
+// program, a list of units, or both. Paths are relative to the package.
+type PackageFpc struct {
+	Program string   `json:"program,omitempty"`
+	Units   []string `json:"units,omitempty"`
+}
+
+// Targets returns the files fpc compiles, the program last so it finds the
+// units already compiled.
+func (f *PackageFpc) Targets() []string {
+	if f == nil {
+		return nil
+	}
+	targets := slices.Clone(f.Units)
+	if f.Program != "" {
+		targets = append(targets, f.Program)
+	}
+	return targets
+}
+
+// PackageCycles says how to build dependencies that depend on each other,
+// which fail the build unless the cycle goes through an edge of Break. Only
+// the root package's are read.
+type PackageCycles struct {
+	// Break lists the edges left out of the build order.
+	Break []PackageEdge `json:"break,omitempty"`
+}
+
+// PackageEdge is the dependency on To declared in the boss.json of From, both
+// repositories, suffixes of them or module folders.
+type PackageEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
 // PackageToolchain represents the toolchain configuration in boss.json.
 type PackageToolchain struct {
 	Compiler string `json:"compiler,omitempty"`
 	Platform string `json:"platform,omitempty"`
 	Path     string `json:"path,omitempty"`
 	Strict   bool   `json:"strict,omitempty"`
+	// Config is the build configuration (Debug, Release, ...) of the build.
+	Config string `json:"config,omitempty"`
+	// Configs overrides the configuration a dependency is compiled with,
+	// keyed by repository. Its artifacts still go to the folders of Config.
+	Configs map[string]string `json:"configs,omitempty"`
+	// Command builds the projects with a command of its own instead of the
+	// Delphi or Free Pascal compilers.
+	Command *PackageCommand `json:"command,omitempty"`
+}
+
+// PackageCommand is the command template that builds a project. Run is a
+// shell command where {project}, {dir}, {name}, {module}, {config},
+// {platform}, {compiler}, {bpl}, {dcp}, {dcu}, {bin}, {defines},
+// {unitscopes} and {searchpath} are replaced; Extensions limits it to the
+// projects with those extensions, all of them when empty.
+type PackageCommand struct {
+	Run        string   `json:"run"`
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// PackageLicensePolicy lists the SPDX license identifiers a project accepts
+// or rejects in its dependencies.
+type PackageLicensePolicy struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
 }
 
 // NewPackage creates a new Package with initialized collections.
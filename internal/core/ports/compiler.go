@@ -21,8 +21,8 @@ type ArtifactManager interface {
 	// EnsureArtifacts collects artifacts for a locked dependency.
 	EnsureArtifacts(lockedDependency *domain.LockedDependency, dep domain.Dependency, rootPath string)
 
-	// MoveArtifacts moves artifacts to the shared folder.
-	MoveArtifacts(dep domain.Dependency, rootPath string)
+	// MoveArtifacts moves artifacts to the shared folders of a build configuration.
+	MoveArtifacts(dep domain.Dependency, rootPath, config string)
 
 	// CollectArtifacts collects artifact files from a path.
 	CollectArtifacts(artifactList []string, path string) []string
@@ -0,0 +1,213 @@
+// Package audit matches locked dependencies against a local advisory database
+// in OSV (Open Source Vulnerability) JSON format.
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/utils"
+)
+
+// Advisory is the subset of the OSV schema that Boss reads.
+// See https://ossf.github.io/osv-schema/ for the full format.
+type Advisory struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases,omitempty"`
+	Summary          string           `json:"summary,omitempty"`
+	Details          string           `json:"details,omitempty"`
+	Modified         string           `json:"modified,omitempty"`
+	Published        string           `json:"published,omitempty"`
+	Withdrawn        string           `json:"withdrawn,omitempty"`
+	Severity         []SeverityScore  `json:"severity,omitempty"`
+	Affected         []Affected       `json:"affected,omitempty"`
+	References       []Reference      `json:"references,omitempty"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific,omitzero"`
+}
+
+// SeverityScore is a scored severity entry, such as a CVSS vector.
+type SeverityScore struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Affected describes one affected package and the versions concerned.
+type Affected struct {
+	Package          AffectedPackage  `json:"package"`
+	Ranges           []Range          `json:"ranges,omitempty"`
+	Versions         []string         `json:"versions,omitempty"`
+	Severity         []SeverityScore  `json:"severity,omitempty"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific,omitzero"`
+}
+
+// AffectedPackage identifies a package. Boss matches on repositories, so
+// Name is expected to be a repository path (github.com/owner/repo) or the
+// Purl a pkg:github/pkg:bitbucket/pkg:gitlab URL.
+type AffectedPackage struct {
+	Ecosystem string `json:"ecosystem,omitempty"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl,omitempty"`
+}
+
+// Range is a list of version events. SEMVER and ECOSYSTEM ranges are
+// evaluated against the locked version; GIT ranges only contribute their repo.
+type Range struct {
+	Type   string  `json:"type"`
+	Repo   string  `json:"repo,omitempty"`
+	Events []Event `json:"events"`
+}
+
+// Event is a single introduced/fixed/last_affected/limit boundary.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Reference is a link attached to an advisory.
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// DatabaseSpecific holds the qualitative severity most databases publish
+// (GitHub advisories use LOW, MODERATE, HIGH and CRITICAL).
+type DatabaseSpecific struct {
+	Severity string `json:"severity,omitempty"`
+}
+
+// ErrDatabaseMissing is returned when the advisory database has never been fetched.
+var ErrDatabaseMissing = errors.New("advisory database not found")
+
+// ParseAdvisories decodes a single OSV advisory or an array of advisories.
+func ParseAdvisories(data []byte) ([]Advisory, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var advisories []Advisory
+		if err := json.Unmarshal(data, &advisories); err != nil {
+			return nil, err
+		}
+		return advisories, nil
+	}
+
+	var advisory Advisory
+	if err := json.Unmarshal(data, &advisory); err != nil {
+		return nil, err
+	}
+	if advisory.ID == "" {
+		return nil, errors.New("advisory without an id")
+	}
+	return []Advisory{advisory}, nil
+}
+
+// LoadDatabase reads every *.json advisory below dir. Withdrawn advisories are skipped.
+func LoadDatabase(dir string) ([]Advisory, error) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%w at %s", ErrDatabaseMissing, dir)
+	}
+
+	var advisories []Advisory
+	err = filepath.WalkDir(dir, func(path string, entry os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return nil
+		}
+
+		data, readErr := os.ReadFile(path) // #nosec G304 -- Reading advisories from the Boss advisory directory
+		if readErr != nil {
+			return readErr
+		}
+		parsed, parseErr := ParseAdvisories(data)
+		if parseErr != nil {
+			return fmt.Errorf("invalid advisory %s: %w", filepath.Base(path), parseErr)
+		}
+		for _, advisory := range parsed {
+			if advisory.Withdrawn == "" {
+				advisories = append(advisories, advisory)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(advisories, func(i, j int) bool {
+		return advisories[i].ID < advisories[j].ID
+	})
+	return advisories, nil
+}
+
+// repositories returns the normalized repositories an affected entry applies to.
+func (a Affected) repositories() []string {
+	var repos []string
+	add := func(raw string) {
+		if repo := domain.NormalizeRepository(raw); repo != "" && !utils.Contains(repos, repo) {
+			repos = append(repos, repo)
+		}
+	}
+
+	if strings.Contains(a.Package.Name, "/") {
+		add(a.Package.Name)
+	}
+	add(repositoryFromPurl(a.Package.Purl))
+	for _, r := range a.Ranges {
+		add(r.Repo)
+	}
+	return repos
+}
+
+// purlHosts maps purl types that name a forge to the forge host.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var purlHosts = map[string]string{
+	"github":    "github.com",
+	"gitlab":    "gitlab.com",
+	"bitbucket": "bitbucket.org",
+}
+
+// repositoryFromPurl turns pkg:github/owner/repo@1.0 into github.com/owner/repo.
+// Generic purls are resolved through their vcs_url qualifier.
+func repositoryFromPurl(purl string) string {
+	rest, ok := strings.CutPrefix(strings.ToLower(purl), "pkg:")
+	if !ok {
+		return ""
+	}
+
+	var qualifiers string
+	if idx := strings.Index(rest, "?"); idx >= 0 {
+		rest, qualifiers = rest[:idx], rest[idx+1:]
+	}
+	if idx := strings.Index(rest, "@"); idx >= 0 {
+		rest = rest[:idx]
+	}
+
+	purlType, path, found := strings.Cut(rest, "/")
+	if !found {
+		return ""
+	}
+	if host, known := purlHosts[purlType]; known {
+		return host + "/" + path
+	}
+
+	for _, qualifier := range strings.Split(qualifiers, "&") {
+		if value, isVcs := strings.CutPrefix(qualifier, "vcs_url="); isVcs {
+			if decoded, err := url.QueryUnescape(value); err == nil {
+				value = decoded
+			}
+			return strings.TrimPrefix(value, "git+")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,287 @@
+//nolint:testpackage // Testing internal implementation details
+package audit
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+const horseAdvisory = `{
+	"id": "BOSS-2024-0001",
+	"aliases": ["CVE-2024-0001"],
+	"summary": "Path traversal in static file middleware",
+	"affected": [{
+		"package": {"ecosystem": "GIT", "name": "github.com/HashLoad/horse"},
+		"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "3.1.0"}]}]
+	}],
+	"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+	"references": [{"type": "ADVISORY", "url": "https://example.com/BOSS-2024-0001"}]
+}`
+
+func lockWith(entries map[string]string) *domain.PackageLock {
+	lock := &domain.PackageLock{Installed: map[string]domain.LockedDependency{}}
+	for key, version := range entries {
+		lock.Installed[key] = domain.LockedDependency{Name: filepath.Base(key), Version: version}
+	}
+	return lock
+}
+
+func parseOne(t *testing.T, data string) Advisory {
+	t.Helper()
+	advisories, err := ParseAdvisories([]byte(data))
+	if err != nil || len(advisories) != 1 {
+		t.Fatalf("ParseAdvisories() = %v, %v", advisories, err)
+	}
+	return advisories[0]
+}
+
+func TestRepositoryFromPurl(t *testing.T) {
+	tests := map[string]string{
+		"pkg:github/hashload/horse@3.0.0": "github.com/hashload/horse",
+		"pkg:bitbucket/acme/lib":          "bitbucket.org/acme/lib",
+		"pkg:generic/lib?vcs_url=git%2Bhttps%3A%2F%2Fgit.acme.com%2Fteam%2Flib": "https://git.acme.com/team/lib",
+		"pkg:npm/left-pad@1.0.0": "",
+	}
+	for input, want := range tests {
+		if got := repositoryFromPurl(input); got != want {
+			t.Errorf("repositoryFromPurl(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestAudit_MatchesSemverRange(t *testing.T) {
+	advisory := parseOne(t, horseAdvisory)
+	lock := lockWith(map[string]string{
+		"github.com/hashload/horse":   "v3.0.9",
+		"github.com/hashload/jhonson": "1.0.0",
+	})
+
+	report := Audit(lock, []Advisory{advisory})
+
+	if report.Dependencies != 2 || report.Advisories != 1 {
+		t.Errorf("counts = %d deps, %d advisories", report.Dependencies, report.Advisories)
+	}
+	if len(report.Findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d", len(report.Findings))
+	}
+	finding := report.Findings[0]
+	if finding.Repository != "github.com/hashload/horse" || finding.AdvisoryID != "BOSS-2024-0001" {
+		t.Errorf("unexpected finding %+v", finding)
+	}
+	if finding.Severity != SeverityCritical || finding.Score != 9.8 {
+		t.Errorf("severity = %s %.1f, want critical 9.8", finding.Severity, finding.Score)
+	}
+	if len(finding.FixedIn) != 1 || finding.FixedIn[0] != "3.1.0" {
+		t.Errorf("FixedIn = %v", finding.FixedIn)
+	}
+}
+
+func TestAudit_FixedVersionIsNotAffected(t *testing.T) {
+	advisory := parseOne(t, horseAdvisory)
+
+	for _, version := range []string{"3.1.0", "v3.2.0"} {
+		report := Audit(lockWith(map[string]string{"github.com/hashload/horse": version}), []Advisory{advisory})
+		if len(report.Findings) != 0 {
+			t.Errorf("version %s should not be affected", version)
+		}
+	}
+}
+
+func TestRange_Contains(t *testing.T) {
+	r := Range{Type: "SEMVER", Events: []Event{
+		{Introduced: "1.0.0"}, {LastAffected: "1.4.2"},
+		{Introduced: "2.0.0"}, {Fixed: "2.1.0"},
+	}}
+	tests := map[string]bool{
+		"0.9.0": false,
+		"1.0.0": true,
+		"1.4.2": true,
+		"1.5.0": false,
+		"2.0.5": true,
+		"2.1.0": false,
+	}
+	for version, want := range tests {
+		advisory := Affected{Ranges: []Range{r}}
+		parsed := mustVersion(t, version)
+		if got := advisory.affects(version, parsed); got != want {
+			t.Errorf("affects(%s) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestAudit_ExplicitVersionsAndUnchecked(t *testing.T) {
+	advisory := parseOne(t, `{
+		"id": "BOSS-2024-0002",
+		"affected": [{
+			"package": {"name": "lib", "purl": "pkg:github/acme/lib"},
+			"versions": ["release-2020"],
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+		}],
+		"database_specific": {"severity": "MODERATE"}
+	}`)
+
+	report := Audit(lockWith(map[string]string{"github.com/acme/lib": "release-2020"}), []Advisory{advisory})
+
+	if len(report.Findings) != 1 || report.Findings[0].Severity != SeverityMedium {
+		t.Fatalf("expected one medium finding, got %+v", report.Findings)
+	}
+	if len(report.Unchecked) != 1 {
+		t.Errorf("expected the non-semver version to be reported as unchecked, got %v", report.Unchecked)
+	}
+}
+
+func TestCVSS3BaseScore(t *testing.T) {
+	tests := map[string]float64{
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H": 9.8,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H": 10.0,
+		"CVSS:3.1/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N": 5.4,
+		"CVSS:3.0/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N": 1.8,
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N": 0,
+	}
+	for vector, want := range tests {
+		got, ok := CVSS3BaseScore(vector)
+		if !ok || got != want {
+			t.Errorf("CVSS3BaseScore(%s) = %v, %v; want %v", vector, got, ok, want)
+		}
+	}
+
+	if _, ok := CVSS3BaseScore("CVSS:3.1/AV:X"); ok {
+		t.Error("expected an invalid vector to be rejected")
+	}
+}
+
+func TestSeverity_AtLeast(t *testing.T) {
+	if !SeverityHigh.AtLeast(SeverityMedium) || SeverityLow.AtLeast(SeverityMedium) {
+		t.Error("unexpected severity ordering")
+	}
+	if SeverityCritical.AtLeast(SeverityNone) {
+		t.Error("threshold none must disable the check")
+	}
+	if !SeverityUnknown.AtLeast(SeverityHigh) {
+		t.Error("unknown severity must not slip past a high threshold")
+	}
+	if _, err := ParseSeverity("severe"); err == nil {
+		t.Error("expected an error for an unknown severity name")
+	}
+}
+
+func TestRefresh_FromDirectoryAndLoad(t *testing.T) {
+	source := t.TempDir()
+	writeFile(t, filepath.Join(source, "a.json"), horseAdvisory)
+	writeFile(t, filepath.Join(source, "nested", "b.json"),
+		`[{"id": "BOSS-2", "affected": []}, {"id": "BOSS-3", "withdrawn": "2024-01-01T00:00:00Z"}]`)
+	writeFile(t, filepath.Join(source, "README.md"), "not an advisory")
+
+	dbDir := filepath.Join(t.TempDir(), "advisories")
+	count, err := Refresh(source, dbDir)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Refresh() stored %d advisories, want 3", count)
+	}
+
+	advisories, err := LoadDatabase(dbDir)
+	if err != nil {
+		t.Fatalf("LoadDatabase() error = %v", err)
+	}
+	if len(advisories) != 2 {
+		t.Errorf("expected the withdrawn advisory to be skipped, got %d advisories", len(advisories))
+	}
+}
+
+func TestRefresh_FromZipArchive(t *testing.T) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	entry, err := archive.Create("BOSS-2024-0001.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = entry.Write([]byte(horseAdvisory)); err != nil {
+		t.Fatal(err)
+	}
+	if err = archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	source := filepath.Join(t.TempDir(), "all.zip")
+	writeFile(t, source, buf.String())
+
+	dbDir := filepath.Join(t.TempDir(), "advisories")
+	if count, refreshErr := Refresh(source, dbDir); refreshErr != nil || count != 1 {
+		t.Fatalf("Refresh() = %d, %v", count, refreshErr)
+	}
+}
+
+func TestRefresh_KeepsDatabaseOnInvalidSource(t *testing.T) {
+	dbDir := t.TempDir()
+	writeFile(t, filepath.Join(dbDir, "keep.json"), horseAdvisory)
+
+	source := filepath.Join(t.TempDir(), "broken.json")
+	writeFile(t, source, "{not json")
+
+	if _, err := Refresh(source, dbDir); err == nil {
+		t.Fatal("expected an error for an invalid source")
+	}
+	if _, err := os.Stat(filepath.Join(dbDir, "keep.json")); err != nil {
+		t.Error("existing database must survive a failed refresh")
+	}
+}
+
+func TestLoadDatabase_Missing(t *testing.T) {
+	if _, err := LoadDatabase(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing database")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	report := Audit(lockWith(map[string]string{"github.com/hashload/horse": "3.0.0"}),
+		[]Advisory{parseOne(t, horseAdvisory)})
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, report, "boss-lock.json"); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Results) != 1 || run.Results[0].Level != "error" || len(run.Tool.Driver.Rules) != 1 {
+		t.Errorf("unexpected SARIF run %+v", run)
+	}
+	if !strings.Contains(run.Results[0].Message.Text, "CVE-2024-0001") {
+		t.Errorf("message should mention the alias: %s", run.Results[0].Message.Text)
+	}
+}
+
+func mustVersion(t *testing.T, version string) *semver.Version {
+	t.Helper()
+	parsed, err := semver.NewVersion(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
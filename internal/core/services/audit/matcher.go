@@ -0,0 +1,202 @@
+package audit
+
+import (
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+// Finding is one advisory affecting one locked dependency.
+type Finding struct {
+	Repository string   `json:"repository"`
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	AdvisoryID string   `json:"advisory"`
+	Aliases    []string `json:"aliases,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Severity   Severity `json:"severity"`
+	Score      float64  `json:"score,omitempty"`
+	FixedIn    []string `json:"fixedIn,omitempty"`
+	References []string `json:"references,omitempty"`
+}
+
+// Report is the outcome of auditing a lock file.
+type Report struct {
+	Advisories   int       `json:"advisories"`
+	Dependencies int       `json:"dependencies"`
+	Findings     []Finding `json:"findings"`
+	// Unchecked lists dependencies locked to a non-semantic version (a branch
+	// name, for instance) that version ranges cannot be evaluated against.
+	Unchecked []string `json:"unchecked,omitempty"`
+}
+
+// Failing returns the findings at or above threshold.
+func (r *Report) Failing(threshold Severity) []Finding {
+	var failing []Finding
+	for _, finding := range r.Findings {
+		if finding.Severity.AtLeast(threshold) {
+			failing = append(failing, finding)
+		}
+	}
+	return failing
+}
+
+// Audit matches every installed lock entry against the advisories.
+// Findings are sorted by severity, most severe first.
+func Audit(lock *domain.PackageLock, advisories []Advisory) *Report {
+	report := &Report{Advisories: len(advisories), Findings: []Finding{}}
+	if lock == nil {
+		return report
+	}
+
+	byRepo := indexAdvisories(advisories)
+	for key, locked := range lock.Installed {
+		report.Dependencies++
+		repo := domain.NormalizeRepository(key)
+		version, versionErr := semver.NewVersion(domain.StripVersionPrefix(locked.Version))
+
+		candidates := byRepo[repo]
+		if len(candidates) > 0 && versionErr != nil {
+			report.Unchecked = append(report.Unchecked, repo+"@"+locked.Version)
+		}
+
+		for _, candidate := range candidates {
+			if !candidate.affected.affects(locked.Version, version) {
+				continue
+			}
+			report.Findings = append(report.Findings, newFinding(repo, locked, candidate))
+		}
+	}
+
+	sort.Strings(report.Unchecked)
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Severity.Rank() != b.Severity.Rank() {
+			return a.Severity.Rank() > b.Severity.Rank()
+		}
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		return a.AdvisoryID < b.AdvisoryID
+	})
+	return report
+}
+
+// candidate pairs an advisory with the affected entry that matched a repository.
+type candidate struct {
+	advisory Advisory
+	affected Affected
+}
+
+func indexAdvisories(advisories []Advisory) map[string][]candidate {
+	byRepo := map[string][]candidate{}
+	for _, advisory := range advisories {
+		for _, affected := range advisory.Affected {
+			for _, repo := range affected.repositories() {
+				byRepo[repo] = append(byRepo[repo], candidate{advisory: advisory, affected: affected})
+			}
+		}
+	}
+	return byRepo
+}
+
+func newFinding(repo string, locked domain.LockedDependency, c candidate) Finding {
+	severity, score := severityOf(c.advisory, c.affected)
+	finding := Finding{
+		Repository: repo,
+		Name:       locked.Name,
+		Version:    locked.Version,
+		AdvisoryID: c.advisory.ID,
+		Aliases:    c.advisory.Aliases,
+		Summary:    c.advisory.Summary,
+		Severity:   severity,
+		Score:      score,
+		FixedIn:    c.affected.fixedVersions(),
+	}
+	for _, reference := range c.advisory.References {
+		finding.References = append(finding.References, reference.URL)
+	}
+	return finding
+}
+
+// affects reports whether the locked version is affected. The explicit
+// versions list is compared textually so that tags which are not valid
+// semver still match; ranges need a parsed version.
+func (a Affected) affects(raw string, version *semver.Version) bool {
+	stripped := domain.StripVersionPrefix(raw)
+	for _, listed := range a.Versions {
+		if listed == raw || domain.StripVersionPrefix(listed) == stripped {
+			return true
+		}
+	}
+
+	if version == nil {
+		return false
+	}
+	for _, r := range a.Ranges {
+		if (r.Type == "SEMVER" || r.Type == "ECOSYSTEM") && r.contains(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains evaluates the range events in version order, as the OSV
+// specification describes: an introduced event opens the range and a fixed or
+// last_affected event closes it.
+func (r Range) contains(version *semver.Version) bool {
+	type boundary struct {
+		at    *semver.Version
+		event Event
+	}
+
+	var boundaries []boundary
+	for _, event := range r.Events {
+		raw := event.Introduced + event.Fixed + event.LastAffected
+		if raw == "" {
+			continue
+		}
+		if raw == "0" {
+			boundaries = append(boundaries, boundary{at: semver.New(0, 0, 0, "", ""), event: event})
+			continue
+		}
+		at, err := semver.NewVersion(domain.StripVersionPrefix(raw))
+		if err != nil {
+			continue
+		}
+		boundaries = append(boundaries, boundary{at: at, event: event})
+	}
+	sort.SliceStable(boundaries, func(i, j int) bool {
+		return boundaries[i].at.LessThan(boundaries[j].at)
+	})
+
+	affected := false
+	for _, b := range boundaries {
+		switch {
+		case b.event.Introduced != "" && !version.LessThan(b.at):
+			affected = true
+		case b.event.Fixed != "" && !version.LessThan(b.at):
+			affected = false
+		case b.event.LastAffected != "" && version.GreaterThan(b.at):
+			affected = false
+		}
+	}
+	return affected
+}
+
+// fixedVersions lists the versions in which the advisory is fixed.
+func (a Affected) fixedVersions() []string {
+	var fixed []string
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+		for _, event := range r.Events {
+			if event.Fixed != "" {
+				fixed = append(fixed, event.Fixed)
+			}
+		}
+	}
+	return fixed
+}
@@ -0,0 +1,181 @@
+package audit
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// downloadTimeout bounds the advisory download; OSV ecosystem dumps are
+	// tens of megabytes.
+	downloadTimeout = 5 * time.Minute
+	// maxDownloadSize guards against a misconfigured source streaming forever.
+	maxDownloadSize = 512 << 20
+)
+
+// unsafeFileChars matches characters that are not kept in advisory file names.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Refresh replaces the advisory database in dbDir with the advisories found
+// at source. The source is an http(s) URL serving an OSV JSON document, a JSON
+// array or a zip archive of advisories (the format of the OSV ecosystem dumps),
+// or a local directory, .json or .zip file. It returns the number of
+// advisories stored. The previous database is left untouched on failure.
+func Refresh(source, dbDir string) (int, error) {
+	if strings.TrimSpace(source) == "" {
+		return 0, errors.New("no advisory source configured")
+	}
+
+	advisories, err := readSource(source)
+	if err != nil {
+		return 0, err
+	}
+	if len(advisories) == 0 {
+		return 0, fmt.Errorf("no advisories found in %s", source)
+	}
+
+	staging := dbDir + ".tmp"
+	if err := os.RemoveAll(staging); err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(staging, 0755); err != nil { // #nosec G301 -- Standard permissions for Boss data directory
+		return 0, err
+	}
+	for id, data := range advisories {
+		name := unsafeFileChars.ReplaceAllString(id, "_") + ".json"
+		if err := os.WriteFile(filepath.Join(staging, name), data, 0600); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := os.RemoveAll(dbDir); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(staging, dbDir); err != nil {
+		return 0, err
+	}
+	return len(advisories), nil
+}
+
+// readSource collects the advisories of a source keyed by advisory id.
+func readSource(source string) (map[string][]byte, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err := download(source)
+		if err != nil {
+			return nil, err
+		}
+		return splitDocument(data)
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(source) // #nosec G304 -- Reading the advisory source chosen by the user
+		if err != nil {
+			return nil, err
+		}
+		return splitDocument(data)
+	}
+
+	advisories := map[string][]byte{}
+	err = filepath.WalkDir(source, func(path string, entry os.DirEntry, walkErr error) error {
+		if walkErr != nil || entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".json") {
+			return walkErr
+		}
+		data, readErr := os.ReadFile(path) // #nosec G304 -- Reading the advisory source chosen by the user
+		if readErr != nil {
+			return readErr
+		}
+		return addDocument(advisories, data, path)
+	})
+	return advisories, err
+}
+
+func download(source string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", source, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize))
+}
+
+// splitDocument reads a zip archive or a JSON document into advisories.
+func splitDocument(data []byte) (map[string][]byte, error) {
+	advisories := map[string][]byte{}
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return advisories, addDocument(advisories, data, "source")
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(file.Name), ".json") {
+			continue
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := addDocument(advisories, content, file.Name); err != nil {
+			return nil, err
+		}
+	}
+	return advisories, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return io.ReadAll(io.LimitReader(reader, maxDownloadSize))
+}
+
+// addDocument validates a JSON document and stores each advisory it holds.
+// Documents are re-encoded one advisory per file, so arrays are split up.
+func addDocument(advisories map[string][]byte, data []byte, origin string) error {
+	parsed, err := ParseAdvisories(data)
+	if err != nil {
+		return fmt.Errorf("invalid advisory %s: %w", origin, err)
+	}
+	if len(parsed) == 1 {
+		advisories[parsed[0].ID] = data
+		return nil
+	}
+	for _, advisory := range parsed {
+		encoded, err := json.Marshal(advisory)
+		if err != nil {
+			return err
+		}
+		advisories[advisory.ID] = encoded
+	}
+	return nil
+}
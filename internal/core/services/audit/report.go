@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	sarifSchema    = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion   = "2.1.0"
+	toolName       = "boss audit"
+	toolInfoURI    = "https://github.com/HashLoad/boss"
+	osvAdvisoryURI = "https://osv.dev/vulnerability/"
+)
+
+// WriteJSON writes the report as indented JSON.
+func WriteJSON(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string         `json:"id"`
+	ShortDescription sarifText      `json:"shortDescription"`
+	HelpURI          string         `json:"helpUri,omitempty"`
+	Properties       map[string]any `json:"properties,omitempty"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log. Every result points at
+// lockFile, the file that pins the vulnerable version.
+func WriteSARIF(w io.Writer, report *Report, lockFile string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			InformationURI: toolInfoURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	seen := map[string]bool{}
+	for _, finding := range report.Findings {
+		if !seen[finding.AdvisoryID] {
+			seen[finding.AdvisoryID] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleFor(finding))
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:  finding.AdvisoryID,
+			Level:   sarifLevel(finding.Severity),
+			Message: sarifText{Text: FindingMessage(finding)},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: lockFile}},
+			}},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+func sarifRuleFor(finding Finding) sarifRule {
+	description := finding.Summary
+	if description == "" {
+		description = finding.AdvisoryID
+	}
+	properties := map[string]any{"tags": []string{"security", "vulnerability"}}
+	if finding.Score > 0 {
+		// GitHub code scanning ranks alerts by this property.
+		properties["security-severity"] = fmt.Sprintf("%.1f", finding.Score)
+	}
+	return sarifRule{
+		ID:               finding.AdvisoryID,
+		ShortDescription: sarifText{Text: description},
+		HelpURI:          osvAdvisoryURI + finding.AdvisoryID,
+		Properties:       properties,
+	}
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityCritical, SeverityHigh, SeverityUnknown:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	case SeverityNone, SeverityLow:
+		return "note"
+	}
+	return "note"
+}
+
+// FindingMessage describes a finding in one line.
+func FindingMessage(finding Finding) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s@%s is affected by %s", finding.Repository, finding.Version, finding.AdvisoryID)
+	if len(finding.Aliases) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(finding.Aliases, ", "))
+	}
+	if finding.Summary != "" {
+		fmt.Fprintf(&b, ": %s", finding.Summary)
+	}
+	if len(finding.FixedIn) > 0 {
+		fmt.Fprintf(&b, "; fixed in %s", strings.Join(finding.FixedIn, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Severity is the qualitative severity of a finding.
+type Severity string
+
+// Severity levels, from the least to the most severe.
+const (
+	SeverityNone     Severity = "none"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+	// SeverityUnknown is used when an advisory carries no usable score.
+	SeverityUnknown Severity = "unknown"
+)
+
+// Rank orders severities for threshold comparisons. An unknown severity ranks
+// as high so that an advisory without a score never slips past a gate.
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityNone:
+		return 0
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh, SeverityUnknown:
+		return 3
+	case SeverityCritical:
+		return 4
+	}
+	return 0
+}
+
+// AtLeast reports whether s is as severe as threshold.
+// A threshold of SeverityNone disables the check.
+func (s Severity) AtLeast(threshold Severity) bool {
+	if threshold == SeverityNone {
+		return false
+	}
+	return s.Rank() >= threshold.Rank()
+}
+
+// ParseSeverity parses a severity name, accepting the MODERATE spelling used by GitHub advisories.
+func ParseSeverity(value string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "none", "":
+		return SeverityNone, nil
+	case "low":
+		return SeverityLow, nil
+	case "medium", "moderate":
+		return SeverityMedium, nil
+	case "high":
+		return SeverityHigh, nil
+	case "critical":
+		return SeverityCritical, nil
+	}
+	return SeverityUnknown, fmt.Errorf("unknown severity %q (use none, low, medium, high or critical)", value)
+}
+
+// SeverityFromScore maps a CVSS base score to its qualitative rating.
+func SeverityFromScore(score float64) Severity {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityNone
+}
+
+// severityOf resolves the severity and CVSS score of an advisory for one
+// affected entry. Scored entries win over the qualitative database rating.
+func severityOf(advisory Advisory, affected Affected) (Severity, float64) {
+	scores := append(append([]SeverityScore{}, affected.Severity...), advisory.Severity...)
+	for _, entry := range scores {
+		if score, ok := parseScore(entry); ok {
+			return SeverityFromScore(score), score
+		}
+	}
+
+	for _, rating := range []string{affected.DatabaseSpecific.Severity, advisory.DatabaseSpecific.Severity} {
+		if rating == "" {
+			continue
+		}
+		if severity, err := ParseSeverity(rating); err == nil {
+			return severity, 0
+		}
+	}
+	return SeverityUnknown, 0
+}
+
+// parseScore reads a numeric score or a CVSS v3 vector.
+func parseScore(entry SeverityScore) (float64, bool) {
+	if value, err := strconv.ParseFloat(entry.Score, 64); err == nil {
+		return value, true
+	}
+	if strings.HasPrefix(entry.Score, "CVSS:3.") {
+		return CVSS3BaseScore(entry.Score)
+	}
+	return 0, false
+}
+
+// cvss3Weights holds the metric weights of the CVSS v3.1 specification.
+// Privileges Required depends on Scope and is handled separately.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// CVSS3BaseScore computes the base score of a CVSS v3.0/v3.1 vector such as
+// CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H.
+func CVSS3BaseScore(vector string) (float64, bool) {
+	metrics := map[string]string{}
+	for _, part := range strings.Split(vector, "/")[1:] {
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return 0, false
+		}
+		metrics[key] = value
+	}
+
+	weights := map[string]float64{}
+	for metric, table := range cvss3Weights {
+		weight, ok := table[metrics[metric]]
+		if !ok {
+			return 0, false
+		}
+		weights[metric] = weight
+	}
+
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return 0, false
+	}
+	privileges, ok := map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}[metrics["PR"]]
+	if !ok {
+		return 0, false
+	}
+	if changed && metrics["PR"] != "N" {
+		privileges = map[string]float64{"L": 0.68, "H": 0.5}[metrics["PR"]]
+	}
+
+	iss := 1 - (1-weights["C"])*(1-weights["I"])*(1-weights["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+
+	exploitability := 8.22 * weights["AV"] * weights["AC"] * privileges * weights["UI"]
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// roundUp implements the CVSS v3.1 Roundup function: the smallest number with
+// one decimal place that is equal to or higher than its input.
+func roundUp(value float64) float64 {
+	scaled := int64(math.Round(value * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+	return float64(scaled/10000+1) / 10
+}
@@ -0,0 +1,296 @@
+// Package binarypkg reads and writes prebuilt binary packages: zip archives of
+// the compiled artifacts (.bpl, .dcp, .dcu and .bin folders) of a package for
+// one compiler version and platform, described by a manifest.
+package binarypkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/pkg/consts"
+)
+
+const (
+	// ManifestFile is the name of the manifest inside the archive.
+	ManifestFile = "boss-binary.json"
+	// downloadTimeout bounds the download of an archive.
+	downloadTimeout = 5 * time.Minute
+	// maxArchiveSize guards against a misconfigured server streaming forever.
+	maxArchiveSize = 1 << 30
+)
+
+// Folders are the artifact folders a binary package carries.
+//
+//nolint:gochecknoglobals // Read-only list of artifact folders
+var Folders = []string{consts.BplFolder, consts.DcpFolder, consts.DcuFolder, consts.BinFolder}
+
+// Manifest describes the binaries of an archive.
+type Manifest struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Engines holds the compiler version and the single platform the
+	// binaries were built for.
+	Engines domain.PackageEngines `json:"engines"`
+	Config  string                `json:"config,omitempty"`
+}
+
+// Platform returns the platform of the binaries.
+func (m Manifest) Platform() string {
+	if len(m.Engines.Platforms) == 0 {
+		return ""
+	}
+	return m.Engines.Platforms[0]
+}
+
+// FileName returns the conventional name of the archive.
+func (m Manifest) FileName() string {
+	parts := []string{m.Name, m.Version, m.Engines.Compiler, m.Platform()}
+	if m.Config != "" {
+		parts = append(parts, m.Config)
+	}
+	return sanitize(strings.Join(slices.DeleteFunc(parts, func(part string) bool { return part == "" }), "-")) + ".zip"
+}
+
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>| `, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// Matches reports whether the binaries were built for the compiler version,
+// platform and build configuration. A manifest without a configuration
+// matches any.
+func (m Manifest) Matches(compiler, platform, config string) bool {
+	return m.Engines.Compiler == compiler &&
+		strings.EqualFold(m.Platform(), platform) &&
+		(m.Config == "" || strings.EqualFold(m.Config, config))
+}
+
+// Pack builds an archive of the manifest and the artifacts, which maps the
+// path of each artifact in the archive ("<folder>/<file>") to its file.
+func Pack(manifest Manifest, artifacts map[string]string) ([]byte, error) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	writer, err := archive.Create(ManifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(artifacts))
+	for name := range artifacts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if _, _, err = splitArtifact(name); err != nil {
+			return nil, err
+		}
+		if err = addFile(archive, artifacts[name], name); err != nil {
+			return nil, err
+		}
+	}
+	if err = archive.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func addFile(archive *zip.Writer, source, name string) error {
+	file, err := os.Open(source) // #nosec G304 -- Packing an artifact of the package
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	writer, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, file)
+	return err
+}
+
+// Digest returns the hex SHA-256 of an archive, as listed in boss.json.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Fetch reads the archive of a binary: an http(s) URL, or a path relative to
+// packageDir. When the binary lists a digest, the archive must match it.
+func Fetch(binary domain.PackageBinary, packageDir string) ([]byte, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(binary.URL, "http://") || strings.HasPrefix(binary.URL, "https://") {
+		data, err = download(binary.URL)
+	} else {
+		source := binary.URL
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(packageDir, filepath.FromSlash(source))
+		}
+		data, err = os.ReadFile(source) // #nosec G304 -- Reading a binary package listed in boss.json
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if binary.SHA256 != "" && !strings.EqualFold(Digest(data), binary.SHA256) {
+		return nil, fmt.Errorf("%s does not match its sha256 %s", binary.URL, binary.SHA256)
+	}
+	return data, nil
+}
+
+func download(source string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), downloadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", req.URL.Redacted(), resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxArchiveSize))
+}
+
+// ReadManifest returns the manifest of an archive.
+func ReadManifest(data []byte) (Manifest, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return Manifest{}, err
+	}
+	return readManifest(archive)
+}
+
+func readManifest(archive *zip.Reader) (Manifest, error) {
+	var manifest Manifest
+	file, err := archive.Open(ManifestFile)
+	if err != nil {
+		return manifest, fmt.Errorf("not a binary package: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if err = json.NewDecoder(file).Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+	return manifest, nil
+}
+
+// Extract writes the artifacts of an archive into the artifact folders of dir
+// and returns its manifest.
+func Extract(data []byte, dir string) (Manifest, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return Manifest{}, err
+	}
+	manifest, err := readManifest(archive)
+	if err != nil {
+		return manifest, err
+	}
+
+	for _, file := range archive.File {
+		if file.Name == ManifestFile {
+			continue
+		}
+		folder, name, err := splitArtifact(file.Name)
+		if err != nil {
+			return manifest, err
+		}
+		target := filepath.Join(dir, folder, name)
+		if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return manifest, err
+		}
+		if err = extractFile(file, target); err != nil {
+			return manifest, err
+		}
+	}
+	return manifest, nil
+}
+
+// splitArtifact accepts only files directly inside an artifact folder, with a
+// name that cannot leave it on any platform.
+func splitArtifact(name string) (string, string, error) {
+	folder, file := path.Split(name)
+	folder = strings.TrimSuffix(folder, "/")
+	if !slices.Contains(Folders, folder) || file == "" || file == "." || file == ".." ||
+		strings.ContainsAny(file, `\:`) {
+		return "", "", fmt.Errorf("unexpected file %q in binary package", name)
+	}
+	return folder, file, nil
+}
+
+func extractFile(file *zip.File, target string) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	output, err := os.Create(target) // #nosec G304 -- Extracting an artifact into a module folder
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(output, io.LimitReader(reader, maxArchiveSize)); err != nil {
+		_ = output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+// ErrNoMatch is returned by Select when no binary fits the build.
+var ErrNoMatch = errors.New("no matching binary package")
+
+// Select returns the first binary listed for the compiler version, platform
+// and build configuration, preferring one built for that configuration over
+// one that matches any.
+func Select(binaries []domain.PackageBinary, compiler, platform, config string) (domain.PackageBinary, error) {
+	var anyConfig *domain.PackageBinary
+	for i, binary := range binaries {
+		if binary.Compiler != compiler || !strings.EqualFold(binary.Platform, platform) {
+			continue
+		}
+		if strings.EqualFold(binary.Config, config) {
+			return binary, nil
+		}
+		if binary.Config == "" && anyConfig == nil {
+			anyConfig = &binaries[i]
+		}
+	}
+	if anyConfig != nil {
+		return *anyConfig, nil
+	}
+	return domain.PackageBinary{}, ErrNoMatch
+}
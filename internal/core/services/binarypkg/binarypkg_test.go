@@ -0,0 +1,113 @@
+package binarypkg_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/binarypkg"
+)
+
+func testManifest() binarypkg.Manifest {
+	return binarypkg.Manifest{
+		Name:    "hashload/horse",
+		Version: "3.1.0",
+		Engines: domain.PackageEngines{Compiler: "37.0", Platforms: []string{"Win64"}},
+		Config:  "Release",
+	}
+}
+
+func TestPackAndExtract(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "Horse.bpl")
+	if err := os.WriteFile(source, []byte("bpl"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := binarypkg.Pack(testManifest(), map[string]string{".bpl/Horse.bpl": source})
+	if err != nil {
+		t.Fatalf("Pack() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	manifest, err := binarypkg.Extract(data, dir)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if !manifest.Matches("37.0", "win64", "release") {
+		t.Errorf("Extract() manifest = %+v", manifest)
+	}
+	if got, err := os.ReadFile(filepath.Join(dir, ".bpl", "Horse.bpl")); err != nil || string(got) != "bpl" {
+		t.Errorf("extracted Horse.bpl = %q, %v", got, err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, binarypkg.ManifestFile)); !os.IsNotExist(err) {
+		t.Error("the manifest should not be extracted")
+	}
+	if name := manifest.FileName(); name != "hashload_horse-3.1.0-37.0-Win64-Release.zip" {
+		t.Errorf("FileName() = %s", name)
+	}
+}
+
+func TestPack_RejectsOtherFolders(t *testing.T) {
+	if _, err := binarypkg.Pack(testManifest(), map[string]string{"src/Horse.pas": "Horse.pas"}); err == nil {
+		t.Error("Pack() should reject files outside the artifact folders")
+	}
+}
+
+func TestExtract_RejectsPathsOutsideArtifactFolders(t *testing.T) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for _, name := range []string{binarypkg.ManifestFile, ".bpl/../../evil.bpl"} {
+		if _, err := archive.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := binarypkg.Extract(buffer.Bytes(), t.TempDir()); err == nil {
+		t.Error("Extract() should reject a path leaving the artifact folders")
+	}
+}
+
+func TestFetch_ChecksDigest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "horse.zip"), []byte("archive"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	binary := domain.PackageBinary{URL: "horse.zip", SHA256: binarypkg.Digest([]byte("archive"))}
+	if data, err := binarypkg.Fetch(binary, dir); err != nil || string(data) != "archive" {
+		t.Errorf("Fetch() = %q, %v", data, err)
+	}
+	binary.SHA256 = binarypkg.Digest([]byte("other"))
+	if _, err := binarypkg.Fetch(binary, dir); err == nil {
+		t.Error("Fetch() should fail when the digest does not match")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	binaries := []domain.PackageBinary{
+		{Compiler: "37.0", Platform: "Win32", URL: "any.zip"},
+		{Compiler: "37.0", Platform: "Win32", Config: "Release", URL: "release.zip"},
+		{Compiler: "36.0", Platform: "Win32", Config: "Debug", URL: "old.zip"},
+	}
+
+	for _, tt := range []struct {
+		compiler, platform, config, want string
+	}{
+		{"37.0", "win32", "Release", "release.zip"},
+		{"37.0", "Win32", "Debug", "any.zip"},
+		{"36.0", "Win32", "Debug", "old.zip"},
+	} {
+		got, err := binarypkg.Select(binaries, tt.compiler, tt.platform, tt.config)
+		if err != nil || got.URL != tt.want {
+			t.Errorf("Select(%s, %s, %s) = %s, %v; want %s", tt.compiler, tt.platform, tt.config, got.URL, err, tt.want)
+		}
+	}
+	if _, err := binarypkg.Select(binaries, "36.0", "Win64", "Debug"); !errors.Is(err, binarypkg.ErrNoMatch) {
+		t.Errorf("Select() error = %v, want ErrNoMatch", err)
+	}
+}
@@ -0,0 +1,111 @@
+package buildcache
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashload/boss/pkg/consts"
+)
+
+// artifactFolders are the folders of a module directory a build writes its
+// artifacts to, the content of a cache entry.
+//
+//nolint:gochecknoglobals // Read-only list of artifact folders
+var artifactFolders = []string{consts.BplFolder, consts.DcpFolder, consts.DcuFolder, consts.BinFolder}
+
+// pack archives the files of the artifact folders of dir.
+func pack(dir string) ([]byte, error) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for _, folder := range artifactFolders {
+		entries, err := os.ReadDir(filepath.Join(dir, folder))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if err = addFile(archive, filepath.Join(dir, folder, entry.Name()), folder+"/"+entry.Name()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func addFile(archive *zip.Writer, source, name string) error {
+	file, err := os.Open(source) // #nosec G304 -- Archiving an artifact of a module
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	writer, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, file)
+	return err
+}
+
+// unpack extracts an entry into the artifact folders of dir. Only files
+// directly inside an artifact folder are accepted.
+func unpack(data []byte, dir string) error {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	for _, file := range archive.File {
+		folder, name := path.Split(file.Name)
+		if !slices.Contains(artifactFolders, strings.TrimSuffix(folder, "/")) || !validName(name) {
+			return fmt.Errorf("unexpected file %q", file.Name)
+		}
+		target := filepath.Join(dir, folder, name)
+		if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err = extractFile(file, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validName rejects names that would leave the artifact folder on any
+// platform.
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `\:`)
+}
+
+func extractFile(file *zip.File, target string) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	output, err := os.Create(target) // #nosec G304 -- Restoring an artifact into a module folder
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(output, io.LimitReader(reader, maxEntrySize)); err != nil {
+		_ = output.Close()
+		return err
+	}
+	return output.Close()
+}
@@ -0,0 +1,125 @@
+// Package buildcache stores the compiled artifacts of dependencies under a key
+// derived from everything that determines them, so a build can restore them
+// instead of invoking the compiler. Entries live in a local directory and,
+// optionally, in a shared HTTP cache.
+package buildcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashload/boss/pkg/msg"
+)
+
+// keyVersion is bumped whenever the key inputs or the entry format change, so
+// entries written by another version are never restored.
+const keyVersion = "boss-build-cache/1"
+
+// ErrNotFound is returned by a Store that holds no entry for a key.
+var ErrNotFound = errors.New("not in the build cache")
+
+// KeyInput is what a compiled dependency depends on.
+type KeyInput struct {
+	// Sources is the digest of the dependency's source files.
+	Sources  string
+	Compiler string
+	Platform string
+	Config   string
+	// Dependencies holds the digests of the artifacts of the dependencies it
+	// is compiled against.
+	Dependencies []string
+	// Options are the compiler options from the build section of boss.json,
+	// in the order they are passed.
+	Options []string
+}
+
+// Key returns the cache key of the input, a hex SHA-256.
+func (k KeyInput) Key() string {
+	dependencies := slices.Clone(k.Dependencies)
+	slices.Sort(dependencies)
+
+	hasher := sha256.New()
+	for _, line := range []string{keyVersion, k.Sources, k.Compiler, k.Platform, strings.ToLower(k.Config)} {
+		hasher.Write([]byte(line))
+		hasher.Write([]byte{'\n'})
+	}
+	for _, dependency := range dependencies {
+		hasher.Write([]byte(dependency))
+		hasher.Write([]byte{'\n'})
+	}
+	if len(k.Options) > 0 {
+		hasher.Write([]byte("options\n"))
+		for _, option := range k.Options {
+			hasher.Write([]byte(option))
+			hasher.Write([]byte{'\n'})
+		}
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// Store holds cache entries, archives of compiled artifacts.
+type Store interface {
+	// Get returns the entry of key, or ErrNotFound.
+	Get(key string) ([]byte, error)
+	Put(key string, data []byte) error
+}
+
+// Cache restores and records compiled artifacts. The local store is always
+// consulted first; an entry found only in the remote one is copied locally.
+type Cache struct {
+	local  Store
+	remote Store
+	push   bool
+}
+
+// New creates a cache over a local store and an optional remote one (nil for
+// none). Entries are uploaded to the remote store only when push is set.
+func New(local, remote Store, push bool) *Cache {
+	return &Cache{local: local, remote: remote, push: push}
+}
+
+// Restore extracts the entry of key into dir and reports whether there was
+// one. Failures are reported as a miss, the dependency is then compiled.
+func (c *Cache) Restore(key, dir string) bool {
+	data, err := c.local.Get(key)
+	if err != nil && c.remote != nil {
+		if data, err = c.remote.Get(key); err == nil {
+			if putErr := c.local.Put(key, data); putErr != nil {
+				msg.Debug("Failed to keep build cache entry %s locally: %v", key, putErr)
+			}
+		}
+	}
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			msg.Warn("⚠️ Build cache lookup failed: %v", err)
+		}
+		return false
+	}
+
+	if err = unpack(data, dir); err != nil {
+		msg.Warn("⚠️ Ignoring corrupt build cache entry %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// Save records the artifacts found in the artifact folders of dir under key.
+func (c *Cache) Save(key, dir string) error {
+	data, err := pack(dir)
+	if err != nil {
+		return err
+	}
+	if err = c.local.Put(key, data); err != nil {
+		return err
+	}
+	if c.remote != nil && c.push {
+		if err = c.remote.Put(key, data); err != nil {
+			return fmt.Errorf("failed to upload to the remote build cache: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+//nolint:testpackage // Testing internal archive functions
+package buildcache
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestKeyInput_Key(t *testing.T) {
+	base := KeyInput{Sources: "sha256:a", Compiler: "37.0", Platform: "Win32", Config: "Release",
+		Dependencies: []string{"github.com/x/a sha256:1", "github.com/x/b sha256:2"}}
+
+	reordered := base
+	reordered.Dependencies = []string{"github.com/x/b sha256:2", "github.com/x/a sha256:1"}
+	if base.Key() != reordered.Key() {
+		t.Error("Key() should not depend on the order of the dependencies")
+	}
+
+	for name, changed := range map[string]KeyInput{
+		"sources":    {Sources: "sha256:b", Compiler: "37.0", Platform: "Win32", Config: "Release"},
+		"compiler":   {Sources: "sha256:a", Compiler: "36.0", Platform: "Win32", Config: "Release"},
+		"platform":   {Sources: "sha256:a", Compiler: "37.0", Platform: "Win64", Config: "Release"},
+		"config":     {Sources: "sha256:a", Compiler: "37.0", Platform: "Win32", Config: "Debug"},
+		"dependency": {Sources: "sha256:a", Compiler: "37.0", Platform: "Win32", Config: "Release"},
+		"options": {Sources: "sha256:a", Compiler: "37.0", Platform: "Win32", Config: "Release",
+			Dependencies: base.Dependencies, Options: []string{"-DUSE_FIREDAC"}},
+	} {
+		if changed.Key() == base.Key() {
+			t.Errorf("Key() should change with the %s", name)
+		}
+	}
+}
+
+func writeArtifact(t *testing.T, dir, folder, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, folder), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, folder, name), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCache_SaveAndRestoreLocally(t *testing.T) {
+	module := t.TempDir()
+	writeArtifact(t, module, ".bpl", "Horse.bpl", "bpl")
+	writeArtifact(t, module, ".dcu", "Horse.Core.dcu", "dcu")
+	writeArtifact(t, module, "src", "Horse.pas", "source")
+
+	cache := New(NewLocalStore(t.TempDir()), nil, false)
+	key := KeyInput{Sources: "sha256:a"}.Key()
+	if cache.Restore(key, t.TempDir()) {
+		t.Fatal("Restore() of an empty cache should miss")
+	}
+	if err := cache.Save(key, module); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	restored := t.TempDir()
+	if !cache.Restore(key, restored) {
+		t.Fatal("Restore() should hit after Save()")
+	}
+	if data, err := os.ReadFile(filepath.Join(restored, ".dcu", "Horse.Core.dcu")); err != nil || string(data) != "dcu" {
+		t.Errorf("restored dcu = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(restored, "src")); !os.IsNotExist(err) {
+		t.Error("only artifact folders should be cached")
+	}
+}
+
+// fileServer is an HTTP cache that serves GET and accepts PUT.
+func fileServer(t *testing.T) (*httptest.Server, map[string][]byte) {
+	t.Helper()
+	var mu sync.Mutex
+	files := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := files[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			files[r.URL.Path] = data
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, files
+}
+
+func TestCache_RemoteIsSharedAndCopiedLocally(t *testing.T) {
+	server, files := fileServer(t)
+	module := t.TempDir()
+	writeArtifact(t, module, ".dcp", "Horse.dcp", "dcp")
+	key := KeyInput{Sources: "sha256:a"}.Key()
+
+	readOnly := New(NewLocalStore(t.TempDir()), NewHTTPStore(server.URL+"/cache/"), false)
+	if err := readOnly.Save(key, module); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if len(files) != 0 {
+		t.Fatal("Save() should not upload without push")
+	}
+
+	pusher := New(NewLocalStore(t.TempDir()), NewHTTPStore(server.URL+"/cache"), true)
+	if err := pusher.Save(key, module); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, ok := files["/cache/"+key+".zip"]; !ok {
+		t.Fatalf("Save() with push should upload %s.zip, server has %v", key, files)
+	}
+
+	local := NewLocalStore(t.TempDir())
+	colleague := New(local, NewHTTPStore(server.URL+"/cache"), false)
+	if !colleague.Restore(key, t.TempDir()) {
+		t.Fatal("Restore() should hit the remote cache")
+	}
+	if _, err := local.Get(key); err != nil {
+		t.Errorf("a remote hit should be kept locally: %v", err)
+	}
+}
+
+func TestUnpack_RejectsPathsOutsideArtifactFolders(t *testing.T) {
+	names := []string{"../evil.bpl", ".bpl/../../evil.bpl", "src/Unit.pas", ".bpl/sub/x.bpl", `.bpl/..\x.bpl`}
+	for _, name := range names {
+		var buffer bytes.Buffer
+		archive := zip.NewWriter(&buffer)
+		if _, err := archive.Create(name); err != nil {
+			t.Fatal(err)
+		}
+		if err := archive.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		err := unpack(buffer.Bytes(), t.TempDir())
+		if err == nil || !strings.Contains(err.Error(), "unexpected file") {
+			t.Errorf("unpack(%q) error = %v, want an unexpected file error", name, err)
+		}
+	}
+}
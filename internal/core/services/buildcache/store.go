@@ -0,0 +1,156 @@
+package buildcache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// entryExt is the extension of a cache entry, a zip archive.
+	entryExt = ".zip"
+	// requestTimeout bounds a single download or upload of an entry.
+	requestTimeout = 5 * time.Minute
+	// maxEntrySize guards against a misconfigured server streaming forever.
+	maxEntrySize = 1 << 30
+)
+
+// LocalStore keeps entries in a directory, fanned out by the first two
+// characters of the key.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a store in dir, created on the first Put.
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+func (s *LocalStore) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key+entryExt)
+}
+
+// Get returns the entry of key, or ErrNotFound.
+func (s *LocalStore) Get(key string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(key)) // #nosec G304 -- Reading an entry of the build cache
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Put writes the entry of key. It is written aside and renamed, so parallel
+// builds never read half an entry.
+func (s *LocalStore) Put(key string, data []byte) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// HTTPStore keeps entries on an HTTP server as <url>/<key>.zip: GET reads an
+// entry and PUT writes one, so a plain static file server can serve a
+// read-only cache. Credentials can be given in the URL.
+type HTTPStore struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPStore creates a store on the server at baseURL.
+func NewHTTPStore(baseURL string) *HTTPStore {
+	return &HTTPStore{url: strings.TrimRight(baseURL, "/"), client: http.DefaultClient}
+}
+
+// Get downloads the entry of key, or returns ErrNotFound.
+func (s *HTTPStore) Get(key string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(io.LimitReader(resp.Body, maxEntrySize))
+	case http.StatusNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("GET %s: %s", s.redacted(key), resp.Status)
+	}
+}
+
+// Put uploads the entry of key.
+func (s *HTTPStore) Put(key string, data []byte) error {
+	resp, err := s.do(http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("PUT %s: %s", s.redacted(key), resp.Status)
+	}
+	return nil
+}
+
+func (s *HTTPStore) do(method, key string, data []byte) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	req, err := http.NewRequestWithContext(ctx, method, s.url+"/"+key+entryExt, bytes.NewReader(data))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/zip")
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// redacted returns the URL of an entry without credentials, for messages.
+func (s *HTTPStore) redacted(key string) string {
+	entryURL, err := url.Parse(s.url + "/" + key + entryExt)
+	if err != nil {
+		return key + entryExt
+	}
+	return entryURL.Redacted()
+}
+
+// cancelOnClose releases the request context once the body is read.
+type cancelOnClose struct {
+	io.ReadCloser
+
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
@@ -0,0 +1,251 @@
+// Package buildlog keeps the compiler output of every build: one folder per
+// run, holding a folder per dependency with the log of each project and its
+// metadata (compiler, platform, configuration, duration and exit status).
+package buildlog
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// runFile holds the metadata of a run, in the run folder.
+	runFile = "run.json"
+	// rootModule is the folder of the projects of the root package.
+	rootModule = "_root"
+	// runIDFormat names the run folders; they sort chronologically.
+	runIDFormat = "20060102-150405.000"
+)
+
+// Status is the outcome of a run or of a project build.
+type Status string
+
+const (
+	// StatusRunning is a run that has not finished, or was interrupted.
+	StatusRunning Status = "running"
+	// StatusSuccess is a run or project that built without errors.
+	StatusSuccess Status = "success"
+	// StatusFailed is a run with a failed project, or a failed project.
+	StatusFailed Status = "failed"
+)
+
+// RunInfo describes a run.
+type RunInfo struct {
+	ID       string        `json:"id"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration,omitempty"`
+	Status   Status        `json:"status"`
+	Compiler string        `json:"compiler,omitempty"`
+	Platform string        `json:"platform,omitempty"`
+	Config   string        `json:"config,omitempty"`
+	Projects int           `json:"projects"`
+	Failed   int           `json:"failed"`
+}
+
+// Entry describes the build of one project.
+type Entry struct {
+	// Module is the folder of the dependency, empty for the root package.
+	Module     string `json:"module,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	// Project is the path of the project file relative to the module, with
+	// forward slashes.
+	Project  string        `json:"project"`
+	Compiler string        `json:"compiler,omitempty"`
+	Platform string        `json:"platform,omitempty"`
+	Config   string        `json:"config,omitempty"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+	ExitCode int           `json:"exit_code"`
+	Status   Status        `json:"status"`
+	// Log is the path of the log file.
+	Log string `json:"log"`
+}
+
+// Matches reports whether name designates the entry: its repository or a
+// path suffix of it, its module folder, or its project file, by path or by
+// name, with or without extension.
+func (e Entry) Matches(name string) bool {
+	name = strings.ToLower(strings.Trim(name, "/"))
+	repository := strings.ToLower(e.Repository)
+	project := strings.ToLower(e.Project)
+	return (repository != "" && (name == repository || strings.HasSuffix(repository, "/"+name))) ||
+		name == strings.ToLower(e.Module) ||
+		name == project || name == strings.TrimSuffix(project, path.Ext(project)) ||
+		name == path.Base(project) || name == strings.TrimSuffix(path.Base(project), path.Ext(project))
+}
+
+// Run is a build being logged. It is safe for concurrent use; a nil *Run logs
+// nothing.
+type Run struct {
+	dir  string
+	mu   sync.Mutex
+	info RunInfo
+}
+
+// Start creates the folder of a new run under logsDir.
+func Start(logsDir string, info RunInfo) (*Run, error) {
+	info.Started = time.Now()
+	info.ID = info.Started.Format(runIDFormat)
+	info.Status = StatusRunning
+	run := &Run{dir: filepath.Join(logsDir, info.ID), info: info}
+	if err := os.MkdirAll(run.dir, 0755); err != nil {
+		return nil, err
+	}
+	return run, run.writeInfo()
+}
+
+// LogPath returns the log file of a project of module (empty for the root
+// package), creating its folder. project is the path of the project file
+// relative to the module.
+func (r *Run) LogPath(module, project string) (string, error) {
+	dir := filepath.Join(r.dir, moduleFolder(module))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, projectFile(project)+".log"), nil
+}
+
+// Record writes the metadata of a project build next to its log.
+func (r *Run) Record(entry Entry) error {
+	if r == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(r.dir, moduleFolder(entry.Module), projectFile(entry.Project)+".json")
+	if err = os.WriteFile(file, data, 0600); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.info.Projects++
+	if entry.Status == StatusFailed {
+		r.info.Failed++
+	}
+	return nil
+}
+
+// Finish records the outcome of the run.
+func (r *Run) Finish() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	r.info.Duration = time.Since(r.info.Started)
+	r.info.Status = StatusSuccess
+	if r.info.Failed > 0 {
+		r.info.Status = StatusFailed
+	}
+	r.mu.Unlock()
+	return r.writeInfo()
+}
+
+func (r *Run) writeInfo() error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.info, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(r.dir, runFile), data, 0600)
+}
+
+func moduleFolder(module string) string {
+	if module == "" {
+		return rootModule
+	}
+	return module
+}
+
+// projectFile returns the name of the files of a project in its module
+// folder: its path with the separators escaped, so projects of the same name
+// in different folders of a module keep files of their own.
+func projectFile(project string) string {
+	project = strings.ReplaceAll(filepath.ToSlash(project), "%", "%25")
+	return strings.ReplaceAll(project, "/", "%2F")
+}
+
+// Runs returns the runs logged under logsDir, newest first. Folders without
+// readable metadata are skipped.
+func Runs(logsDir string) ([]RunInfo, error) {
+	entries, err := os.ReadDir(logsDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []RunInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		var info RunInfo
+		if readJSON(filepath.Join(logsDir, entry.Name(), runFile), &info) == nil {
+			runs = append(runs, info)
+		}
+	}
+	slices.SortFunc(runs, func(a, b RunInfo) int { return strings.Compare(b.ID, a.ID) })
+	return runs, nil
+}
+
+// Entries returns the project builds of a run, sorted by module and project.
+func Entries(logsDir, runID string) ([]Entry, error) {
+	paths, err := filepath.Glob(filepath.Join(logsDir, runID, "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, path := range paths {
+		var entry Entry
+		if readJSON(path, &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		if order := strings.Compare(a.Module, b.Module); order != 0 {
+			return order
+		}
+		return strings.Compare(a.Project, b.Project)
+	})
+	return entries, nil
+}
+
+func readJSON(path string, value any) error {
+	data, err := os.ReadFile(path) // #nosec G304 -- Reading build log metadata written by Boss
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// Prune removes the runs beyond the keep newest and those older than maxAge;
+// a limit that is not positive is not applied. It returns the number of runs
+// removed.
+func Prune(logsDir string, keep int, maxAge time.Duration) (int, error) {
+	runs, err := Runs(logsDir)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for i, run := range runs {
+		if (keep > 0 && i >= keep) || (maxAge > 0 && time.Since(run.Started) > maxAge) {
+			if err = os.RemoveAll(filepath.Join(logsDir, run.ID)); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,143 @@
+package buildlog_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashload/boss/internal/core/services/buildlog"
+)
+
+func TestRun_RecordsEntriesAndOutcome(t *testing.T) {
+	logsDir := t.TempDir()
+	run, err := buildlog.Start(logsDir, buildlog.RunInfo{Compiler: "37.0", Platform: "Win64", Config: "Release"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	for _, entry := range []buildlog.Entry{
+		{Module: "horse", Repository: "github.com/hashload/horse", Project: "Horse.dproj",
+			Status: buildlog.StatusSuccess},
+		{Module: "jhonson", Repository: "github.com/hashload/jhonson", Project: "Jhonson.dproj",
+			Status: buildlog.StatusFailed, ExitCode: 1},
+		{Project: "App.dproj", Status: buildlog.StatusSuccess},
+	} {
+		path, pathErr := run.LogPath(entry.Module, entry.Project)
+		if pathErr != nil {
+			t.Fatalf("LogPath() error = %v", pathErr)
+		}
+		if err = os.WriteFile(path, []byte("output of "+entry.Project), 0600); err != nil {
+			t.Fatal(err)
+		}
+		entry.Log = path
+		if err = run.Record(entry); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if err = run.Finish(); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	runs, err := buildlog.Runs(logsDir)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("Runs() = %+v, %v", runs, err)
+	}
+	if got := runs[0]; got.Status != buildlog.StatusFailed || got.Projects != 3 || got.Failed != 1 ||
+		got.Platform != "Win64" {
+		t.Errorf("run = %+v, want a failed Win64 run of 3 projects", got)
+	}
+
+	entries, err := buildlog.Entries(logsDir, runs[0].ID)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Entries() = %+v, %v", entries, err)
+	}
+	if entries[0].Project != "App.dproj" || entries[2].ExitCode != 1 {
+		t.Errorf("Entries() = %+v, want the root project first and the exit status kept", entries)
+	}
+	if data, readErr := os.ReadFile(entries[1].Log); readErr != nil || string(data) != "output of Horse.dproj" {
+		t.Errorf("log = %q, %v", data, readErr)
+	}
+}
+
+func TestRun_ProjectsOfTheSameName(t *testing.T) {
+	logsDir := t.TempDir()
+	run, err := buildlog.Start(logsDir, buildlog.RunInfo{})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	projects := []string{"packages/D11/Foo.dproj", "packages/D12/Foo.dproj"}
+	for _, project := range projects {
+		path, pathErr := run.LogPath("foo", project)
+		if pathErr != nil {
+			t.Fatalf("LogPath() error = %v", pathErr)
+		}
+		if err = os.WriteFile(path, []byte("output of "+project), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err = run.Record(buildlog.Entry{Module: "foo", Project: project, Log: path}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	runs, _ := buildlog.Runs(logsDir)
+	entries, err := buildlog.Entries(logsDir, runs[0].ID)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Entries() = %+v, %v, want one entry per project", entries, err)
+	}
+	for i, entry := range entries {
+		data, readErr := os.ReadFile(entry.Log)
+		if entry.Project != projects[i] || readErr != nil || string(data) != "output of "+projects[i] {
+			t.Errorf("entry = %+v with log %q, want the log of %s", entry, data, projects[i])
+		}
+	}
+}
+
+func TestEntry_Matches(t *testing.T) {
+	entry := buildlog.Entry{Module: "horse", Repository: "github.com/hashload/horse", Project: "Horse.dproj"}
+	for _, name := range []string{"horse", "hashload/horse", "github.com/hashload/horse", "Horse.dproj", "Horse"} {
+		if !entry.Matches(name) {
+			t.Errorf("Matches(%q) = false, want true", name)
+		}
+	}
+	nested := buildlog.Entry{Module: "foo", Project: "packages/D12/Foo.dproj"}
+	for _, name := range []string{"packages/D12/Foo.dproj", "Foo.dproj", "Foo"} {
+		if !nested.Matches(name) {
+			t.Errorf("Matches(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"orse", "jhonson", ""} {
+		if entry.Matches(name) {
+			t.Errorf("Matches(%q) = true, want false", name)
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	logsDir := t.TempDir()
+	for range 3 {
+		run, err := buildlog.Start(logsDir, buildlog.RunInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = run.Finish(); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	runs, _ := buildlog.Runs(logsDir)
+
+	removed, err := buildlog.Prune(logsDir, 2, 0)
+	if err != nil || removed != 1 {
+		t.Fatalf("Prune(keep 2) = %d, %v, want 1 removed", removed, err)
+	}
+	if _, err = os.Stat(filepath.Join(logsDir, runs[2].ID)); !os.IsNotExist(err) {
+		t.Error("Prune() should remove the oldest run")
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if removed, err = buildlog.Prune(logsDir, 0, time.Millisecond); err != nil || removed != 2 {
+		t.Errorf("Prune(max age) = %d, %v, want 2 removed", removed, err)
+	}
+}
@@ -0,0 +1,174 @@
+// Package collisions finds the units that more than one installed dependency
+// provides. The compiled units of every dependency share modules/.dcu and the
+// sources of every dependency share the search path, so when two packages
+// ship a Utils.pas one of them silently shadows the other.
+package collisions
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/pkgmanager"
+)
+
+// Owner is a dependency that provides a unit.
+type Owner struct {
+	Repository string `json:"repository"`
+	Module     string `json:"module"`
+	// Files are the sources of the unit, relative to the modules folder, and
+	// its compiled units as recorded in the lock.
+	Files []string `json:"files"`
+}
+
+// Collision is a unit provided by more than one dependency.
+type Collision struct {
+	Unit   string  `json:"unit"`
+	Owners []Owner `json:"owners"`
+}
+
+// String describes the collision in one line.
+func (c Collision) String() string {
+	owners := make([]string, 0, len(c.Owners))
+	for _, owner := range c.Owners {
+		owners = append(owners, fmt.Sprintf("%s (%s)", owner.Repository, strings.Join(owner.Files, ", ")))
+	}
+	return fmt.Sprintf("unit %s is provided by %s", c.Unit, strings.Join(owners, " and "))
+}
+
+// sourceExtensions are the extensions of Pascal unit sources.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var sourceExtensions = map[string]bool{".pas": true, ".pp": true}
+
+// compiledExtensions are the extensions of compiled units; the .o files of
+// fpc go with a .ppu of the same name.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var compiledExtensions = map[string]bool{".dcu": true, ".ppu": true}
+
+// ignoredDirs are folders of VCS metadata and IDE backups, never on the
+// search path.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var ignoredDirs = map[string]bool{".git": true, ".svn": true, ".hg": true, "__history": true, "__recovery": true}
+
+// Scan returns the units provided by more than one dependency of lock, sorted
+// by unit. The sources of a dependency are the units in its mainsrc folders,
+// or anywhere in its module folder without one, as for the library path; its
+// compiled units are the .dcu and .ppu artifacts recorded in the lock. Unit
+// names are compared ignoring case, as the compilers do.
+func Scan(modulesDir string, lock domain.PackageLock) []Collision {
+	return scan(modulesDir, lock, true)
+}
+
+// ScanSources returns the units whose sources more than one dependency of
+// lock provides, as Scan does without the compiled units: it can run before
+// the dependencies are built.
+func ScanSources(modulesDir string, lock domain.PackageLock) []Collision {
+	return scan(modulesDir, lock, false)
+}
+
+func scan(modulesDir string, lock domain.PackageLock, compiled bool) []Collision {
+	owners := map[string]map[string]*Owner{}
+	names := map[string]string{}
+	add := func(unit, repository, module, file string) {
+		key := strings.ToLower(unit)
+		if _, ok := names[key]; !ok {
+			names[key] = unit
+			owners[key] = map[string]*Owner{}
+		}
+		owner, ok := owners[key][repository]
+		if !ok {
+			owner = &Owner{Repository: repository, Module: module}
+			owners[key][repository] = owner
+		}
+		if !slices.Contains(owner.Files, file) {
+			owner.Files = append(owner.Files, file)
+		}
+	}
+
+	for repository, locked := range lock.Installed {
+		for _, file := range sourceUnits(modulesDir, locked.Name) {
+			add(unitName(file), repository, locked.Name, file)
+		}
+		if !compiled {
+			continue
+		}
+		for _, artifact := range locked.GetArtifacts() {
+			if compiledExtensions[strings.ToLower(filepath.Ext(artifact))] {
+				add(unitName(artifact), repository, locked.Name, artifact)
+			}
+		}
+	}
+
+	var collisions []Collision
+	for key, byRepository := range owners {
+		if len(byRepository) < 2 {
+			continue
+		}
+		collision := Collision{Unit: names[key]}
+		for _, owner := range byRepository {
+			slices.Sort(owner.Files)
+			collision.Owners = append(collision.Owners, *owner)
+		}
+		slices.SortFunc(collision.Owners, func(a, b Owner) int {
+			return cmp.Compare(a.Repository, b.Repository)
+		})
+		collisions = append(collisions, collision)
+	}
+	slices.SortFunc(collisions, func(a, b Collision) int {
+		return cmp.Compare(strings.ToLower(a.Unit), strings.ToLower(b.Unit))
+	})
+	return collisions
+}
+
+// sourceUnits returns the unit sources of a module, relative to modulesDir.
+func sourceUnits(modulesDir, module string) []string {
+	moduleDir := filepath.Join(modulesDir, module)
+	roots := []string{moduleDir}
+	if pkg, err := pkgmanager.LoadPackageOther(filepath.Join(moduleDir, consts.FilePackage)); err == nil {
+		var mainSrcs []string
+		for mainSrc := range strings.SplitSeq(pkg.MainSrc, ";") {
+			if mainSrc = strings.TrimSpace(mainSrc); mainSrc != "" {
+				mainSrcs = append(mainSrcs, filepath.Join(moduleDir, mainSrc))
+			}
+		}
+		if len(mainSrcs) > 0 {
+			roots = mainSrcs
+		}
+	}
+
+	var files []string
+	for _, root := range roots {
+		_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil //nolint:nilerr // Skip unreadable folders
+			}
+			if entry.IsDir() {
+				if ignoredDirs[entry.Name()] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if sourceExtensions[strings.ToLower(filepath.Ext(path))] {
+				relative, relErr := filepath.Rel(modulesDir, path)
+				if relErr == nil && !slices.Contains(files, filepath.ToSlash(relative)) {
+					files = append(files, filepath.ToSlash(relative))
+				}
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+func unitName(file string) string {
+	base := filepath.Base(file)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
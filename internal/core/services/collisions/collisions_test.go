@@ -0,0 +1,119 @@
+package collisions_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/adapters/secondary/repository"
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/collisions"
+	"github.com/hashload/boss/internal/core/services/packages"
+	"github.com/hashload/boss/pkg/pkgmanager"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func setupModules(t *testing.T) string {
+	t.Helper()
+	fs := filesystem.NewOSFileSystem()
+	pkgmanager.SetInstance(packages.NewPackageService(
+		repository.NewFilePackageRepository(fs),
+		repository.NewFileLockRepository(fs),
+	))
+	return t.TempDir()
+}
+
+// TestScan tests that units of several dependencies are reported with their owners.
+func TestScan(t *testing.T) {
+	modules := setupModules(t)
+
+	writeFile(t, filepath.Join(modules, "alpha", "boss.json"), `{"name": "alpha", "mainsrc": "src"}`)
+	writeFile(t, filepath.Join(modules, "alpha", "src", "Utils.pas"), "unit Utils;")
+	writeFile(t, filepath.Join(modules, "alpha", "src", "Alpha.Core.pas"), "unit Alpha.Core;")
+	// Outside mainsrc, so not on the search path.
+	writeFile(t, filepath.Join(modules, "alpha", "samples", "Strings.pas"), "unit Strings;")
+	writeFile(t, filepath.Join(modules, "beta", "utils.pas"), "unit utils;")
+	writeFile(t, filepath.Join(modules, "beta", "Strings.pas"), "unit Strings;")
+	writeFile(t, filepath.Join(modules, "beta", ".git", "Alpha.Core.pas"), "unit Alpha.Core;")
+	writeFile(t, filepath.Join(modules, "gamma", "Gamma.pas"), "unit Gamma;")
+
+	lock := domain.PackageLock{Installed: map[string]domain.LockedDependency{
+		"github.com/test/alpha": {Name: "alpha"},
+		"github.com/test/beta":  {Name: "beta"},
+		"github.com/test/gamma": {
+			Name:      "gamma",
+			Artifacts: domain.DependencyArtifacts{Dcu: []string{"Gamma.dcu", "Strings.dcu"}},
+		},
+	}}
+
+	got := collisions.Scan(modules, lock)
+	if len(got) != 2 {
+		t.Fatalf("Scan() = %v, want 2 collisions", got)
+	}
+
+	if got[0].Unit != "Strings" || len(got[0].Owners) != 2 {
+		t.Fatalf("Scan()[0] = %v, want Strings from beta and gamma", got[0])
+	}
+	if got[0].Owners[0].Repository != "github.com/test/beta" ||
+		!slices.Equal(got[0].Owners[0].Files, []string{"beta/Strings.pas"}) {
+		t.Errorf("Scan()[0].Owners[0] = %+v", got[0].Owners[0])
+	}
+	if got[0].Owners[1].Repository != "github.com/test/gamma" ||
+		!slices.Equal(got[0].Owners[1].Files, []string{"Strings.dcu"}) {
+		t.Errorf("Scan()[0].Owners[1] = %+v", got[0].Owners[1])
+	}
+
+	if !slices.Contains([]string{"Utils", "utils"}, got[1].Unit) || len(got[1].Owners) != 2 {
+		t.Fatalf("Scan()[1] = %v, want Utils from alpha and beta", got[1])
+	}
+	if got[1].Owners[0].Module != "alpha" || got[1].Owners[1].Module != "beta" {
+		t.Errorf("Scan()[1].Owners = %+v", got[1].Owners)
+	}
+	// Strings collides with a compiled unit only, which is not built yet.
+	sources := collisions.ScanSources(modules, lock)
+	if len(sources) != 1 || !slices.Contains([]string{"Utils", "utils"}, sources[0].Unit) {
+		t.Errorf("ScanSources() = %v, want only Utils", sources)
+	}
+}
+
+// TestScan_NoCollisions tests that a unit shipped and compiled by one dependency is no collision.
+func TestScan_NoCollisions(t *testing.T) {
+	modules := setupModules(t)
+
+	writeFile(t, filepath.Join(modules, "alpha", "Alpha.Core.pas"), "unit Alpha.Core;")
+	lock := domain.PackageLock{Installed: map[string]domain.LockedDependency{
+		"github.com/test/alpha": {
+			Name:      "alpha",
+			Artifacts: domain.DependencyArtifacts{Dcu: []string{"Alpha.Core.dcu"}},
+		},
+	}}
+
+	if got := collisions.Scan(modules, lock); len(got) != 0 {
+		t.Errorf("Scan() = %v, want none", got)
+	}
+}
+
+// TestCollision_String tests the description of a collision.
+func TestCollision_String(t *testing.T) {
+	collision := collisions.Collision{Unit: "Utils", Owners: []collisions.Owner{
+		{Repository: "github.com/test/alpha", Files: []string{"alpha/src/Utils.pas"}},
+		{Repository: "github.com/test/beta", Files: []string{"beta/Utils.pas", "Utils.dcu"}},
+	}}
+
+	want := "unit Utils is provided by github.com/test/alpha (alpha/src/Utils.pas) and " +
+		"github.com/test/beta (beta/Utils.pas, Utils.dcu)"
+	if got := collision.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -2,6 +2,7 @@ package compiler
 
 import (
 	"path/filepath"
+	"sync"
 
 	"github.com/hashload/boss/internal/core/domain"
 	"github.com/hashload/boss/internal/infra"
@@ -12,6 +13,9 @@ import (
 // ArtifactService manages build artifacts using dependency injection.
 type ArtifactService struct {
 	fs infra.FileSystem
+	// moveMu serializes moves into the shared folders, which dependencies
+	// built in parallel all write to.
+	moveMu sync.Mutex
 }
 
 // NewArtifactService creates a new artifact service.
@@ -19,18 +23,26 @@ func NewArtifactService(fs infra.FileSystem) *ArtifactService {
 	return &ArtifactService{fs: fs}
 }
 
-func (a *ArtifactService) moveArtifacts(dep domain.Dependency, rootPath string) {
+func (a *ArtifactService) moveArtifacts(dep domain.Dependency, rootPath, platform, config string) {
+	a.moveMu.Lock()
+	defer a.moveMu.Unlock()
+
 	var moduleName = dep.Name()
-	a.movePath(filepath.Join(rootPath, moduleName, consts.BplFolder), filepath.Join(rootPath, consts.BplFolder))
-	a.movePath(filepath.Join(rootPath, moduleName, consts.DcpFolder), filepath.Join(rootPath, consts.DcpFolder))
-	a.movePath(filepath.Join(rootPath, moduleName, consts.BinFolder), filepath.Join(rootPath, consts.BinFolder))
-	a.movePath(filepath.Join(rootPath, moduleName, consts.DcuFolder), filepath.Join(rootPath, consts.DcuFolder))
+	for _, folder := range []string{consts.BplFolder, consts.DcpFolder, consts.BinFolder, consts.DcuFolder} {
+		a.movePath(filepath.Join(rootPath, moduleName, folder),
+			domain.PlatformArtifactDir(rootPath, folder, platform, config))
+	}
 }
 
 func (a *ArtifactService) movePath(oldPath string, newPath string) {
 	entries, err := a.fs.ReadDir(oldPath)
 	var hasError = false
 	if err == nil {
+		if len(entries) > 0 {
+			if err = a.fs.MkdirAll(newPath, 0755); err != nil {
+				msg.Debug("Failed to create artifact folder %s: %v", newPath, err)
+			}
+		}
 		for _, entry := range entries {
 			if !entry.IsDir() {
 				oldFile := filepath.Join(oldPath, entry.Name())
@@ -93,7 +105,13 @@ func (d *DefaultArtifactManager) EnsureArtifacts(
 	d.service.ensureArtifacts(lockedDependency, dep, rootPath)
 }
 
-// MoveArtifacts moves artifacts to the shared folder.
-func (d *DefaultArtifactManager) MoveArtifacts(dep domain.Dependency, rootPath string) {
-	d.service.moveArtifacts(dep, rootPath)
+// MoveArtifacts moves artifacts to the shared folders of a build configuration.
+func (d *DefaultArtifactManager) MoveArtifacts(dep domain.Dependency, rootPath, config string) {
+	d.service.moveArtifacts(dep, rootPath, "", config)
+}
+
+// MovePlatformArtifacts moves artifacts to the shared folders of a build
+// configuration for a platform of a multi-platform build.
+func (d *DefaultArtifactManager) MovePlatformArtifacts(dep domain.Dependency, rootPath, platform, config string) {
+	d.service.moveArtifacts(dep, rootPath, platform, config)
 }
@@ -0,0 +1,88 @@
+package compiler
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/compilerselector"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+)
+
+// backend compiles one kind of project: Delphi projects through MSBuild,
+// Lazarus projects through lazbuild, programs and units through fpc, or any
+// project through the command template of boss.json.
+type backend interface {
+	// name identifies the compiler of job in the build logs.
+	name(job compileJob) string
+	// supports reports whether the backend builds the project file.
+	supports(projectPath string) bool
+	// compile builds the project of job, writing the compiler output to
+	// job.log.
+	compile(job compileJob) error
+}
+
+// compileJob is a project to compile and what it is compiled with.
+type compileJob struct {
+	// project is the absolute path of the project file.
+	project string
+	// dep is the dependency the project belongs to, nil for the root package.
+	dep  *domain.Dependency
+	lock domain.PackageLock
+	// compiler is the selected Delphi compiler, nil for the default one.
+	compiler *compilerselector.SelectedCompiler
+	// platform is the target platform, empty when none was chosen.
+	platform string
+	config   buildConfig
+	// log is the file the compiler output is written to.
+	log string
+	// verbose is set when no progress tracker is shown, so the backend may
+	// print what it does.
+	verbose bool
+}
+
+// delphiPlatform returns the platform of the job, Win32 when none was chosen.
+func (j compileJob) delphiPlatform() string {
+	if j.platform == "" {
+		return consts.PlatformWin32.String()
+	}
+	return j.platform
+}
+
+// outputDir returns the folder the artifacts of the job are written to: the
+// module's own folders for a dependency, moved to the shared folders of the
+// configuration afterwards, and the shared ones for a root project.
+func (j compileJob) outputDir(folder string) string {
+	return outputDir(env.GetModulesDir(), j.dep, folder, j.config)
+}
+
+// compilerBackends returns the backends of a build, in the order they are
+// tried: the command template of toolchain, then lazbuild, fpc and, for every
+// other project, MSBuild.
+func compilerBackends(toolchain *domain.PackageToolchain) []backend {
+	backends := []backend{lazarusBackend{}, fpcBackend{}, delphiBackend{}}
+	if toolchain != nil && toolchain.Command != nil && toolchain.Command.Run != "" {
+		backends = append([]backend{commandBackend{command: *toolchain.Command}}, backends...)
+	}
+	return backends
+}
+
+// selectBackend returns the first backend that supports the project.
+func selectBackend(backends []backend, projectPath string) backend {
+	for _, candidate := range backends {
+		if candidate.supports(projectPath) {
+			return candidate
+		}
+	}
+	return delphiBackend{}
+}
+
+// hasExtension reports whether path has one of extensions, ignoring case.
+func hasExtension(path string, extensions ...string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return slices.ContainsFunc(extensions, func(candidate string) bool {
+		return strings.EqualFold(candidate, ext)
+	})
+}
@@ -0,0 +1,227 @@
+//nolint:testpackage // Testing internal functions
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/adapters/secondary/repository"
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/packages"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/pkgmanager"
+)
+
+func TestSelectBackend(t *testing.T) {
+	defaults := compilerBackends(nil)
+	custom := compilerBackends(&domain.PackageToolchain{
+		Command: &domain.PackageCommand{Run: "make", Extensions: []string{".DPROJ"}},
+	})
+
+	tests := []struct {
+		backends []backend
+		project  string
+		want     string
+	}{
+		{defaults, "App.dproj", ""},
+		{defaults, "App.lpi", "lazbuild"},
+		{defaults, "Pkg.LPK", "lazbuild"},
+		{defaults, "App.lpr", "fpc"},
+		{defaults, "Unit1.pas", "fpc"},
+		{custom, "App.dproj", "command"},
+		{custom, "App.lpi", "lazbuild"},
+	}
+	for _, tt := range tests {
+		if got := selectBackend(tt.backends, tt.project).name(compileJob{}); got != tt.want {
+			t.Errorf("selectBackend(%s) = %q, want %q", tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestFpcArguments(t *testing.T) {
+	setupModules(t)
+	dep := &domain.Dependency{Repository: "github.com/test/lib"}
+	job := compileJob{
+		project: "/src/lib.lpr",
+		dep:     dep,
+		config: buildConfig{Name: "Release", Profile: "Release", Build: &domain.PackageBuild{
+			Defines: []string{"CI"},
+		}},
+	}
+
+	args := fpcArguments(job)
+	if args[0] != "-dCI" || args[len(args)-1] != "/src/lib.lpr" {
+		t.Errorf("fpcArguments() = %v, want the defines first and the project last", args)
+	}
+	if !slices.Contains(args, "-FU"+job.outputDir(consts.DcuFolder)) ||
+		!slices.Contains(args, "-Fu"+filepath.Join(modulesDir(), dep.Name())) {
+		t.Errorf("fpcArguments() = %v, want the module dcu folder and search path", args)
+	}
+}
+
+func TestCommandBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+	setupModules(t)
+	dep := &domain.Dependency{Repository: "github.com/test/lib"}
+	project := filepath.Join(modulesDir(), dep.Name(), "Lib.dproj")
+	if err := os.MkdirAll(filepath.Dir(project), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := buildConfig{Name: "Release", Profile: "Release"}
+
+	ctx := &buildContext{backends: compilerBackends(&domain.PackageToolchain{
+		Command: &domain.PackageCommand{Run: `echo "$BOSS_CONFIG" > {bpl}/{name}.bpl`},
+	})}
+	if !compile(project, dep, domain.PackageLock{}, nil, ctx, config) {
+		t.Fatal("compile() failed with the command backend")
+	}
+	data, err := os.ReadFile(filepath.Join(modulesDir(), dep.Name(), consts.BplFolder, "Lib.bpl"))
+	if err != nil || strings.TrimSpace(string(data)) != "Release" {
+		t.Errorf("the command wrote %q, %v; want the configuration in the module bpl folder", data, err)
+	}
+
+	ctx = &buildContext{backends: compilerBackends(&domain.PackageToolchain{
+		Command: &domain.PackageCommand{Run: "echo boom; exit 3"},
+	})}
+	if compile(project, dep, domain.PackageLock{}, nil, ctx, config) {
+		t.Fatal("compile() succeeded with a failing command")
+	}
+	buildLog, _ := ctx.logPath(dep, project)
+	if data, _ := os.ReadFile(buildLog); !strings.Contains(string(data), "boom") {
+		t.Errorf("build log = %q, want the output of the command", data)
+	}
+}
+
+func TestCommandBackend_QuotesPlaceholders(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test commands need a POSIX shell")
+	}
+	setupModules(t)
+	dep := &domain.Dependency{Repository: "github.com/test/lib"}
+	project := filepath.Join(modulesDir(), dep.Name(), "My Lib's $(touch pwned); src & %PATH% ^ |", "Lib.dproj")
+	if err := os.MkdirAll(filepath.Dir(project), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(project, []byte("project"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := &buildContext{backends: compilerBackends(&domain.PackageToolchain{
+		Command: &domain.PackageCommand{Run: "cp {project} {bpl}/{name}.bpl"},
+	})}
+	if !compile(project, dep, domain.PackageLock{}, nil, ctx, buildConfig{Name: "Debug"}) {
+		t.Fatal("compile() failed with a project path holding spaces and shell metacharacters")
+	}
+	if data, err := os.ReadFile(filepath.Join(modulesDir(), dep.Name(), consts.BplFolder, "Lib.bpl")); err != nil ||
+		string(data) != "project" {
+		t.Errorf("the command copied %q, %v; want the project", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(project), "pwned")); !os.IsNotExist(err) {
+		t.Error("a command substitution in the project path was run")
+	}
+}
+
+func TestShellQuote_Cmd(t *testing.T) {
+	tests := map[string]string{
+		`C:\My Lib & Co\src`: `^"C:\My Lib ^& Co\src^"`,
+		`C:\%PATH%\a^b|c`:    `^"C:\^%PATH^%\a^^b^|c^"`,
+		`C:\(x)\<y>!z`:       `^"C:\^(x^)\^<y^>^!z^"`,
+		`C:\out\`:            `^"C:\out\\^"`,
+		`say \"hi\"`:         `^"say \\\^"hi\\\^"^"`,
+	}
+	for value, want := range tests {
+		if got := shellQuote(value, true); got != want {
+			t.Errorf("shellQuote(%q, true) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestFpcBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake fpc is a shell script")
+	}
+	setupModules(t)
+	fakeCommand(t, "fpc", `for arg; do case "$arg" in -FU*) out="${arg#-FU}";; esac; last="$arg"; done
+name=$(basename "$last"); name="${name%.*}"
+touch "$out/$name.ppu" "$out/$name.o"`)
+
+	dep := domain.Dependency{Repository: "github.com/test/lib"}
+	project := filepath.Join(modulesDir(), dep.Name(), "src", "Lib.Core.pas")
+	if err := os.MkdirAll(filepath.Dir(project), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := buildConfig{Name: "Debug", Profile: "Debug", Native: true}
+	if !compile(project, &dep, domain.PackageLock{}, nil, &buildContext{}, config) {
+		t.Fatal("compile() failed with the fpc backend")
+	}
+
+	locked := domain.LockedDependency{}
+	NewDefaultArtifactManager(&OSFileSystemWrapper{}).EnsureArtifacts(&locked, dep, modulesDir())
+	slices.Sort(locked.Artifacts.Dcu)
+	if !slices.Equal(locked.Artifacts.Dcu, []string{"Lib.Core.o", "Lib.Core.ppu"}) {
+		t.Errorf("Artifacts.Dcu = %v, want the .ppu and .o of the unit", locked.Artifacts.Dcu)
+	}
+}
+
+func TestPackageProjects(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake compilers are shell scripts")
+	}
+	t.Setenv("PATH", t.TempDir())
+	pkg := &domain.Package{
+		Projects: []string{"Lib.lpk"},
+		Fpc:      &domain.PackageFpc{Program: "src/App.lpr", Units: []string{"src/Lib.pas"}},
+	}
+
+	if projects, native := packageProjects(pkg); native || !slices.Equal(projects, pkg.Projects) {
+		t.Errorf("packageProjects() without fpc = %v, %v; want the projects", projects, native)
+	}
+	fakeCommand(t, "fpc", "exit 0")
+	projects, native := packageProjects(pkg)
+	if !native || !slices.Equal(projects, []string{"src/Lib.pas", "src/App.lpr"}) {
+		t.Errorf("packageProjects() without lazbuild = %v, %v; want the units, then the program", projects, native)
+	}
+	fakeCommand(t, "lazbuild", "exit 0")
+	if _, native := packageProjects(pkg); native {
+		t.Error("packageProjects() should build the Lazarus projects when lazbuild is available")
+	}
+	pkg.Projects = nil
+	if _, native := packageProjects(pkg); !native {
+		t.Error("packageProjects() should build the fpc targets of a package without projects")
+	}
+}
+
+// fakeCommand puts a shell script named command first on the PATH.
+func fakeCommand(t *testing.T, command, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	// #nosec G306 -- The fake compiler must be executable
+	if err := os.WriteFile(filepath.Join(dir, command), []byte("#!/bin/sh\n"+script+"\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// setupModules runs the test in an empty project, with the package service
+// that reads the boss.json of its dependencies.
+func setupModules(t *testing.T) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	fs := filesystem.NewOSFileSystem()
+	pkgmanager.SetInstance(packages.NewPackageService(
+		repository.NewFilePackageRepository(fs),
+		repository.NewFileLockRepository(fs),
+	))
+}
+
+func modulesDir() string {
+	dir, _ := os.Getwd()
+	return filepath.Join(dir, consts.FolderDependencies)
+}
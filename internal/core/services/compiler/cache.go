@@ -0,0 +1,143 @@
+package compiler
+
+import (
+	"path/filepath"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/buildcache"
+	"github.com/hashload/boss/internal/core/services/compilerselector"
+	"github.com/hashload/boss/internal/core/services/timing"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/hashload/boss/utils"
+)
+
+// buildCache restores dependencies from the build cache and records the ones
+// that were compiled. A nil *buildCache is a disabled cache.
+type buildCache struct {
+	cache    *buildcache.Cache
+	index    *utils.HashIndex
+	compiler *compilerselector.SelectedCompiler
+	timings  *timing.Recorder
+}
+
+// newBuildCache returns the build cache of the global configuration, or nil
+// when it is off: disabled, skipped with --no-cache, in global mode (programs
+// are written to the global bin folder, outside the cached folders) or when no
+// compiler was selected, as its version is part of the key. Hashing and cache
+// transfers are timed in timings.
+func newBuildCache(
+	options BuildOptions,
+	selected *compilerselector.SelectedCompiler,
+	timings *timing.Recorder,
+) *buildCache {
+	config := env.GlobalConfiguration().GetBuildCache()
+	if options.NoCache || config.Disabled || env.GetGlobal() || selected == nil {
+		return nil
+	}
+
+	var remote buildcache.Store
+	if config.URL != "" {
+		remote = buildcache.NewHTTPStore(config.URL)
+	}
+	return &buildCache{
+		cache:    buildcache.New(buildcache.NewLocalStore(env.GetBuildCacheDir()), remote, config.Push),
+		index:    utils.LoadHashIndex(filepath.Join(env.GetCacheDir(), consts.FileHashIndex)),
+		compiler: selected,
+		timings:  timings,
+	}
+}
+
+// key returns the cache key of a dependency: its sources, the compiler,
+// platform and configuration, and the artifacts of the dependencies it is
+// compiled against, read from lock. It returns "" when one of them cannot be
+// read, and the dependency is compiled without the cache.
+func (b *buildCache) key(
+	dep domain.Dependency,
+	dependencyPackage *domain.Package,
+	lock domain.PackageLock,
+	config buildConfig,
+) string {
+	if b == nil {
+		return ""
+	}
+	defer b.timings.Start(timing.StepHash, dep.Name(), "")()
+	sources, err := b.index.HashFiles(filepath.Join(env.GetModulesDir(), dep.Name()))
+	if err != nil {
+		msg.Debug("Build cache: failed to hash %s: %v", dep.Name(), err)
+		return ""
+	}
+
+	input := buildcache.KeyInput{
+		Sources:  domain.FileManifest(sources).Digest(),
+		Compiler: b.compiler.Version,
+		Platform: b.compiler.Arch,
+		Config:   config.Name,
+		Options:  dccOptions(config.Build),
+	}
+	if config.Native {
+		input.Options = append([]string{"fpc"}, fpcOptions(config.Build)...)
+	}
+	for _, required := range dependencyPackage.GetParsedDependencies() {
+		digest, err := artifactsDigest(lock.GetInstalled(required), config)
+		if err != nil {
+			msg.Debug("Build cache: failed to hash the artifacts of %s: %v", required.Name(), err)
+			return ""
+		}
+		input.Dependencies = append(input.Dependencies, required.GetKey()+" "+digest)
+	}
+	return input.Key()
+}
+
+// artifactsDigest returns the digest of the artifacts of a dependency in the
+// shared folders of config.
+func artifactsDigest(locked domain.LockedDependency, config buildConfig) (string, error) {
+	manifest := domain.FileManifest{}
+	artifacts := locked.ArtifactsFor(config.Platform).Artifacts
+	for folder, names := range map[string][]string{
+		consts.BplFolder: artifacts.Bpl,
+		consts.DcpFolder: artifacts.Dcp,
+		consts.DcuFolder: artifacts.Dcu,
+		consts.BinFolder: artifacts.Bin,
+	} {
+		for _, name := range names {
+			digest, err := utils.HashFile(filepath.Join(config.artifactDir(folder), name))
+			if err != nil {
+				return "", err
+			}
+			manifest[folder+"/"+name] = digest
+		}
+	}
+	return manifest.Digest(), nil
+}
+
+// restore extracts the cached artifacts of key into the module folder of dep.
+func (b *buildCache) restore(key string, dep domain.Dependency) bool {
+	if b == nil || key == "" {
+		return false
+	}
+	defer b.timings.Start(timing.StepCache, dep.Name(), "")()
+	return b.cache.Restore(key, filepath.Join(env.GetModulesDir(), dep.Name()))
+}
+
+// save records the artifacts just compiled into the module folder of dep.
+func (b *buildCache) save(key string, dep domain.Dependency) {
+	if b == nil || key == "" {
+		return
+	}
+	defer b.timings.Start(timing.StepCache, dep.Name(), "")()
+	if err := b.cache.Save(key, filepath.Join(env.GetModulesDir(), dep.Name())); err != nil {
+		msg.Warn("⚠️ Failed to cache the build of %s: %v", dep.Name(), err)
+	}
+}
+
+// close persists the hash index.
+func (b *buildCache) close() {
+	if b == nil {
+		return
+	}
+	if err := b.index.Save(); err != nil {
+		msg.Debug("Failed to save the hash index: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package compiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/pkg/consts"
+)
+
+// commandBackend builds projects with the command template of
+// toolchain.command, run by the shell in the folder of the project.
+type commandBackend struct {
+	command domain.PackageCommand
+}
+
+func (commandBackend) name(compileJob) string {
+	return "command"
+}
+
+func (b commandBackend) supports(projectPath string) bool {
+	return len(b.command.Extensions) == 0 || hasExtension(projectPath, b.command.Extensions...)
+}
+
+func (b commandBackend) compile(job compileJob) error {
+	for _, folder := range []string{consts.BplFolder, consts.DcpFolder, consts.DcuFolder, consts.BinFolder} {
+		if err := os.MkdirAll(job.outputDir(folder), 0755); err != nil {
+			return fmt.Errorf("creating %s folder: %w", folder, err)
+		}
+	}
+
+	windows := runtime.GOOS == "windows"
+	values := commandValues(job)
+	replacements := make([]string, 0, 2*len(values))
+	environment := os.Environ()
+	for _, value := range values {
+		replacements = append(replacements, "{"+value.name+"}", shellQuote(value.value, windows))
+		environment = append(environment, "BOSS_"+strings.ToUpper(value.name)+"="+value.value)
+	}
+	script := strings.NewReplacer(replacements...).Replace(b.command.Run)
+
+	cmd := shellCommand(script)
+	cmd.Dir = filepath.Dir(job.project)
+	cmd.Env = environment
+	return runLogged(cmd, job)
+}
+
+// shellQuote quotes a placeholder value as a single argument of sh, or of
+// cmd on Windows, so paths with spaces or shell metacharacters are passed as
+// they are.
+func shellQuote(value string, windows bool) string {
+	if windows {
+		return cmdQuote(value)
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// cmdSpecial are the characters cmd interprets in a command line.
+const cmdSpecial = `()%!^"<>&|`
+
+// cmdQuote quotes value as one argument of a Windows program, as
+// CommandLineToArgvW reads it, then escapes every character cmd interprets
+// with a caret. As its quotes are escaped too, cmd sees no quoted text: the
+// carets keep &, | and ^ literal, and break %VAR% so it is not expanded.
+func cmdQuote(value string) string {
+	var arg strings.Builder
+	arg.WriteByte('"')
+	backslashes := 0
+	for _, r := range value {
+		switch r {
+		case '\\':
+			backslashes++
+		case '"':
+			arg.WriteString(strings.Repeat(`\`, backslashes+1))
+			backslashes = 0
+		default:
+			backslashes = 0
+		}
+		arg.WriteRune(r)
+	}
+	arg.WriteString(strings.Repeat(`\`, backslashes))
+	arg.WriteByte('"')
+
+	var escaped strings.Builder
+	for _, r := range arg.String() {
+		if strings.ContainsRune(cmdSpecial, r) {
+			escaped.WriteByte('^')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+type commandValue struct {
+	name  string
+	value string
+}
+
+// commandValues returns the placeholders of a command template for job, also
+// set as BOSS_<NAME> environment variables.
+func commandValues(job compileJob) []commandValue {
+	var defines, scopes []string
+	if job.config.Build != nil {
+		defines, scopes = job.config.Build.Defines, job.config.Build.UnitScopes
+	}
+	paths := append(searchPaths(job.dep), job.config.artifactDir(consts.DcuFolder))
+	return []commandValue{
+		{"project", job.project},
+		{"dir", filepath.Dir(job.project)},
+		{"name", strings.TrimSuffix(filepath.Base(job.project), filepath.Ext(job.project))},
+		{"module", logModule(job.dep)},
+		{"config", job.config.Name},
+		{"platform", job.platform},
+		{"compiler", compilerVersion(job.compiler)},
+		{"bpl", job.outputDir(consts.BplFolder)},
+		{"dcp", job.outputDir(consts.DcpFolder)},
+		{"dcu", job.outputDir(consts.DcuFolder)},
+		{"bin", job.outputDir(consts.BinFolder)},
+		{"defines", strings.Join(defines, ";")},
+		{"unitscopes", strings.Join(scopes, ";")},
+		{"searchpath", strings.Join(paths, string(os.PathListSeparator))},
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package compiler
+
+import (
+	"context"
+	"os/exec"
+)
+
+// shellCommand returns the command running script with sh.
+func shellCommand(script string) *exec.Cmd {
+	// #nosec G204 -- Running the build command configured in boss.json
+	return exec.CommandContext(context.Background(), "sh", "-c", script)
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package compiler
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+)
+
+// shellCommand returns the command running script with cmd. The command line
+// is set as it is, since the quoting of exec would escape the quotes of
+// script with backslashes, which cmd does not read.
+func shellCommand(script string) *exec.Cmd {
+	// #nosec G204 -- Running the build command configured in boss.json
+	cmd := exec.CommandContext(context.Background(), "cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd /S /C "` + script + `"`}
+	return cmd
+}
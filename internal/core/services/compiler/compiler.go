@@ -3,13 +3,19 @@
 package compiler
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/core/services/buildlog"
 	"github.com/hashload/boss/internal/core/services/compilerselector"
+	"github.com/hashload/boss/internal/core/services/diagnostics"
+	"github.com/hashload/boss/internal/core/services/timing"
 	"github.com/hashload/boss/pkg/pkgmanager"
 
 	"github.com/hashload/boss/internal/core/domain"
@@ -19,12 +25,344 @@ import (
 	"github.com/hashload/boss/pkg/msg"
 )
 
-// Build compiles the package and its dependencies.
-func Build(pkg *domain.Package, compilerVersion, platform string) {
+// BuildOptions selects what a build compiles and with which compiler.
+type BuildOptions struct {
+	Compiler string
+	Platform string
+	// Config is the build configuration, overriding toolchain.config.
+	Config string
+	// All rebuilds every dependency instead of only those changed since the
+	// last build (and the dependencies that use them).
+	All bool
+	// Modules restricts the build to the named dependencies. A name is the
+	// repository ("github.com/hashload/horse"), a suffix of it ("horse") or
+	// the module folder.
+	Modules []string
+	// Root also builds the projects listed in the root boss.json.
+	Root bool
+	// Jobs is the number of dependencies compiled at the same time; zero
+	// means one per CPU.
+	Jobs int
+	// NoCache compiles every dependency instead of restoring it from the
+	// build cache.
+	NoCache bool
+	// DiagnosticsJSON and DiagnosticsSARIF are the files the compiler
+	// diagnostics are written to, as JSON and SARIF; empty for none.
+	DiagnosticsJSON  string
+	DiagnosticsSARIF string
+	// Profile is the file a Chrome trace of the build is written to; empty
+	// for none.
+	Profile string
+	// Matrix builds every platform of engines.platforms; a Platform listing
+	// several, separated by commas, does the same for those.
+	Matrix bool
+	// Timings records how long each step takes. When nil, Run records the
+	// build on its own and prints the summary; an install passes the recorder
+	// of its own steps and reports once the build is done.
+	Timings *timing.Recorder
+}
+
+// buildContext is what the compilations of one build share.
+type buildContext struct {
+	compiler *compilerselector.SelectedCompiler
+	profile  string
+	cache    *buildCache
+	report   *diagnostics.Report
+	logs     *buildlog.Run
+	timings  *timing.Recorder
+	// platform is the target platform, empty when none was chosen.
+	platform string
+	// matrix is the platform of a multi-platform build, empty for a
+	// single-platform build.
+	matrix   string
+	backends []backend
+}
+
+// selectedCompiler returns the compiler of the build; nil (the default
+// compiler) for a nil context.
+func (c *buildContext) selectedCompiler() *compilerselector.SelectedCompiler {
+	if c == nil {
+		return nil
+	}
+	return c.compiler
+}
+
+// buildPlatform returns the target platform of the build, empty for a nil
+// context.
+func (c *buildContext) buildPlatform() string {
+	if c == nil {
+		return ""
+	}
+	return c.platform
+}
+
+// compilerBackends returns the backends of the build; the default ones for a
+// nil context.
+func (c *buildContext) compilerBackends() []backend {
+	if c == nil || len(c.backends) == 0 {
+		return compilerBackends(nil)
+	}
+	return c.backends
+}
+
+// diagnostics returns the report of the build, nil for a nil context.
+func (c *buildContext) diagnostics() *diagnostics.Report {
+	if c == nil {
+		return nil
+	}
+	return c.report
+}
+
+// buildConfig is the configuration a project is compiled with. Name is passed
+// to MSBuild or lazbuild; Profile selects the shared artifact folders, and is
+// the same for the whole build even when a dependency overrides Name.
+type buildConfig struct {
+	Name    string
+	Profile string
+	// Build holds the compiler options of the projects: the build section of
+	// their package followed by Overrides.
+	Build *domain.PackageBuild
+	// Overrides is what the root package sets for the dependency. Binary
+	// packages, built without it, are not used when it is set.
+	Overrides *domain.PackageBuild
+	// Native compiles the projects with fpc whatever their extension: they
+	// are the fpc section of the package.
+	Native bool
+	// Platform is the platform of a multi-platform build, whose artifacts go
+	// to folders of their own; empty for a single-platform build.
+	Platform string
+}
+
+// artifactDir returns the shared artifact folder of the configuration.
+func (c buildConfig) artifactDir(folder string) string {
+	return domain.PlatformArtifactDir(env.GetModulesDir(), folder, c.Platform, c.Profile)
+}
+
+// Build compiles the changed dependencies of the package, as the last step of
+// an install. It fails when the build cannot be planned, such as on a
+// dependency cycle that cycles.break does not declare; projects that fail to
+// compile are reported by the build itself.
+func Build(pkg *domain.Package, options BuildOptions) error {
+	if _, err := Run(pkg, options); err != nil {
+		return fmt.Errorf("build failed: %w", err)
+	}
+	return nil
+}
+
+// Run compiles what options select, dependencies in build order and then the
+// root projects, and returns the name of every dependency or root project
+// that failed to build. The lock entries of the built dependencies are
+// updated in pkg.Lock; saving it is up to the caller.
+//
+// A build for several platforms compiles the same dependencies once per
+// platform, with the artifacts of each in folders of their own.
+func Run(pkg *domain.Package, options BuildOptions) ([]string, error) {
+	profile := buildProfile(pkg, options)
+	platforms, err := matrixPlatforms(pkg, options)
+	if err != nil {
+		return nil, err
+	}
+	markReconfigured(pkg, profile, platforms)
+
+	graph, err := loadDependencyGraph(pkg)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := selectNodes(pkg, graph, options)
+	if err != nil {
+		return nil, err
+	}
+	schedule := buildSchedule{
+		nodes:     nodes,
+		dependsOn: buildDependencies(graph, nodes),
+		workers:   buildWorkers(options.Jobs),
+	}
+	timings := options.Timings
+	if timings == nil {
+		timings = timing.NewRecorder()
+	}
+	report := diagnostics.NewReport()
+
+	var failed []string
+	if len(platforms) == 0 {
+		failed = runPlatform(pkg, schedule, options, profile, "", report, timings)
+	}
+	for _, platform := range platforms {
+		msg.Info("🎯 Platform: %s", platform)
+		platformOptions := options
+		platformOptions.Platform = platform
+		for _, name := range runPlatform(pkg, schedule, platformOptions, profile, platform, report, timings) {
+			failed = append(failed, name+" ("+platform+")")
+		}
+	}
+	reportDiagnostics(report, options)
+
+	if options.Timings == nil {
+		ReportTimings(timings, options.Profile, pkg.Name)
+	}
+	return failed, nil
+}
+
+// runPlatform builds the schedule and the root projects for the platform of
+// options. matrix is the platform of a multi-platform build, empty for a
+// single-platform build.
+func runPlatform(
+	pkg *domain.Package,
+	schedule buildSchedule,
+	options BuildOptions,
+	profile, matrix string,
+	report *diagnostics.Report,
+	timings *timing.Recorder,
+) []string {
+	selected := selectCompiler(pkg, options)
+	msg.Info("   Config: %s", profile)
+	ctx := &buildContext{
+		compiler: selected,
+		profile:  profile,
+		cache:    newBuildCache(options, selected, timings),
+		report:   report,
+		logs:     startBuildLog(selected, profile),
+		timings:  timings,
+		platform: buildPlatform(pkg, options, selected),
+		matrix:   matrix,
+		backends: compilerBackends(pkg.Toolchain),
+	}
+	failed := buildOrderedPackages(pkg, schedule, ctx)
+	ctx.cache.close()
+	if options.Root {
+		failed = append(failed, buildRootProjects(pkg, ctx, buildConfig{
+			Name:     profile,
+			Profile:  profile,
+			Build:    pkg.Build.Merge(nil),
+			Platform: matrix,
+		})...)
+	}
+	finishBuildLog(ctx.logs)
+
+	if queue, err := LoadOrderGraphAll(pkg); err != nil {
+		msg.Warn("⚠️ Failed to save build order: %v", err)
+	} else if err := saveLoadOrder(queue, matrix); err != nil {
+		msg.Warn("⚠️ Failed to save build order: %v", err)
+	}
+	return failed
+}
+
+// matrixPlatforms returns the platforms of a multi-platform build: those of
+// --platform when it lists several, separated by commas, or those of
+// engines.platforms with --matrix. It returns nil for a single-platform
+// build.
+func matrixPlatforms(pkg *domain.Package, options BuildOptions) ([]string, error) {
+	var platforms []string
+	if options.Matrix {
+		if pkg.Engines == nil || len(pkg.Engines.Platforms) == 0 {
+			return nil, errors.New("--matrix needs the platforms of engines.platforms in boss.json")
+		}
+		platforms = pkg.Engines.Platforms
+	} else if strings.Contains(options.Platform, ",") {
+		platforms = strings.Split(options.Platform, ",")
+	}
+
+	var unique []string
+	for _, platform := range platforms {
+		platform = strings.TrimSpace(platform)
+		if platform != "" && !slices.ContainsFunc(unique, func(seen string) bool {
+			return strings.EqualFold(seen, platform)
+		}) {
+			unique = append(unique, platform)
+		}
+	}
+	if len(platforms) > 0 && len(unique) == 0 {
+		return nil, fmt.Errorf("no platform in %q", options.Platform)
+	}
+	return unique, nil
+}
+
+// buildProfile returns the build configuration: --config, then
+// toolchain.config, then DefaultBuildConfig.
+func buildProfile(pkg *domain.Package, options BuildOptions) string {
+	if options.Config != "" {
+		return options.Config
+	}
+	if pkg.Toolchain != nil && pkg.Toolchain.Config != "" {
+		return pkg.Toolchain.Config
+	}
+	return domain.DefaultBuildConfig
+}
+
+// buildPlatform returns the target platform: the one of the selected
+// compiler, then --platform, then toolchain.platform.
+func buildPlatform(pkg *domain.Package, options BuildOptions, selected *compilerselector.SelectedCompiler) string {
+	if selected != nil && selected.Arch != "" {
+		return selected.Arch
+	}
+	if options.Platform != "" {
+		return options.Platform
+	}
+	if pkg.Toolchain != nil {
+		return pkg.Toolchain.Platform
+	}
+	return ""
+}
+
+// dependencyConfig returns the configuration a dependency is compiled with:
+// its toolchain.configs override, or the build profile.
+func dependencyConfig(pkg *domain.Package, dep domain.Dependency, profile string) buildConfig {
+	overrides := rootBuildSettings(pkg, dep)
+	config := buildConfig{Name: profile, Profile: profile, Build: overrides, Overrides: overrides}
+	if pkg.Toolchain == nil {
+		return config
+	}
+	for name, override := range pkg.Toolchain.Configs {
+		if override != "" && matchesModule(dep, name) {
+			config.Name = override
+			break
+		}
+	}
+	return config
+}
+
+// markReconfigured queues for rebuilding the dependencies whose artifacts
+// were built with another configuration or other options from the root
+// package, or not built yet for one of the platforms of a multi-platform
+// build.
+func markReconfigured(pkg *domain.Package, profile string, platforms []string) {
+	for key, locked := range pkg.Lock.Installed {
+		options := overridesDigest(rootBuildSettings(pkg, domain.ParseDependency(key, "")))
+		reconfigured := locked.Options != options
+		if len(platforms) == 0 {
+			reconfigured = reconfigured || !sameConfig(locked.Config, profile)
+		}
+		for _, platform := range platforms {
+			built, ok := locked.Platforms[platform]
+			reconfigured = reconfigured || !ok || !sameConfig(built.Config, profile)
+		}
+		if reconfigured {
+			locked.Changed = true
+			pkg.Lock.Installed[key] = locked
+		}
+	}
+}
+
+func sameConfig(a, b string) bool {
+	if domain.IsDefaultBuildConfig(a) || domain.IsDefaultBuildConfig(b) {
+		return domain.IsDefaultBuildConfig(a) && domain.IsDefaultBuildConfig(b)
+	}
+	return strings.EqualFold(a, b)
+}
+
+// lockedConfig is the value recorded in the lock for a profile.
+func lockedConfig(profile string) string {
+	if domain.IsDefaultBuildConfig(profile) {
+		return ""
+	}
+	return profile
+}
+
+func selectCompiler(pkg *domain.Package, options BuildOptions) *compilerselector.SelectedCompiler {
 	ctx := compilerselector.SelectionContext{
 		Package:            pkg,
-		CliCompilerVersion: compilerVersion,
-		CliPlatform:        platform,
+		CliCompilerVersion: options.Compiler,
+		CliPlatform:        options.Platform,
 	}
 	selected, err := compilerselector.SelectCompiler(ctx)
 	if err != nil {
@@ -35,15 +373,58 @@ func Build(pkg *domain.Package, compilerVersion, platform string) {
 		msg.Info("   Platform: %s", selected.Arch)
 		msg.Info("   Binary: %s", selected.Path)
 	}
+	return selected
+}
 
-	buildOrderedPackages(pkg, selected)
-	graph := LoadOrderGraphAll(pkg)
-	if err := saveLoadOrder(graph); err != nil {
-		msg.Warn("⚠️ Failed to save build order: %v", err)
+// buildSchedule is the set of dependencies to build, in topological order,
+// with what each of them waits for.
+type buildSchedule struct {
+	nodes     []domain.Node
+	dependsOn map[string][]string
+	workers   int
+}
+
+// selectNodes returns the dependencies of graph to build, in build order.
+func selectNodes(pkg *domain.Package, graph *domain.GraphItem, options BuildOptions) ([]domain.Node, error) {
+	if len(options.Modules) == 0 {
+		return drainQueue(graph.Queue(pkg, options.All)), nil
+	}
+
+	all := drainQueue(graph.Queue(pkg, true))
+	for _, name := range options.Modules {
+		if !slices.ContainsFunc(all, func(node domain.Node) bool { return matchesModule(node.Dep, name) }) {
+			return nil, fmt.Errorf("%s is not a dependency of this project", name)
+		}
+	}
+
+	var nodes []domain.Node
+	for _, node := range all {
+		if slices.ContainsFunc(options.Modules, func(name string) bool { return matchesModule(node.Dep, name) }) {
+			nodes = append(nodes, node)
+		}
 	}
+	return nodes, nil
 }
 
-func saveLoadOrder(queue *domain.NodeQueue) error {
+// matchesModule reports whether name designates the dependency: its
+// repository, a path suffix of it, or its module folder.
+func matchesModule(dep domain.Dependency, name string) bool {
+	name = strings.ToLower(strings.Trim(name, "/"))
+	repository := dep.GetKey()
+	return name == repository || strings.HasSuffix(repository, "/"+name) || name == strings.ToLower(dep.Name())
+}
+
+func drainQueue(queue *domain.NodeQueue) []domain.Node {
+	nodes := make([]domain.Node, 0, queue.Size())
+	for !queue.IsEmpty() {
+		nodes = append(nodes, *queue.Dequeue())
+	}
+	return nodes
+}
+
+// saveLoadOrder writes the order the bpls are loaded in to bpl_order.txt, in
+// the bpl folder of the platform of a multi-platform build.
+func saveLoadOrder(queue *domain.NodeQueue, platform string) error {
 	var projects strings.Builder
 	for !queue.IsEmpty() {
 		node := queue.Dequeue()
@@ -56,39 +437,40 @@ func saveLoadOrder(queue *domain.NodeQueue) error {
 			}
 		}
 	}
-	outDir := filepath.Join(env.GetModulesDir(), consts.BplFolder, consts.FileBplOrder)
+	bplDir := domain.PlatformArtifactDir(env.GetModulesDir(), consts.BplFolder, platform, "")
+	outDir := filepath.Join(bplDir, consts.FileBplOrder)
 
+	if err := os.MkdirAll(bplDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", bplDir, err)
+	}
 	if err := os.WriteFile(outDir, []byte(projects.String()), 0600); err != nil {
 		return fmt.Errorf("failed to save build load order to %s: %w", outDir, err)
 	}
 	return nil
 }
 
-func buildOrderedPackages(pkg *domain.Package, selectedCompiler *compilerselector.SelectedCompiler) {
+func buildOrderedPackages(
+	pkg *domain.Package,
+	schedule buildSchedule,
+	ctx *buildContext,
+) []string {
 	_ = pkgmanager.SavePackageCurrent(pkg)
-	queue := loadOrderGraph(pkg)
-	packageNames := extractPackageNames(pkg)
+	packageNames := make([]string, 0, len(schedule.nodes))
+	for _, node := range schedule.nodes {
+		packageNames = append(packageNames, node.Dep.Name())
+	}
 
 	trackerPtr := initializeBuildTracker(packageNames)
 	if len(packageNames) == 0 {
 		msg.Info("📄 No packages to compile.\n")
-		return
+		return nil
 	}
 
-	processPackageQueue(pkg, queue, trackerPtr, selectedCompiler)
+	failed := processPackageQueue(pkg, schedule, trackerPtr, ctx)
 
 	msg.SetQuietMode(false)
 	trackerPtr.Stop()
-}
-
-func extractPackageNames(pkg *domain.Package) []string {
-	var packageNames []string
-	tempQueue := loadOrderGraph(pkg)
-	for !tempQueue.IsEmpty() {
-		node := tempQueue.Dequeue()
-		packageNames = append(packageNames, node.Dep.Name())
-	}
-	return packageNames
+	return failed
 }
 
 func initializeBuildTracker(packageNames []string) *BuildTracker {
@@ -114,62 +496,167 @@ func initializeBuildTracker(packageNames []string) *BuildTracker {
 	return trackerPtr
 }
 
+// processPackageQueue compiles the scheduled dependencies, each as soon as
+// the dependencies it is built against are done, and returns those that
+// failed in build order. Workers read a snapshot of the lock; the updated
+// lock entries are written back one at a time.
 func processPackageQueue(
 	pkg *domain.Package,
-	queue *domain.NodeQueue,
+	schedule buildSchedule,
 	trackerPtr *BuildTracker,
-	selectedCompiler *compilerselector.SelectedCompiler,
-) {
+	ctx *buildContext,
+) []string {
 	fs := filesystem.NewOSFileSystem()
 	artifactMgr := NewDefaultArtifactManager(fs)
 
-	for !queue.IsEmpty() {
-		node := queue.Dequeue()
-		processPackageNode(pkg, node, trackerPtr, selectedCompiler, artifactMgr)
+	var mu sync.Mutex
+	failedNodes := map[string]bool{}
+	err := runSchedule(schedule.nodes, schedule.dependsOn, schedule.workers, func(node domain.Node) {
+		mu.Lock()
+		dependency := pkg.Lock.GetInstalled(node.Dep)
+		lock := pkg.Lock.Snapshot()
+		mu.Unlock()
+
+		config := dependencyConfig(pkg, node.Dep, ctx.profile)
+		config.Platform = ctx.matrix
+		previous := dependency
+		dependency, hasFailed := processPackageNode(&node, dependency, lock, trackerPtr, ctx, artifactMgr, config)
+		if ctx.matrix != "" {
+			dependency = recordPlatform(previous, dependency, ctx.matrix)
+		}
+
+		mu.Lock()
+		pkg.Lock.SetInstalled(node.Dep, dependency)
+		failedNodes[node.Value] = hasFailed
+		mu.Unlock()
+	})
+	if err != nil {
+		msg.Err("❌ %v", err)
 	}
+
+	var failed []string
+	for _, node := range schedule.nodes {
+		if hasFailed, done := failedNodes[node.Value]; hasFailed || !done {
+			failed = append(failed, node.Dep.Name())
+		}
+	}
+	return failed
+}
+
+// recordPlatform moves what a build for a platform of a multi-platform build
+// recorded in the lock entry of a dependency to its Platforms, keeping the
+// artifacts of single-platform builds of previous.
+func recordPlatform(previous, built domain.LockedDependency, platform string) domain.LockedDependency {
+	built.SetArtifactsFor(platform, built.ArtifactsFor(""))
+	built.SetArtifactsFor("", previous.ArtifactsFor(""))
+	return built
 }
 
+// processPackageNode installs the matching binary package of a dependency, or
+// restores it from the build cache, or compiles its projects, and returns its
+// updated lock entry and whether a project failed.
 func processPackageNode(
-	pkg *domain.Package,
 	node *domain.Node,
+	dependency domain.LockedDependency,
+	lock domain.PackageLock,
 	trackerPtr *BuildTracker,
-	selectedCompiler *compilerselector.SelectedCompiler,
+	ctx *buildContext,
 	artifactMgr *DefaultArtifactManager,
-) {
+	config buildConfig,
+) (domain.LockedDependency, bool) {
 	dependencyPath := filepath.Join(env.GetModulesDir(), node.Dep.Name())
-	dependency := pkg.Lock.GetInstalled(node.Dep)
 
 	reportBuildStart(trackerPtr, node.Dep.Name())
 
 	dependency.Changed = false
+	dependency.Config = lockedConfig(config.Profile)
+	dependency.Options = overridesDigest(config.Overrides)
+	dependency.Prebuilt = ""
 	dependencyPackage, err := pkgmanager.LoadPackageOther(filepath.Join(dependencyPath, consts.FilePackage))
 
 	if err != nil {
 		reportNoBossJSON(trackerPtr, node.Dep.Name())
-		pkg.Lock.SetInstalled(node.Dep, dependency)
-		return
+		return dependency, false
 	}
+	config.Build = dependencyPackage.Build.Merge(config.Build)
 
-	if len(dependencyPackage.Projects) == 0 {
+	if digest, ok := installPrebuilt(node.Dep, dependencyPackage, ctx, config); ok {
+		dependency.Prebuilt = digest
+		moveArtifacts(&dependency, node.Dep, artifactMgr, ctx, config)
+		reportPrebuilt(trackerPtr, node.Dep.Name())
+		return dependency, false
+	}
+
+	projects, native := packageProjects(dependencyPackage)
+	if len(projects) == 0 {
 		reportNoProjects(trackerPtr, node.Dep.Name())
-		pkg.Lock.SetInstalled(node.Dep, dependency)
-		return
+		return dependency, false
+	}
+	config.Native = native
+
+	key := ctx.cache.key(node.Dep, dependencyPackage, lock, config)
+	if ctx.cache.restore(key, node.Dep) {
+		moveArtifacts(&dependency, node.Dep, artifactMgr, ctx, config)
+		reportRestored(trackerPtr, node.Dep.Name())
+		return dependency, false
 	}
 
 	hasFailed := buildProjectsForDependency(
 		&dependency,
 		node.Dep,
-		dependencyPackage.Projects,
+		projects,
 		trackerPtr,
-		selectedCompiler,
-		pkg.Lock,
+		ctx,
+		lock,
+		config,
 	)
+	if !hasFailed {
+		ctx.cache.save(key, node.Dep)
+	}
+
+	moveArtifacts(&dependency, node.Dep, artifactMgr, ctx, config)
+
+	reportBuildResult(trackerPtr, node.Dep.Name(), hasFailed, ctx.report)
+	return dependency, hasFailed
+}
+
+// moveArtifacts records the artifacts of a dependency in its lock entry and
+// moves them to the shared folders of the configuration.
+func moveArtifacts(
+	dependency *domain.LockedDependency,
+	dep domain.Dependency,
+	artifactMgr *DefaultArtifactManager,
+	ctx *buildContext,
+	config buildConfig,
+) {
+	defer ctx.timeStep(timing.StepArtifacts, dep.Name(), "")()
+	artifactMgr.EnsureArtifacts(dependency, dep, env.GetModulesDir())
+	artifactMgr.MovePlatformArtifacts(dep, env.GetModulesDir(), config.Platform, config.Profile)
+}
 
-	artifactMgr.EnsureArtifacts(&dependency, node.Dep, env.GetModulesDir())
-	artifactMgr.MoveArtifacts(node.Dep, env.GetModulesDir())
+// buildRootProjects compiles the projects of the root boss.json once the
+// dependencies are built, and returns those that failed.
+func buildRootProjects(
+	pkg *domain.Package,
+	ctx *buildContext,
+	config buildConfig,
+) []string {
+	projects, native := packageProjects(pkg)
+	if len(projects) == 0 {
+		msg.Info("📄 The root package has no projects to compile.")
+		return nil
+	}
 
-	reportBuildResult(trackerPtr, node.Dep.Name(), hasFailed)
-	pkg.Lock.SetInstalled(node.Dep, dependency)
+	msg.Info("🔨 Building %s", pkg.Name)
+	config.Native = native
+	var failed []string
+	for _, project := range projects {
+		projectPath := filepath.Join(env.GetCurrentDir(), project)
+		if !compile(projectPath, nil, pkg.Lock, nil, ctx, config) {
+			failed = append(failed, project)
+		}
+	}
+	return failed
 }
 
 func buildProjectsForDependency(
@@ -177,8 +664,9 @@ func buildProjectsForDependency(
 	dep domain.Dependency,
 	projects []string,
 	trackerPtr *BuildTracker,
-	selectedCompiler *compilerselector.SelectedCompiler,
+	ctx *buildContext,
 	lock domain.PackageLock,
+	config buildConfig,
 ) bool {
 	hasFailed := false
 	for _, dproj := range projects {
@@ -190,7 +678,7 @@ func buildProjectsForDependency(
 			msg.Info("  🔥 Compiling project: %s", filepath.Base(dproj))
 		}
 
-		if !compile(dprojPath, &dep, lock, trackerPtr, selectedCompiler) {
+		if !compile(dprojPath, &dep, lock, trackerPtr, ctx, config) {
 			dependency.Failed = true
 			hasFailed = true
 		}
@@ -206,11 +694,11 @@ func reportBuildStart(trackerPtr *BuildTracker, depName string) {
 	}
 }
 
-func reportBuildResult(trackerPtr *BuildTracker, depName string, hasFailed bool) {
+func reportBuildResult(trackerPtr *BuildTracker, depName string, hasFailed bool, report *diagnostics.Report) {
 	//nolint:nestif // Complex compiler logic requires nesting
 	if trackerPtr.IsEnabled() {
 		if hasFailed {
-			trackerPtr.SetFailed(depName, consts.StatusMsgBuildError)
+			trackerPtr.SetFailed(depName, firstError(report, depName, consts.StatusMsgBuildError))
 		} else {
 			trackerPtr.SetSuccess(depName)
 		}
@@ -223,6 +711,22 @@ func reportBuildResult(trackerPtr *BuildTracker, depName string, hasFailed bool)
 	}
 }
 
+func reportPrebuilt(trackerPtr *BuildTracker, depName string) {
+	if trackerPtr.IsEnabled() {
+		trackerPtr.SetPrebuilt(depName)
+	} else {
+		msg.Info("  📦 %s installed from its binary package", depName)
+	}
+}
+
+func reportRestored(trackerPtr *BuildTracker, depName string) {
+	if trackerPtr.IsEnabled() {
+		trackerPtr.SetRestored(depName)
+	} else {
+		msg.Info("  ♻️ %s restored from the build cache", depName)
+	}
+}
+
 func reportNoProjects(trackerPtr *BuildTracker, depName string) {
 	if trackerPtr.IsEnabled() {
 		trackerPtr.SetSkipped(depName, consts.StatusMsgNoProjects)
@@ -5,9 +5,14 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/binarypkg"
+	"github.com/hashload/boss/internal/core/services/compilerselector"
+	"github.com/hashload/boss/internal/core/services/diagnostics"
 	"github.com/hashload/boss/internal/infra"
 	"github.com/hashload/boss/pkg/consts"
 )
@@ -44,7 +49,7 @@ func TestGetCompilerParameters(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getCompilerParameters(tt.rootPath, tt.dep, tt.platform)
+			result := getCompilerParameters(tt.rootPath, tt.dep, tt.platform, buildConfig{Name: "Debug", Profile: "Debug"})
 
 			if tt.wantBpl && !containsStr(result, "DCC_BplOutput") {
 				t.Error("Expected DCC_BplOutput in parameters")
@@ -133,13 +138,173 @@ func TestMoveArtifacts(t *testing.T) {
 	// Test move using the artifact manager
 	fs := &OSFileSystemWrapper{}
 	artifactMgr := NewDefaultArtifactManager(fs)
-	artifactMgr.MoveArtifacts(dep, tmpDir)
+	artifactMgr.MoveArtifacts(dep, tmpDir, domain.DefaultBuildConfig)
 
 	// Verify file was moved
 	destFile := filepath.Join(destBplDir, "test.bpl")
 	if _, err := os.Stat(destFile); os.IsNotExist(err) {
 		t.Error("Expected file to be moved to destination")
 	}
+
+	// Another configuration goes to its own subfolder, leaving Debug alone.
+	_ = os.MkdirAll(bplDir, 0755)
+	_ = os.WriteFile(testFile, []byte("release"), 0600)
+	artifactMgr.MoveArtifacts(dep, tmpDir, "Release")
+	data, err := os.ReadFile(filepath.Join(destBplDir, "Release", "test.bpl"))
+	if err != nil || string(data) != "release" {
+		t.Errorf("Expected the Release artifact in its own folder, got %q, %v", data, err)
+	}
+	if data, _ := os.ReadFile(destFile); string(data) != "test" {
+		t.Error("The Release build must not overwrite the Debug artifact")
+	}
+}
+
+func TestGetCompilerParameters_Config(t *testing.T) {
+	release := buildConfig{Name: "Release", Profile: "Release"}
+	root := getCompilerParameters("/test/modules", nil, consts.PlatformWin32.String(), release)
+	if !containsStr(root, "/p:config=Release") {
+		t.Errorf("Expected the Release configuration in %s", root)
+	}
+	if !containsStr(root, filepath.Join("/test/modules", consts.BplFolder, "Release")) {
+		t.Errorf("Expected root output in the Release folder, got %s", root)
+	}
+
+	dep := &domain.Dependency{Repository: "github.com/test/lib"}
+	override := buildConfig{Name: "Production", Profile: "Release"}
+	params := getCompilerParameters("/test/modules", dep, consts.PlatformWin32.String(), override)
+	if !containsStr(params, "/p:config=Production") ||
+		!containsStr(params, filepath.Join("/test/modules", dep.Name(), consts.BplFolder)) {
+		t.Errorf("Expected the overridden configuration and module output, got %s", params)
+	}
+}
+
+func TestDependencyConfig(t *testing.T) {
+	pkg := domain.NewPackage()
+	pkg.Toolchain = &domain.PackageToolchain{
+		Config:  "Release",
+		Configs: map[string]string{"hashload/horse": "Production"},
+	}
+	if got := buildProfile(pkg, BuildOptions{}); got != "Release" {
+		t.Errorf("buildProfile() = %s, want toolchain.config", got)
+	}
+	if got := buildProfile(pkg, BuildOptions{Config: "Debug"}); got != "Debug" {
+		t.Errorf("buildProfile() = %s, want --config", got)
+	}
+
+	horse := domain.ParseDependency("github.com/hashload/horse", "^3.0.0")
+	if got := dependencyConfig(pkg, horse, "Debug"); got.Name != "Production" || got.Profile != "Debug" {
+		t.Errorf("dependencyConfig(horse) = %+v", got)
+	}
+	jhonson := domain.ParseDependency("github.com/hashload/jhonson", "^1.0.0")
+	if got := dependencyConfig(pkg, jhonson, "Release"); got.Name != "Release" {
+		t.Errorf("dependencyConfig(jhonson) = %+v", got)
+	}
+}
+
+func TestMarkReconfigured(t *testing.T) {
+	pkg := domain.NewPackage()
+	pkg.Lock.Installed = map[string]domain.LockedDependency{
+		"github.com/test/debug":   {Name: "debug"},
+		"github.com/test/release": {Name: "release", Config: "release"},
+	}
+	markReconfigured(pkg, "Release", nil)
+	if !pkg.Lock.Installed["github.com/test/debug"].Changed || pkg.Lock.Installed["github.com/test/release"].Changed {
+		t.Errorf("only the Debug build should be queued: %+v", pkg.Lock.Installed)
+	}
+
+	pkg.Build = &domain.PackageBuild{Dependencies: map[string]*domain.PackageBuild{
+		"release": {Defines: []string{"USE_FIREDAC"}},
+	}}
+	markReconfigured(pkg, "Release", nil)
+	if !pkg.Lock.Installed["github.com/test/release"].Changed {
+		t.Error("a dependency should be queued when the root package changes its build options")
+	}
+}
+
+func TestMarkReconfigured_Platforms(t *testing.T) {
+	pkg := domain.NewPackage()
+	pkg.Lock.Installed = map[string]domain.LockedDependency{
+		"github.com/test/both": {Name: "both", Platforms: map[string]domain.PlatformArtifacts{
+			"Win32": {}, "Win64": {},
+		}},
+		"github.com/test/win32":  {Name: "win32", Platforms: map[string]domain.PlatformArtifacts{"Win32": {}}},
+		"github.com/test/single": {Name: "single"},
+	}
+	markReconfigured(pkg, domain.DefaultBuildConfig, []string{"Win32", "Win64"})
+	if pkg.Lock.Installed["github.com/test/both"].Changed {
+		t.Error("a dependency built for every platform should not be queued")
+	}
+	if !pkg.Lock.Installed["github.com/test/win32"].Changed || !pkg.Lock.Installed["github.com/test/single"].Changed {
+		t.Errorf("the dependencies missing a platform should be queued: %+v", pkg.Lock.Installed)
+	}
+}
+
+func TestMatrixPlatforms(t *testing.T) {
+	pkg := domain.NewPackage()
+	if platforms, err := matrixPlatforms(pkg, BuildOptions{Platform: "Win64"}); err != nil || platforms != nil {
+		t.Errorf("matrixPlatforms(Win64) = %v, %v; want a single-platform build", platforms, err)
+	}
+	platforms, err := matrixPlatforms(pkg, BuildOptions{Platform: "Win32, Win64,win32"})
+	if err != nil || !slices.Equal(platforms, []string{"Win32", "Win64"}) {
+		t.Errorf("matrixPlatforms(Win32, Win64,win32) = %v, %v", platforms, err)
+	}
+	if _, err = matrixPlatforms(pkg, BuildOptions{Matrix: true}); err == nil {
+		t.Error("--matrix should fail without engines.platforms")
+	}
+	pkg.Engines = &domain.PackageEngines{Platforms: []string{"Win32", "Linux64"}}
+	if platforms, _ = matrixPlatforms(pkg, BuildOptions{Matrix: true}); !slices.Equal(platforms, pkg.Engines.Platforms) {
+		t.Errorf("matrixPlatforms(--matrix) = %v, want engines.platforms", platforms)
+	}
+}
+
+func TestRecordPlatform(t *testing.T) {
+	previous := domain.LockedDependency{
+		Config:    "",
+		Artifacts: domain.DependencyArtifacts{Bpl: []string{"Single.bpl"}},
+		Platforms: map[string]domain.PlatformArtifacts{"Win32": {}},
+	}
+	built := previous
+	built.Config = "Release"
+	built.Artifacts = domain.DependencyArtifacts{Bpl: []string{"Win64.bpl"}}
+
+	recorded := recordPlatform(previous, built, "Win64")
+	if recorded.Config != "" || !slices.Equal(recorded.Artifacts.Bpl, []string{"Single.bpl"}) {
+		t.Errorf("recordPlatform() changed the single-platform artifacts: %+v", recorded)
+	}
+	win64 := recorded.Platforms["Win64"]
+	if win64.Config != "Release" || !slices.Equal(win64.Artifacts.Bpl, []string{"Win64.bpl"}) {
+		t.Errorf("Platforms[Win64] = %+v", win64)
+	}
+	if len(previous.Platforms) != 1 {
+		t.Error("recordPlatform() must not modify the Platforms of previous")
+	}
+}
+
+func TestBuildOptions(t *testing.T) {
+	pkg := domain.NewPackage()
+	pkg.Build = &domain.PackageBuild{Dependencies: map[string]*domain.PackageBuild{
+		"hashload/horse": {Defines: []string{"HORSE_VCL"}, FpcFlags: []string{"-O2"}},
+		"*":              {Defines: []string{"CI"}, WarningsAsErrors: true},
+		"jhonson":        {Defines: []string{"UNUSED"}},
+	}}
+	horse := domain.ParseDependency("github.com/hashload/horse", "^3.0.0")
+	config := dependencyConfig(pkg, horse, "Debug")
+	if config.Overrides == nil {
+		t.Fatal("dependencyConfig() should carry the settings of the root package")
+	}
+
+	own := &domain.PackageBuild{Defines: []string{"USE_FIREDAC"}, UnitScopes: []string{"System", "Vcl"},
+		DccFlags: []string{"-$O+"}}
+	build := own.Merge(config.Overrides)
+	if got := strings.Join(dccOptions(build), " "); got != "-DUSE_FIREDAC;CI;HORSE_VCL -NSSystem;Vcl -W^ -$O+" {
+		t.Errorf("dccOptions() = %s", got)
+	}
+	if got := strings.Join(fpcOptions(build), " "); got != "-dUSE_FIREDAC -dCI -dHORSE_VCL -FNSystem -FNVcl -Sew -O2" {
+		t.Errorf("fpcOptions() = %s", got)
+	}
+	if dccOptions((*domain.PackageBuild)(nil).Merge(&domain.PackageBuild{})) != nil {
+		t.Error("an empty build section should add no options")
+	}
 }
 
 // OSFileSystemWrapper wraps os package functions for testing.
@@ -236,3 +401,181 @@ func TestEnsureArtifacts(t *testing.T) {
 
 	// The function should have collected artifacts
 }
+
+func TestMatchesModule(t *testing.T) {
+	dep := domain.ParseDependency("github.com/HashLoad/horse", "^3.0.0")
+	tests := map[string]bool{
+		"github.com/hashload/horse": true,
+		"hashload/horse":            true,
+		"Horse":                     true,
+		"github_com_hashload_horse": true,
+		"orse":                      false,
+		"hashload/jhonson":          false,
+	}
+	for name, want := range tests {
+		if got := matchesModule(dep, name); got != want {
+			t.Errorf("matchesModule(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestArtifactsDigest(t *testing.T) {
+	t.Chdir(t.TempDir())
+	bplDir := domain.ArtifactDir(filepath.Join(".", consts.FolderDependencies), consts.BplFolder, "Release")
+	if err := os.MkdirAll(bplDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	bpl := filepath.Join(bplDir, "Horse.bpl")
+	if err := os.WriteFile(bpl, []byte("v1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	locked := domain.LockedDependency{Artifacts: domain.DependencyArtifacts{Bpl: []string{"Horse.bpl"}}}
+
+	release := buildConfig{Name: "Release", Profile: "Release"}
+	first, err := artifactsDigest(locked, release)
+	if err != nil {
+		t.Fatalf("artifactsDigest() error = %v", err)
+	}
+	if err = os.WriteFile(bpl, []byte("v2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := artifactsDigest(locked, release); second == first {
+		t.Error("artifactsDigest() should change when an artifact changes")
+	}
+	if _, err = artifactsDigest(locked, buildConfig{Profile: domain.DefaultBuildConfig}); err == nil {
+		t.Error("artifactsDigest() should fail when an artifact is missing")
+	}
+}
+
+func TestRootArtifacts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	bplDir := filepath.Join(consts.FolderDependencies, consts.BplFolder)
+	for _, name := range []string{"Horse.bpl", "MyLib.bpl", consts.FileBplOrder} {
+		if err := os.MkdirAll(bplDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(bplDir, name), []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lock := domain.PackageLock{Installed: map[string]domain.LockedDependency{
+		"github.com/hashload/horse": {Artifacts: domain.DependencyArtifacts{Bpl: []string{"Horse.bpl"}}},
+	}}
+
+	artifacts, err := rootArtifacts(lock, domain.DefaultBuildConfig, "")
+	if err != nil {
+		t.Fatalf("rootArtifacts() error = %v", err)
+	}
+	if len(artifacts) != 1 || artifacts[".bpl/MyLib.bpl"] == "" {
+		t.Errorf("rootArtifacts() = %v, want only the root project's bpl", artifacts)
+	}
+}
+
+func TestRootArtifacts_Platform(t *testing.T) {
+	t.Chdir(t.TempDir())
+	win64Dir := domain.PlatformArtifactDir(modulesDir(), consts.BplFolder, "Win64", "Release")
+	if err := os.MkdirAll(win64Dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Horse.bpl", "MyLib.bpl"} {
+		if err := os.WriteFile(filepath.Join(win64Dir, name), []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	locked := domain.LockedDependency{}
+	locked.SetArtifactsFor("Win64", domain.PlatformArtifacts{
+		Config:    "Release",
+		Artifacts: domain.DependencyArtifacts{Bpl: []string{"Horse.bpl"}},
+	})
+	lock := domain.PackageLock{Installed: map[string]domain.LockedDependency{"github.com/hashload/horse": locked}}
+
+	artifacts, err := rootArtifacts(lock, "Release", "Win64")
+	if err != nil {
+		t.Fatalf("rootArtifacts() error = %v", err)
+	}
+	if len(artifacts) != 1 || artifacts[".bpl/MyLib.bpl"] != filepath.Join(win64Dir, "MyLib.bpl") {
+		t.Errorf("rootArtifacts() = %v, want only the root project's Win64 bpl", artifacts)
+	}
+	if artifacts, _ = rootArtifacts(lock, "Release", "Win32"); len(artifacts) != 0 {
+		t.Errorf("rootArtifacts() = %v, want nothing for a platform that was not built", artifacts)
+	}
+}
+
+func TestInstallPrebuilt(t *testing.T) {
+	t.Chdir(t.TempDir())
+	dep := domain.ParseDependency("github.com/acme/grid", "1.0.0")
+	moduleDir := filepath.Join(consts.FolderDependencies, dep.Name())
+	if err := os.MkdirAll(moduleDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	bpl := filepath.Join(t.TempDir(), "Grid.bpl")
+	if err := os.WriteFile(bpl, []byte("bpl"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := binarypkg.Pack(binarypkg.Manifest{
+		Name:    "grid",
+		Engines: domain.PackageEngines{Compiler: "37.0", Platforms: []string{"Win64"}},
+	}, map[string]string{".bpl/Grid.bpl": bpl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(moduleDir, "grid.zip"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	dependencyPackage := &domain.Package{Binaries: []domain.PackageBinary{
+		{Compiler: "37.0", Platform: "Win64", URL: "grid.zip", SHA256: binarypkg.Digest(data)},
+	}}
+	debug := buildConfig{Name: "Debug", Profile: "Debug"}
+	win32 := &buildContext{compiler: &compilerselector.SelectedCompiler{Version: "37.0", Arch: "Win32"}}
+	if _, ok := installPrebuilt(dep, dependencyPackage, win32, debug); ok {
+		t.Error("installPrebuilt() should not use a binary of another platform")
+	}
+
+	win64 := &buildContext{compiler: &compilerselector.SelectedCompiler{Version: "37.0", Arch: "Win64"}}
+	digest, ok := installPrebuilt(dep, dependencyPackage, win64, debug)
+	if !ok || digest != binarypkg.Digest(data) {
+		t.Fatalf("installPrebuilt() = %s, %t", digest, ok)
+	}
+	if _, err = os.Stat(filepath.Join(moduleDir, consts.BplFolder, "Grid.bpl")); err != nil {
+		t.Errorf("the binaries should be extracted into the module folder: %v", err)
+	}
+}
+
+func TestFirstError(t *testing.T) {
+	report := diagnostics.NewReport()
+	report.Add("github_com_acme_grid", "Grid.dproj", []diagnostics.Diagnostic{
+		{Severity: diagnostics.SeverityWarning, Unit: "Grid", Line: 3, Message: "deprecated"},
+		{Severity: diagnostics.SeverityError, Unit: "Grid.Core", Line: 12, Message: "Undeclared identifier: 'x'"},
+	})
+
+	got := firstError(report, "github_com_acme_grid", "build error")
+	if got != "Grid.Core(12): Undeclared identifier: 'x'" {
+		t.Errorf("firstError() = %q", got)
+	}
+	if got := firstError(report, "github_com_acme_other", "build error"); got != "build error" {
+		t.Errorf("firstError() without errors = %q, want the fallback", got)
+	}
+}
+
+func TestLogProject(t *testing.T) {
+	t.Chdir(t.TempDir())
+	dep := domain.ParseDependency("github.com/test/lib", "^1.0.0")
+	moduleDir := filepath.Join(modulesDir(), dep.Name())
+
+	tests := []struct {
+		dep     *domain.Dependency
+		project string
+		want    string
+	}{
+		{&dep, filepath.Join(moduleDir, "packages", "D11", "Lib.dproj"), "packages/D11/Lib.dproj"},
+		{&dep, filepath.Join(moduleDir, "Lib.dproj"), "Lib.dproj"},
+		{nil, filepath.Join("src", "App.dproj"), "src/App.dproj"},
+		{&dep, filepath.Join(t.TempDir(), "Other.dproj"), "Other.dproj"},
+	}
+	for _, tt := range tests {
+		if got := logProject(tt.dep, tt.project); got != tt.want {
+			t.Errorf("logProject(%s) = %q, want %q", tt.project, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package compiler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+	"github.com/hashload/boss/pkg/pkgmanager"
+	"github.com/hashload/boss/utils/dcp"
+)
+
+// delphiBackend builds Delphi projects with MSBuild, from a batch file that
+// loads rsvars.bat first. It builds every project no other backend supports.
+type delphiBackend struct{}
+
+func (delphiBackend) name(job compileJob) string {
+	return compilerVersion(job.compiler)
+}
+
+func (delphiBackend) supports(string) bool {
+	return true
+}
+
+func (delphiBackend) compile(job compileJob) error {
+	// A nil dep is a project of the root package, which keeps its own requires.
+	if job.dep != nil {
+		bossPackagePath := filepath.Join(env.GetModulesDir(), job.dep.Name(), consts.FilePackage)
+		if dependencyPackage, err := pkgmanager.LoadPackageOther(bossPackagePath); err == nil {
+			dcp.InjectDpcsFile(job.project, dependencyPackage, job.lock)
+		}
+	}
+
+	dccDir := env.GetDcc32Dir()
+	platform := job.delphiPlatform()
+	if job.compiler != nil {
+		dccDir = job.compiler.BinDir
+	}
+	if job.verbose {
+		msg.Debug("  🛠️ Using: %s (Platform: %s)", filepath.Join(dccDir, dccBinary(platform)), platform)
+	}
+
+	fileRes := "build_boss_" + strings.TrimSuffix(filepath.Base(job.project), filepath.Ext(job.project))
+	abs := filepath.Dir(job.project)
+	buildBat := filepath.Join(abs, fileRes+".bat")
+	cfgPath := filepath.Join(abs, "boss.cfg")
+
+	// Create boss.cfg to hold search paths and avoid command-line too long errors (Issue #205)
+	if err := os.WriteFile(cfgPath, []byte(dccConfig(job)), 0600); err != nil {
+		return fmt.Errorf("creating compiler configuration file: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(cfgPath); err != nil {
+			msg.Debug("Could not remove boss.cfg %s: %v", cfgPath, err)
+		}
+	}()
+
+	rsvars := filepath.Join(dccDir, "rsvars.bat")
+	readFile, err := os.ReadFile(rsvars) // #nosec G304 -- Reading Delphi environment variables file from known location
+	if err != nil {
+		msg.Err("    ❌ Error on read rsvars.bat")
+	}
+
+	var scriptBuilder strings.Builder
+	scriptBuilder.Write(readFile)
+	scriptBuilder.WriteString("\n@SET PATH=%PATH%;")
+	scriptBuilder.WriteString(job.config.artifactDir(consts.BplFolder))
+	scriptBuilder.WriteString(";")
+	scriptBuilder.WriteString(" \n msbuild \"")
+	scriptBuilder.WriteString(job.project)
+	scriptBuilder.WriteString("\" /p:Configuration=" + job.config.Name + " ")
+	scriptBuilder.WriteString(getCompilerParameters(env.GetModulesDir(), job.dep, platform, job.config))
+	scriptBuilder.WriteString(" /p:DCC_AdditionalParameters=\"@")
+	scriptBuilder.WriteString(cfgPath)
+	scriptBuilder.WriteString("\"")
+	scriptBuilder.WriteString(" > \"")
+	scriptBuilder.WriteString(job.log)
+	scriptBuilder.WriteString("\"")
+
+	if err := os.WriteFile(buildBat, []byte(scriptBuilder.String()), 0600); err != nil {
+		return fmt.Errorf("creating build file: %w", err)
+	}
+
+	// #nosec G204 -- Executing controlled build script generated by Boss
+	command := exec.CommandContext(context.Background(), buildBat)
+	command.Dir = abs
+	if _, err := command.Output(); err != nil {
+		return fmt.Errorf("running %s: %w", filepath.Base(buildBat), err)
+	}
+	if err := os.Remove(buildBat); err != nil {
+		msg.Debug("Could not remove build script %s: %v", buildBat, err)
+	}
+	return nil
+}
+
+// dccConfig returns the content of boss.cfg: the search paths of the shared
+// folders and of the dependency, then the options of its build section.
+func dccConfig(job compileJob) string {
+	var cfgContent strings.Builder
+	dcuPath := job.config.artifactDir(consts.DcuFolder)
+	dcpPath := job.config.artifactDir(consts.DcpFolder)
+	fmt.Fprintf(&cfgContent, "-I\"%s\"\n-U\"%s\"\n", dcuPath, dcuPath)
+	fmt.Fprintf(&cfgContent, "-I\"%s\"\n-U\"%s\"\n", dcpPath, dcpPath)
+
+	for _, option := range dccOptions(job.config.Build) {
+		cfgContent.WriteString(option + "\n")
+	}
+
+	for _, p := range searchPaths(job.dep) {
+		fmt.Fprintf(&cfgContent, "-I\"%s\"\n-U\"%s\"\n", p, p)
+	}
+	return cfgContent.String()
+}
+
+// dccBinary returns the command-line compiler of a platform.
+func dccBinary(platform string) string {
+	switch platform {
+	case consts.PlatformWin64.String():
+		return "dcc64.exe"
+	case consts.PlatformOSX64.String():
+		return "dccosx.exe"
+	case consts.PlatformLinux64.String():
+		return "dcclinux64.exe"
+	default:
+		return "dcc32.exe"
+	}
+}
@@ -1,11 +1,16 @@
 package compiler
 
 import (
+	"errors"
+	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/hashload/boss/internal/core/domain"
 	"github.com/hashload/boss/pkg/consts"
 	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
 	"github.com/hashload/boss/pkg/pkgmanager"
 )
 
@@ -13,51 +18,147 @@ import (
 type DefaultGraphBuilder struct{}
 
 // LoadOrderGraph loads the dependency graph for changed packages only.
-func (d *DefaultGraphBuilder) LoadOrderGraph(pkg *domain.Package) *domain.NodeQueue {
+func (d *DefaultGraphBuilder) LoadOrderGraph(pkg *domain.Package) (*domain.NodeQueue, error) {
 	return loadOrderGraph(pkg)
 }
 
 // LoadOrderGraphAll loads the complete dependency graph.
-func (d *DefaultGraphBuilder) LoadOrderGraphAll(pkg *domain.Package) *domain.NodeQueue {
+func (d *DefaultGraphBuilder) LoadOrderGraphAll(pkg *domain.Package) (*domain.NodeQueue, error) {
 	return LoadOrderGraphAll(pkg)
 }
 
-func loadOrderGraph(pkg *domain.Package) *domain.NodeQueue {
-	var graph domain.GraphItem
-	deps := pkg.GetParsedDependencies()
-	loadGraph(&graph, nil, deps, nil)
-	return graph.Queue(pkg, false)
+func loadOrderGraph(pkg *domain.Package) (*domain.NodeQueue, error) {
+	graph, err := loadDependencyGraph(pkg)
+	if err != nil {
+		return nil, err
+	}
+	return graph.Queue(pkg, false), nil
 }
 
 // LoadOrderGraphAll loads the dependency graph for all dependencies.
-func LoadOrderGraphAll(pkg *domain.Package) *domain.NodeQueue {
-	var graph domain.GraphItem
-	deps := pkg.GetParsedDependencies()
-	loadGraph(&graph, nil, deps, nil)
-	return graph.Queue(pkg, true)
+func LoadOrderGraphAll(pkg *domain.Package) (*domain.NodeQueue, error) {
+	graph, err := loadDependencyGraph(pkg)
+	if err != nil {
+		return nil, err
+	}
+	return graph.Queue(pkg, true), nil
 }
 
-func loadGraph(graph *domain.GraphItem, dep *domain.Dependency, deps []domain.Dependency, father *domain.Node) {
-	var localFather *domain.Node
-	if dep != nil {
-		localFather = domain.NewNode(dep)
-		graph.AddNode(localFather)
-	}
+// loadDependencyGraph loads the graph of the installed dependencies. Cycles
+// are broken at the edges of cycles.break in the root package; any other
+// cycle is an error.
+func loadDependencyGraph(pkg *domain.Package) (*domain.GraphItem, error) {
+	graph := LoadGraph(pkg)
 
-	if father != nil {
-		graph.AddEdge(father, localFather)
+	var declared []domain.PackageEdge
+	if pkg.Cycles != nil {
+		declared = pkg.Cycles.Break
+	}
+	if err := breakCycles(graph, declared); err != nil {
+		return nil, err
 	}
+	return graph, nil
+}
+
+// LoadGraph loads the graph of the dependencies of pkg as their boss.json
+// files declare it, cycles included. The node an edge leads to carries the
+// version constraint of the declaration.
+func LoadGraph(pkg *domain.Package) *domain.GraphItem {
+	loader := newGraphLoader(pkgmanager.LoadPackageOther)
+	loader.add(nil, pkg.GetParsedDependencies())
+	return loader.graph
+}
+
+// graphLoader adds the installed dependencies to a graph, reading the
+// boss.json of each of them once however many packages depend on it.
+type graphLoader struct {
+	graph *domain.GraphItem
+	// load reads the boss.json at a path.
+	load func(path string) (*domain.Package, error)
+	// expanded holds the nodes whose dependencies were already added, so
+	// that a cycle ends there.
+	expanded map[string]bool
+}
+
+func newGraphLoader(load func(path string) (*domain.Package, error)) *graphLoader {
+	return &graphLoader{graph: &domain.GraphItem{}, load: load, expanded: map[string]bool{}}
+}
 
+// add adds deps to the graph as dependencies of father, nil for the root
+// package, followed by what each of them depends on when it is installed.
+func (l *graphLoader) add(father *domain.Node, deps []domain.Dependency) {
 	for _, dep := range deps {
-		pkgModule, err := pkgmanager.LoadPackageOther(filepath.Join(env.GetModulesDir(), dep.Name(), consts.FilePackage))
-		if err != nil {
-			node := domain.NewNode(&dep)
-			graph.AddNode(node)
-			if localFather != nil {
-				graph.AddEdge(localFather, node)
+		node := domain.NewNode(&dep)
+		l.graph.AddNode(node)
+		if father != nil {
+			l.graph.AddEdge(father, node)
+		}
+		if l.expanded[node.Value] {
+			continue
+		}
+		l.expanded[node.Value] = true
+
+		if pkgModule, err := l.load(filepath.Join(env.GetModulesDir(), dep.Name(), consts.FilePackage)); err == nil {
+			l.add(node, pkgModule.GetParsedDependencies())
+		}
+	}
+}
+
+// breakCycles removes from graph an edge of declared in each of its cycles,
+// or returns an error describing the first cycle without one.
+func breakCycles(graph *domain.GraphItem, declared []domain.PackageEdge) error {
+	for {
+		cycles := graph.Cycles()
+		if len(cycles) == 0 {
+			return nil
+		}
+		for _, cycle := range cycles {
+			if declaredEdge(cycle, declared) < 0 {
+				return cycleError(cycle)
 			}
-		} else {
-			loadGraph(graph, &dep, pkgModule.GetParsedDependencies(), localFather)
 		}
+
+		cycle := cycles[0]
+		edge := declaredEdge(cycle, declared)
+		msg.Warn("⚠️ Breaking dependency cycle %s at %s → %s",
+			cyclePath(cycle), cycle[edge].Dep.Repository, cycle[edge+1].Dep.Repository)
+		graph.RemoveEdge(cycle[edge], cycle[edge+1])
+	}
+}
+
+// declaredEdge returns the index in cycle of the first node whose edge to the
+// next one is declared, or -1.
+func declaredEdge(cycle []*domain.Node, declared []domain.PackageEdge) int {
+	for i := range len(cycle) - 1 {
+		if slices.ContainsFunc(declared, func(edge domain.PackageEdge) bool {
+			return matchesModule(cycle[i].Dep, edge.From) && matchesModule(cycle[i+1].Dep, edge.To)
+		}) {
+			return i
+		}
+	}
+	return -1
+}
+
+// cycleError describes a cycle with the boss.json files declaring its edges.
+func cycleError(cycle []*domain.Node) error {
+	var description strings.Builder
+	fmt.Fprintf(&description, "dependency cycle %s:", cyclePath(cycle))
+	for i := range len(cycle) - 1 {
+		file := filepath.Join(env.GetModulesDir(), cycle[i].Dep.Name(), consts.FilePackage)
+		if relative, err := filepath.Rel(env.GetCurrentDir(), file); err == nil {
+			file = relative
+		}
+		fmt.Fprintf(&description, "\n  %s requires %s", file, cycle[i+1].Dep.Repository)
+	}
+	description.WriteString("\nadd one of these edges to cycles.break in boss.json to build it anyway")
+	return errors.New(description.String())
+}
+
+// cyclePath returns the repositories of a cycle: A → B → A.
+func cyclePath(cycle []*domain.Node) string {
+	repositories := make([]string, 0, len(cycle))
+	for _, node := range cycle {
+		repositories = append(repositories, node.Dep.Repository)
 	}
+	return strings.Join(repositories, " → ")
 }
@@ -0,0 +1,156 @@
+//nolint:testpackage // Testing internal functions
+package compiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+)
+
+// writeModulePackage writes the boss.json of an installed module.
+func writeModulePackage(t *testing.T, module, content string) {
+	t.Helper()
+	dir := filepath.Join(modulesDir(), module)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, consts.FilePackage), []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func TestLoadDependencyGraph_Cycle(t *testing.T) {
+	setupModules(t)
+	writeModulePackage(t, "github_com_test_a", `{"name": "a", "dependencies": {"github.com/test/b": "^1.0.0"}}`)
+	writeModulePackage(t, "github_com_test_b", `{"name": "b", "dependencies": {"github.com/test/c": "^1.0.0"}}`)
+	writeModulePackage(t, "github_com_test_c", `{"name": "c", "dependencies": {"github.com/test/a": "^1.0.0"}}`)
+	pkg := &domain.Package{Dependencies: map[string]string{"github.com/test/a": "^1.0.0"}}
+
+	_, err := loadDependencyGraph(pkg)
+	if err == nil {
+		t.Fatal("loadDependencyGraph() error = nil, want a cycle")
+	}
+	for _, want := range []string{
+		"github.com/test/a → github.com/test/b → github.com/test/c → github.com/test/a",
+		filepath.Join(consts.FolderDependencies, "github_com_test_c", consts.FilePackage) + " requires github.com/test/a",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("loadDependencyGraph() error = %q, want it to contain %q", err, want)
+		}
+	}
+
+	pkg.Cycles = &domain.PackageCycles{Break: []domain.PackageEdge{{From: "test/c", To: "github.com/test/a"}}}
+	graph, err := loadDependencyGraph(pkg)
+	if err != nil {
+		t.Fatalf("loadDependencyGraph() with a declared edge error = %v", err)
+	}
+	var order []string
+	for _, node := range drainQueue(graph.Queue(pkg, true)) {
+		order = append(order, node.Dep.Repository)
+	}
+	want := []string{"github.com/test/c", "github.com/test/b", "github.com/test/a"}
+	if !slices.Equal(order, want) {
+		t.Errorf("build order = %v, want %v", order, want)
+	}
+}
+
+func TestSearchPaths_Cycle(t *testing.T) {
+	setupModules(t)
+	writeModulePackage(t, "github_com_test_a",
+		`{"name": "a", "mainsrc": "src", "dependencies": {"github.com/test/b": "^1.0.0"}}`)
+	writeModulePackage(t, "github_com_test_b",
+		`{"name": "b", "mainsrc": "src", "dependencies": {"github.com/test/a": "^1.0.0"}}`)
+
+	done := make(chan []string, 1)
+	go func() { done <- searchPaths(&domain.Dependency{Repository: "github.com/test/a"}) }()
+	var paths []string
+	select {
+	case paths = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("searchPaths() did not return on a dependency cycle")
+	}
+
+	want := []string{
+		filepath.Join(modulesDir(), "github_com_test_a"),
+		filepath.Join(modulesDir(), "github_com_test_a", "src"),
+		filepath.Join(modulesDir(), "github_com_test_b"),
+		filepath.Join(modulesDir(), "github_com_test_b", "src"),
+	}
+	if !slices.Equal(paths, want) {
+		t.Errorf("searchPaths() = %v, want %v", paths, want)
+	}
+}
+
+// syntheticPackages returns the boss.json of size modules, keyed by path, in
+// layers of width where each module depends on every fourth module of the
+// layer below.
+func syntheticPackages(size, width int) (*domain.Package, map[string]*domain.Package) {
+	repository := func(i int) string { return fmt.Sprintf("github.com/bench/pkg%05d", i) }
+	root := &domain.Package{Dependencies: map[string]string{}}
+	packages := make(map[string]*domain.Package, size)
+	for i := range size {
+		pkg := &domain.Package{Dependencies: map[string]string{}}
+		if lower := i/width*width + width; lower+width <= size {
+			for j := i % 4; j < width; j += 4 {
+				pkg.Dependencies[repository(lower+j)] = "^1.0.0"
+			}
+		}
+		dep := domain.Dependency{Repository: repository(i)}
+		packages[filepath.Join(env.GetModulesDir(), dep.Name(), consts.FilePackage)] = pkg
+		if i < width {
+			root.Dependencies[repository(i)] = "^1.0.0"
+		}
+	}
+	return root, packages
+}
+
+func loadFrom(packages map[string]*domain.Package, reads map[string]int) func(string) (*domain.Package, error) {
+	return func(path string) (*domain.Package, error) {
+		reads[path]++
+		if pkg, ok := packages[path]; ok {
+			return pkg, nil
+		}
+		return nil, os.ErrNotExist
+	}
+}
+
+func TestGraphLoader_ReadsOnce(t *testing.T) {
+	root, packages := syntheticPackages(200, 10)
+	reads := map[string]int{}
+	loader := newGraphLoader(loadFrom(packages, reads))
+	loader.add(nil, root.GetParsedDependencies())
+
+	if got := drainQueue(loader.graph.Queue(root, true)); len(got) != 200 {
+		t.Errorf("Queue() = %d nodes, want 200", len(got))
+	}
+	for path, count := range reads {
+		if count != 1 {
+			t.Errorf("%s read %d times, want once", path, count)
+		}
+	}
+}
+
+func BenchmarkLoadGraph(b *testing.B) {
+	for _, size := range []int{1000, 5000} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			root, packages := syntheticPackages(size, 50)
+			deps := root.GetParsedDependencies()
+			for b.Loop() {
+				loader := newGraphLoader(loadFrom(packages, map[string]int{}))
+				loader.add(nil, deps)
+				if err := breakCycles(loader.graph, nil); err != nil {
+					b.Fatal(err)
+				}
+				loader.graph.Queue(root, true)
+			}
+		})
+	}
+}
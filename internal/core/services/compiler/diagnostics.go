@@ -0,0 +1,107 @@
+package compiler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/diagnostics"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+)
+
+// summaryErrors is the number of errors printed after a build.
+const summaryErrors = 10
+
+// recordDiagnostics parses the build log of a project into the report of the
+// build.
+func recordDiagnostics(ctx *buildContext, dep *domain.Dependency, projectPath, buildLog string) {
+	module := ""
+	if dep != nil {
+		module = dep.Name()
+	}
+	ctx.diagnostics().Add(module, filepath.Base(projectPath), diagnostics.ParseFile(buildLog))
+}
+
+// firstError describes the first error of a module in a few words, for the
+// build tracker; it falls back to message when the log had none.
+func firstError(report *diagnostics.Report, module, message string) string {
+	var own []diagnostics.Diagnostic
+	for _, diagnostic := range report.Diagnostics() {
+		if diagnostic.Module == module {
+			own = append(own, diagnostic)
+		}
+	}
+	errors := diagnostics.Errors(own, 1)
+	if len(errors) == 0 {
+		return message
+	}
+
+	text := errors[0].Message
+	if errors[0].Unit != "" {
+		text = fmt.Sprintf("%s(%d): %s", errors[0].Unit, errors[0].Line, text)
+	}
+	const maxLength = 100
+	if len(text) > maxLength {
+		text = text[:maxLength-3] + "..."
+	}
+	return text
+}
+
+// reportDiagnostics prints the counts and first errors of the build, and
+// writes the report to the files options name.
+func reportDiagnostics(report *diagnostics.Report, options BuildOptions) {
+	all := report.Diagnostics()
+	errorCount := report.Count(diagnostics.SeverityError)
+	if len(all) > 0 {
+		msg.Info("🩺 %d error(s), %d warning(s), %d hint(s)", errorCount,
+			report.Count(diagnostics.SeverityWarning), report.Count(diagnostics.SeverityHint))
+	}
+	for _, diagnostic := range diagnostics.Errors(all, summaryErrors) {
+		msg.Err("  %s", describe(diagnostic))
+	}
+	if errorCount > summaryErrors {
+		msg.Err("  ... and %d more", errorCount-summaryErrors)
+	}
+
+	writeReport(options.DiagnosticsJSON, func(file *os.File) error {
+		return diagnostics.WriteJSON(file, report)
+	})
+	writeReport(options.DiagnosticsSARIF, func(file *os.File) error {
+		return diagnostics.WriteSARIF(file, report, env.GetCurrentDir())
+	})
+}
+
+// describe formats a diagnostic for the terminal, with the file relative to
+// the project and the module or project it comes from.
+func describe(diagnostic diagnostics.Diagnostic) string {
+	if relative, err := filepath.Rel(env.GetCurrentDir(), diagnostic.File); err == nil &&
+		!strings.HasPrefix(relative, "..") {
+		diagnostic.File = relative
+	}
+	origin := diagnostic.Project
+	if diagnostic.Module != "" {
+		origin = diagnostic.Module + "/" + origin
+	}
+	return "[" + origin + "] " + diagnostic.String()
+}
+
+func writeReport(path string, write func(*os.File) error) {
+	if path == "" {
+		return
+	}
+	file, err := os.Create(path) // #nosec G304 -- Writing the report path chosen by the user
+	if err == nil {
+		err = write(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		msg.Warn("⚠️ Failed to write %s: %v", path, err)
+		return
+	}
+	msg.Info("📄 Diagnostics written to %s", path)
+}
@@ -1,49 +1,55 @@
 package compiler
 
 import (
-	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/hashload/boss/internal/core/domain"
-	"github.com/hashload/boss/internal/core/services/compilerselector"
+	"github.com/hashload/boss/internal/core/services/timing"
 	"github.com/hashload/boss/pkg/consts"
 	"github.com/hashload/boss/pkg/env"
 	"github.com/hashload/boss/pkg/msg"
 	"github.com/hashload/boss/pkg/pkgmanager"
-	"github.com/hashload/boss/utils/dcp"
 )
 
-func getCompilerParameters(rootPath string, dep *domain.Dependency, platform string) string {
-	var moduleName = ""
-
-	if dep != nil {
-		moduleName = dep.Name()
+// outputDir returns the folder of rootPath a project writes its artifacts to.
+// A dependency writes to its own folders, moved to the shared ones of the
+// configuration afterwards; a root project writes to the shared ones.
+func outputDir(rootPath string, dep *domain.Dependency, folder string, config buildConfig) string {
+	if folder == consts.BinFolder && env.GetGlobal() {
+		return env.GetGlobalBinPath()
 	}
-
-	binPath := env.GetGlobalBinPath()
-
-	if !env.GetGlobal() {
-		binPath = filepath.Join(rootPath, moduleName, consts.BinFolder)
+	if dep != nil {
+		return filepath.Join(rootPath, dep.Name(), folder)
 	}
+	return domain.PlatformArtifactDir(rootPath, folder, config.Platform, config.Profile)
+}
 
-	return " /p:DCC_BplOutput=\"" + filepath.Join(rootPath, moduleName, consts.BplFolder) + "\" " +
-		"/p:DCC_DcpOutput=\"" + filepath.Join(rootPath, moduleName, consts.DcpFolder) + "\" " +
-		"/p:DCC_DcuOutput=\"" + filepath.Join(rootPath, moduleName, consts.DcuFolder) + "\" " +
-		"/p:DCC_ExeOutput=\"" + binPath + "\" " +
+func getCompilerParameters(rootPath string, dep *domain.Dependency, platform string, config buildConfig) string {
+	return " /p:DCC_BplOutput=\"" + outputDir(rootPath, dep, consts.BplFolder, config) + "\" " +
+		"/p:DCC_DcpOutput=\"" + outputDir(rootPath, dep, consts.DcpFolder, config) + "\" " +
+		"/p:DCC_DcuOutput=\"" + outputDir(rootPath, dep, consts.DcuFolder, config) + "\" " +
+		"/p:DCC_ExeOutput=\"" + outputDir(rootPath, dep, consts.BinFolder, config) + "\" " +
 		"/target:Build " +
-		"/p:config=Debug " +
+		"/p:config=" + config.Name + " " +
 		"/p:DCC_UseMSBuildExternally=true " +
 		"/P:platform=" + platform + " "
 }
 
 func buildSearchPath(dep *domain.Dependency) string {
+	return searchPathOf(dep, map[string]bool{})
+}
+
+// searchPathOf adds the folders of dep and of what it depends on; visited
+// holds the modules already added, so that a dependency cycle ends there.
+func searchPathOf(dep *domain.Dependency, visited map[string]bool) string {
 	var searchPath strings.Builder
 
-	if dep != nil {
+	if dep != nil && !visited[dep.Name()] {
+		visited[dep.Name()] = true
 		searchPath.WriteString(filepath.Join(env.GetModulesDir(), dep.Name()))
 
 		packageData, err := pkgmanager.LoadPackageOther(filepath.Join(env.GetModulesDir(), dep.Name(), consts.FilePackage))
@@ -52,191 +58,93 @@ func buildSearchPath(dep *domain.Dependency) string {
 			searchPath.WriteString(filepath.Join(env.GetModulesDir(), dep.Name(), packageData.MainSrc))
 			for _, lib := range packageData.GetParsedDependencies() {
 				searchPath.WriteString(";")
-				searchPath.WriteString(buildSearchPath(&lib))
+				searchPath.WriteString(searchPathOf(&lib, visited))
 			}
 		}
 	}
 	return searchPath.String()
 }
 
-func compileLazarus(lazarusPath string, tracker *BuildTracker) bool {
-	if tracker == nil || !tracker.IsEnabled() {
-		msg.Info("  🔨 Building Lazarus project/package: " + filepath.Base(lazarusPath))
-	}
-
-	_, err := exec.LookPath("lazbuild")
-	if err != nil {
-		if tracker == nil || !tracker.IsEnabled() {
-			msg.Err("    ❌ 'lazbuild' compiler not found on PATH. Please install Lazarus/lazbuild to compile.")
+// searchPaths returns the folders of buildSearchPath, without empty ones.
+func searchPaths(dep *domain.Dependency) []string {
+	var paths []string
+	for p := range strings.SplitSeq(buildSearchPath(dep), ";") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
 		}
-		return false
 	}
+	return paths
+}
 
-	absPath, _ := filepath.Abs(lazarusPath)
-	absDir := filepath.Dir(absPath)
-
-	// #nosec G204 -- Controlled lazbuild command
-	cmd := exec.CommandContext(context.Background(), "lazbuild", "--build-mode=Debug", absPath)
-	cmd.Dir = absDir
-
-	baseName := strings.TrimSuffix(filepath.Base(lazarusPath), filepath.Ext(lazarusPath))
-	buildLog := filepath.Join(absDir, "build_boss_"+baseName+".log")
-	// #nosec G304 -- Controlled build log path
-	logFile, err := os.OpenFile(buildLog, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+// compile builds a project with the backend of the build that supports it,
+// recording its log and diagnostics. A nil ctx builds with the default
+// backends and compiler.
+func compile(
+	projectPath string,
+	dep *domain.Dependency,
+	rootLock domain.PackageLock,
+	tracker *BuildTracker,
+	ctx *buildContext,
+	config buildConfig,
+) bool {
+	defer ctx.timeStep(timing.StepCompile, logModule(dep), filepath.Base(projectPath))()
+
+	verbose := tracker == nil || !tracker.IsEnabled()
+	project, _ := filepath.Abs(projectPath)
+	buildLog, keepLog := ctx.logPath(dep, projectPath)
+	job := compileJob{
+		project:  project,
+		dep:      dep,
+		lock:     rootLock,
+		compiler: ctx.selectedCompiler(),
+		platform: ctx.buildPlatform(),
+		config:   config,
+		log:      buildLog,
+		verbose:  verbose,
+	}
+	selected := selectBackend(ctx.compilerBackends(), project)
+	if _, custom := selected.(commandBackend); config.Native && !custom {
+		selected = fpcBackend{}
+	}
+	if verbose {
+		msg.Info("  🔨 Building " + filepath.Base(projectPath))
+	}
+
+	started := time.Now()
+	err := selected.compile(job)
 	if err != nil {
-		if tracker == nil || !tracker.IsEnabled() {
-			msg.Warn("  ⚠️ Error creating build log file: %v", err)
+		if _, statErr := os.Stat(buildLog); statErr != nil {
+			// The backend failed before running the compiler; the log says why.
+			_ = os.WriteFile(buildLog, []byte(err.Error()+"\n"), 0600)
 		}
-		return false
 	}
-	defer func() { _ = logFile.Close() }()
-
-	cmd.Stdout = logFile
-	cmd.Stderr = logFile
-
-	if err := cmd.Run(); err != nil {
-		if tracker == nil || !tracker.IsEnabled() {
+	recordBuildLog(ctx, dep, projectPath, buildLog, selected.name(job), job.platform, config, started, err)
+	recordDiagnostics(ctx, dep, projectPath, buildLog)
+	if err != nil {
+		if verbose {
 			msg.Err("  ❌ Failed to compile, see "+buildLog+" for more information: %v", err)
 		}
 		return false
 	}
-
-	if tracker == nil || !tracker.IsEnabled() {
+	if verbose {
 		msg.Info("  ✅️ Success!")
 	}
 
-	_ = os.Remove(buildLog)
-	return true
-}
-
-//nolint:funlen,gocognit,gocyclo,cyclop,lll // Complex compilation orchestration
-func compile(dprojPath string, dep *domain.Dependency, rootLock domain.PackageLock, tracker *BuildTracker, selectedCompiler *compilerselector.SelectedCompiler) bool {
-	ext := strings.ToLower(filepath.Ext(dprojPath))
-	if ext == ".lpi" || ext == ".lpk" {
-		return compileLazarus(dprojPath, tracker)
-	}
-
-	if tracker == nil || !tracker.IsEnabled() {
-		msg.Info("  🔨 Building " + filepath.Base(dprojPath))
-	}
-
-	bossPackagePath := filepath.Join(env.GetModulesDir(), dep.Name(), consts.FilePackage)
-
-	if dependencyPackage, err := pkgmanager.LoadPackageOther(bossPackagePath); err == nil {
-		dcp.InjectDpcsFile(dprojPath, dependencyPackage, rootLock)
-	}
-
-	dccDir := env.GetDcc32Dir()
-	platform := consts.PlatformWin32.String()
-	compilerBinary := "dcc32.exe"
-
-	if selectedCompiler != nil {
-		dccDir = selectedCompiler.BinDir
-		if selectedCompiler.Arch != "" {
-			platform = selectedCompiler.Arch
-		}
-		switch selectedCompiler.Arch {
-		case consts.PlatformWin64.String():
-			compilerBinary = "dcc64.exe"
-		case consts.PlatformOSX64.String():
-			compilerBinary = "dccosx.exe"
-		case consts.PlatformLinux64.String():
-			compilerBinary = "dcclinux64.exe"
+	if !keepLog {
+		if err := os.Remove(buildLog); err != nil {
+			msg.Debug("Could not remove build log %s: %v", buildLog, err)
 		}
 	}
+	return true
+}
 
-	if tracker == nil || !tracker.IsEnabled() {
-		msg.Debug("  🛠️ Using: %s (Platform: %s)", filepath.Join(dccDir, compilerBinary), platform)
-	}
-
-	rsvars := filepath.Join(dccDir, "rsvars.bat")
-	fileRes := "build_boss_" + strings.TrimSuffix(filepath.Base(dprojPath), filepath.Ext(dprojPath))
-	abs, _ := filepath.Abs(filepath.Dir(dprojPath))
-	buildLog := filepath.Join(abs, fileRes+".log")
-	buildBat := filepath.Join(abs, fileRes+".bat")
-	cfgPath := filepath.Join(abs, "boss.cfg")
-
-	// Create boss.cfg to hold search paths and avoid command-line too long errors (Issue #205)
-	var cfgContent strings.Builder
-	dcuPath := filepath.Join(env.GetModulesDir(), consts.DcuFolder)
-	dcpPath := filepath.Join(env.GetModulesDir(), consts.DcpFolder)
-	fmt.Fprintf(&cfgContent, "-I\"%s\"\n-U\"%s\"\n", dcuPath, dcuPath)
-	fmt.Fprintf(&cfgContent, "-I\"%s\"\n-U\"%s\"\n", dcpPath, dcpPath)
-
-	if searchPathsStr := buildSearchPath(dep); searchPathsStr != "" {
-		paths := strings.Split(searchPathsStr, ";")
-		for _, p := range paths {
-			p = strings.TrimSpace(p)
-			if p != "" {
-				fmt.Fprintf(&cfgContent, "-I\"%s\"\n-U\"%s\"\n", p, p)
-			}
-		}
-	}
-
-	err := os.WriteFile(cfgPath, []byte(cfgContent.String()), 0600)
-	if err != nil {
-		if tracker == nil || !tracker.IsEnabled() {
-			msg.Warn("  ⚠️ Error on create compiler configuration file")
-		}
-		return false
-	}
-
-	readFile, err := os.ReadFile(rsvars) // #nosec G304 -- Reading Delphi environment variables file from known location
-	if err != nil {
-		msg.Err("    ❌ Error on read rsvars.bat")
-	}
-	readFileStr := string(readFile)
-	project, _ := filepath.Abs(dprojPath)
-
-	var scriptBuilder strings.Builder
-	scriptBuilder.WriteString(readFileStr)
-	scriptBuilder.WriteString("\n@SET PATH=%PATH%;")
-	scriptBuilder.WriteString(filepath.Join(env.GetModulesDir(), consts.BplFolder))
-	scriptBuilder.WriteString(";")
-	for _, value := range []string{platform} {
-		scriptBuilder.WriteString(" \n msbuild \"")
-		scriptBuilder.WriteString(project)
-		scriptBuilder.WriteString("\" /p:Configuration=Debug ")
-		scriptBuilder.WriteString(getCompilerParameters(env.GetModulesDir(), dep, value))
-		scriptBuilder.WriteString(" /p:DCC_AdditionalParameters=\"@")
-		scriptBuilder.WriteString(cfgPath)
-		scriptBuilder.WriteString("\"")
-	}
-	scriptBuilder.WriteString(" > \"")
-	scriptBuilder.WriteString(buildLog)
-	scriptBuilder.WriteString("\"")
-
-	err = os.WriteFile(buildBat, []byte(scriptBuilder.String()), 0600)
+// openLog creates the log file of job for a backend that runs its compiler
+// itself.
+func openLog(job compileJob) (*os.File, error) {
+	// #nosec G304 -- Controlled build log path
+	file, err := os.OpenFile(job.log, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
 	if err != nil {
-		if tracker == nil || !tracker.IsEnabled() {
-			msg.Warn("  ⚠️ Error on create build file")
-		}
-		_ = os.Remove(cfgPath)
-		return false
-	}
-
-	command := exec.CommandContext(context.Background(), buildBat) // #nosec G204 -- Executing controlled build script generated by Boss
-	command.Dir = abs
-	if _, err = command.Output(); err != nil {
-		if tracker == nil || !tracker.IsEnabled() {
-			msg.Err("  ❌ Failed to compile, see " + buildLog + " for more information")
-		}
-		_ = os.Remove(cfgPath)
-		return false
+		return nil, fmt.Errorf("creating build log: %w", err)
 	}
-	if tracker == nil || !tracker.IsEnabled() {
-		msg.Info("  ✅️ Success!")
-	}
-
-	if err := os.Remove(buildLog); err != nil {
-		msg.Debug("Could not remove build log %s: %v", buildLog, err)
-	}
-	if err := os.Remove(buildBat); err != nil {
-		msg.Debug("Could not remove build script %s: %v", buildBat, err)
-	}
-	if err := os.Remove(cfgPath); err != nil {
-		msg.Debug("Could not remove boss.cfg %s: %v", cfgPath, err)
-	}
-
-	return true
+	return file, nil
 }
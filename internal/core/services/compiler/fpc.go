@@ -0,0 +1,103 @@
+package compiler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/pkgmanager"
+)
+
+// fpcBackend builds Free Pascal programs (.lpr) and units (.pas, .pp) with
+// fpc, without Lazarus.
+type fpcBackend struct{}
+
+func (fpcBackend) name(compileJob) string {
+	return "fpc"
+}
+
+func (fpcBackend) supports(projectPath string) bool {
+	return hasExtension(projectPath, ".lpr", ".pas", ".pp")
+}
+
+func (fpcBackend) compile(job compileJob) error {
+	if !onPath("fpc") {
+		return errors.New("'fpc' compiler not found on PATH. Please install Free Pascal to compile")
+	}
+	for _, folder := range []string{consts.DcuFolder, consts.BinFolder} {
+		if err := os.MkdirAll(job.outputDir(folder), 0755); err != nil {
+			return fmt.Errorf("creating %s folder: %w", folder, err)
+		}
+	}
+
+	// #nosec G204 -- Controlled fpc command
+	cmd := exec.CommandContext(context.Background(), "fpc", fpcArguments(job)...)
+	cmd.Dir = filepath.Dir(job.project)
+	return runLogged(cmd, job)
+}
+
+// fpcArguments returns the fpc command line of job: the options of its build
+// section, the output folders, the search paths of fpcSearchPaths, then the
+// project. Units go to the dcu folder as .ppu and .o files, programs to the
+// bin folder.
+func fpcArguments(job compileJob) []string {
+	args := fpcOptions(job.config.Build)
+	args = append(args, "-FU"+job.outputDir(consts.DcuFolder), "-FE"+job.outputDir(consts.BinFolder))
+	for _, path := range fpcSearchPaths(job) {
+		args = append(args, "-Fu"+path, "-Fi"+path)
+	}
+	return append(args, job.project)
+}
+
+// fpcSearchPaths returns the unit and include folders of job: those of
+// buildSearchPath for a dependency, the main sources of the root package for
+// its projects, then the units already compiled for the module and for the
+// dependencies.
+func fpcSearchPaths(job compileJob) []string {
+	paths := searchPaths(job.dep)
+	if job.dep == nil {
+		root := env.GetCurrentDir()
+		if pkg, err := pkgmanager.LoadPackageOther(filepath.Join(root, consts.FilePackage)); err == nil {
+			for mainSrc := range strings.SplitSeq(pkg.MainSrc, ";") {
+				if mainSrc = strings.TrimSpace(mainSrc); mainSrc != "" {
+					paths = append(paths, filepath.Join(root, mainSrc))
+				}
+			}
+		}
+	}
+	paths = append(paths, job.outputDir(consts.DcuFolder))
+	shared := job.config.artifactDir(consts.DcuFolder)
+	if !slices.Contains(paths, shared) {
+		paths = append(paths, shared)
+	}
+	return paths
+}
+
+// packageProjects returns the projects of a package to compile, and whether
+// they are compiled natively with fpc: the targets of its fpc section when it
+// declares them and either lists no projects or 'lazbuild' is not on the PATH
+// but 'fpc' is, otherwise its projects.
+func packageProjects(pkg *domain.Package) ([]string, bool) {
+	targets := pkg.Fpc.Targets()
+	if len(targets) == 0 {
+		return pkg.Projects, false
+	}
+	if len(pkg.Projects) == 0 || (!onPath("lazbuild") && onPath("fpc")) {
+		return targets, true
+	}
+	return pkg.Projects, false
+}
+
+// onPath reports whether the command is found on the PATH.
+func onPath(command string) bool {
+	_, err := exec.LookPath(command)
+	return err == nil
+}
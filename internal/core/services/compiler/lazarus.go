@@ -0,0 +1,50 @@
+package compiler
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// lazarusBackend builds Lazarus projects (.lpi) and packages (.lpk) with
+// lazbuild.
+type lazarusBackend struct{}
+
+func (lazarusBackend) name(compileJob) string {
+	return "lazbuild"
+}
+
+func (lazarusBackend) supports(projectPath string) bool {
+	return hasExtension(projectPath, ".lpi", ".lpk")
+}
+
+func (lazarusBackend) compile(job compileJob) error {
+	if !onPath("lazbuild") {
+		return errors.New("'lazbuild' compiler not found on PATH. Please install Lazarus/lazbuild, " +
+			"or declare the fpc section of boss.json to compile with fpc")
+	}
+
+	args := []string{"--build-mode=" + job.config.Name}
+	if options := fpcOptions(job.config.Build); len(options) > 0 {
+		args = append(args, "--opt="+strings.Join(options, " "))
+	}
+	// #nosec G204 -- Controlled lazbuild command
+	cmd := exec.CommandContext(context.Background(), "lazbuild", append(args, job.project)...)
+	cmd.Dir = filepath.Dir(job.project)
+	return runLogged(cmd, job)
+}
+
+// runLogged runs cmd with its output written to the log of job.
+func runLogged(cmd *exec.Cmd, job compileJob) error {
+	logFile, err := openLog(job)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logFile.Close() }()
+
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	return cmd.Run()
+}
@@ -0,0 +1,138 @@
+package compiler
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/buildlog"
+	"github.com/hashload/boss/internal/core/services/compilerselector"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+)
+
+// startBuildLog starts keeping the compiler output of a build under
+// modules/.logs. It returns nil when the folder cannot be written; the logs
+// are then left next to the projects, and only when they fail.
+func startBuildLog(selected *compilerselector.SelectedCompiler, profile string) *buildlog.Run {
+	info := buildlog.RunInfo{Config: profile, Platform: consts.PlatformWin32.String()}
+	if selected != nil {
+		info.Compiler = selected.Version
+		if selected.Arch != "" {
+			info.Platform = selected.Arch
+		}
+	}
+	run, err := buildlog.Start(env.GetBuildLogsDir(), info)
+	if err != nil {
+		msg.Debug("Could not keep build logs: %v", err)
+		return nil
+	}
+	return run
+}
+
+// finishBuildLog records the outcome of the build and removes the logs the
+// retention settings no longer keep.
+func finishBuildLog(run *buildlog.Run) {
+	if run == nil {
+		return
+	}
+	if err := run.Finish(); err != nil {
+		msg.Debug("Could not save build log: %v", err)
+	}
+	retention := env.GlobalConfiguration().GetBuildLogs()
+	if _, err := buildlog.Prune(env.GetBuildLogsDir(), retention.Keep(), retention.MaxAge()); err != nil {
+		msg.Debug("Could not remove old build logs: %v", err)
+	}
+}
+
+// logPath returns the log file of a project, and whether it is kept after a
+// successful build.
+func (c *buildContext) logPath(dep *domain.Dependency, projectPath string) (string, bool) {
+	if c != nil && c.logs != nil {
+		path, err := c.logs.LogPath(logModule(dep), logProject(dep, projectPath))
+		if err == nil {
+			return path, true
+		}
+		msg.Debug("Could not keep build log of %s: %v", projectPath, err)
+	}
+	base := strings.TrimSuffix(filepath.Base(projectPath), filepath.Ext(projectPath))
+	dir, _ := filepath.Abs(filepath.Dir(projectPath))
+	return filepath.Join(dir, "build_boss_"+base+".log"), false
+}
+
+// recordBuildLog writes the metadata of a project build next to its log.
+// err is the error of the compiler command.
+func recordBuildLog(
+	ctx *buildContext,
+	dep *domain.Dependency,
+	projectPath, logPath, compiler, platform string,
+	config buildConfig,
+	started time.Time,
+	err error,
+) {
+	if ctx == nil || ctx.logs == nil {
+		return
+	}
+	entry := buildlog.Entry{
+		Module:   logModule(dep),
+		Project:  logProject(dep, projectPath),
+		Compiler: compiler,
+		Platform: platform,
+		Config:   config.Name,
+		Started:  started,
+		Duration: time.Since(started),
+		Status:   buildlog.StatusSuccess,
+		Log:      logPath,
+	}
+	if dep != nil {
+		entry.Repository = dep.GetKey()
+	}
+	if err != nil {
+		entry.Status = buildlog.StatusFailed
+		entry.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			entry.ExitCode = exitErr.ExitCode()
+		}
+	}
+	if err = ctx.logs.Record(entry); err != nil {
+		msg.Debug("Could not save build log of %s: %v", projectPath, err)
+	}
+}
+
+// logProject returns the path of a project relative to its module, the
+// current directory for the root package, with forward slashes.
+func logProject(dep *domain.Dependency, projectPath string) string {
+	moduleDir := env.GetCurrentDir()
+	if dep != nil {
+		moduleDir = filepath.Join(env.GetModulesDir(), dep.Name())
+	}
+	project, err := filepath.Abs(projectPath)
+	if err == nil {
+		project, err = filepath.Rel(moduleDir, project)
+	}
+	if err != nil || strings.HasPrefix(project, "..") {
+		return filepath.Base(projectPath)
+	}
+	return filepath.ToSlash(project)
+}
+
+func logModule(dep *domain.Dependency) string {
+	if dep == nil {
+		return ""
+	}
+	return dep.Name()
+}
+
+// compilerVersion returns the version of the compiler, empty for the default
+// one.
+func compilerVersion(selected *compilerselector.SelectedCompiler) string {
+	if selected == nil {
+		return ""
+	}
+	return selected.Version
+}
@@ -0,0 +1,97 @@
+package compiler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+// allDependencies is the key of build.dependencies that applies to every
+// dependency.
+const allDependencies = "*"
+
+// rootBuildSettings returns what the build section of the root package sets
+// for dep: the "*" entry first, then the entries naming it, in key order.
+func rootBuildSettings(pkg *domain.Package, dep domain.Dependency) *domain.PackageBuild {
+	if pkg.Build == nil {
+		return nil
+	}
+	names := make([]string, 0, len(pkg.Build.Dependencies))
+	for name := range pkg.Build.Dependencies {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if (a == allDependencies) != (b == allDependencies) {
+			if a == allDependencies {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	var settings *domain.PackageBuild
+	for _, name := range names {
+		if name == allDependencies || matchesModule(dep, name) {
+			settings = settings.Merge(pkg.Build.Dependencies[name])
+		}
+	}
+	return settings
+}
+
+// overridesDigest identifies what the root package sets for a dependency, to
+// rebuild it when that changes; "" for nothing.
+func overridesDigest(overrides *domain.PackageBuild) string {
+	if overrides == nil {
+		return ""
+	}
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// dccOptions returns the lines of boss.cfg for a build section: -D for the
+// defines, -NS for the unit scopes, -W^ to treat warnings as errors and then
+// the dcc flags.
+func dccOptions(build *domain.PackageBuild) []string {
+	if build == nil {
+		return nil
+	}
+	var options []string
+	if len(build.Defines) > 0 {
+		options = append(options, "-D"+strings.Join(build.Defines, ";"))
+	}
+	if len(build.UnitScopes) > 0 {
+		options = append(options, "-NS"+strings.Join(build.UnitScopes, ";"))
+	}
+	if build.WarningsAsErrors {
+		options = append(options, "-W^")
+	}
+	return append(options, build.DccFlags...)
+}
+
+// fpcOptions returns the FPC options for a build section: -d per define, -FN
+// per unit scope, -Sew to treat warnings as errors and then the fpc flags.
+func fpcOptions(build *domain.PackageBuild) []string {
+	if build == nil {
+		return nil
+	}
+	var options []string
+	for _, define := range build.Defines {
+		options = append(options, "-d"+define)
+	}
+	for _, scope := range build.UnitScopes {
+		options = append(options, "-FN"+scope)
+	}
+	if build.WarningsAsErrors {
+		options = append(options, "-Sew")
+	}
+	return append(options, build.FpcFlags...)
+}
@@ -0,0 +1,142 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/binarypkg"
+	"github.com/hashload/boss/internal/core/services/compilerselector"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+)
+
+// BinaryPackage is a binary package archive and its manifest.
+type BinaryPackage struct {
+	Manifest binarypkg.Manifest
+	Data     []byte
+}
+
+// packTarget is a platform a binary package is made for, with the compiler
+// selected for it. matrix is the platform of a multi-platform build, empty for
+// a single-platform build.
+type packTarget struct {
+	matrix   string
+	compiler string
+	platform string
+}
+
+// PackBinary returns binary packages of the artifacts of the root projects,
+// built with the compiler, platform and configuration options select: one
+// per platform when options selects several, as a multi-platform build does.
+// Unless skipBuild is set the root projects, and the changed dependencies,
+// are built first; a failed build is an error.
+func PackBinary(pkg *domain.Package, options BuildOptions, skipBuild bool) ([]BinaryPackage, error) {
+	platforms, err := matrixPlatforms(pkg, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(platforms) == 0 {
+		platforms = []string{""}
+	}
+
+	targets := make([]packTarget, 0, len(platforms))
+	for _, matrix := range platforms {
+		cliPlatform := options.Platform
+		if matrix != "" {
+			cliPlatform = matrix
+		}
+		selected, err := compilerselector.SelectCompiler(compilerselector.SelectionContext{
+			Package:            pkg,
+			CliCompilerVersion: options.Compiler,
+			CliPlatform:        cliPlatform,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("a binary package needs a known compiler: %w", err)
+		}
+		platform := selected.Arch
+		if platform == "" {
+			platform = consts.PlatformWin32.String()
+		}
+		targets = append(targets, packTarget{matrix: matrix, compiler: selected.Version, platform: platform})
+	}
+
+	if !skipBuild {
+		options.Root = true
+		failed, err := Run(pkg, options)
+		if err != nil {
+			return nil, err
+		}
+		if len(failed) > 0 {
+			return nil, fmt.Errorf("failed to build %v", failed)
+		}
+	}
+
+	profile := buildProfile(pkg, options)
+	packages := make([]BinaryPackage, 0, len(targets))
+	for _, target := range targets {
+		artifacts, err := rootArtifacts(pkg.Lock, profile, target.matrix)
+		if err != nil {
+			return nil, err
+		}
+		if len(artifacts) == 0 {
+			return nil, fmt.Errorf("no %s artifacts of the root projects were found, build them first", target.platform)
+		}
+
+		manifest := binarypkg.Manifest{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			Engines: domain.PackageEngines{Compiler: target.compiler, Platforms: []string{target.platform}},
+			Config:  profile,
+		}
+		data, err := binarypkg.Pack(manifest, artifacts)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, BinaryPackage{Manifest: manifest, Data: data})
+	}
+	return packages, nil
+}
+
+// rootArtifacts returns the files of the shared artifact folders of profile
+// that no dependency of lock claims: those of the root projects. matrix is the
+// platform of a multi-platform build, whose artifacts have folders of their
+// own, empty for a single-platform build.
+func rootArtifacts(lock domain.PackageLock, profile, matrix string) (map[string]string, error) {
+	claimed := map[string]bool{}
+	for _, locked := range lock.Installed {
+		built := locked.ArtifactsFor(matrix).Artifacts
+		for folder, names := range map[string][]string{
+			consts.BplFolder: built.Bpl,
+			consts.DcpFolder: built.Dcp,
+			consts.DcuFolder: built.Dcu,
+			consts.BinFolder: built.Bin,
+		} {
+			for _, name := range names {
+				claimed[folder+"/"+name] = true
+			}
+		}
+	}
+
+	artifacts := map[string]string{}
+	for _, folder := range binarypkg.Folders {
+		dir := domain.PlatformArtifactDir(env.GetModulesDir(), folder, matrix, profile)
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			name := folder + "/" + entry.Name()
+			if entry.IsDir() || claimed[name] || entry.Name() == consts.FileBplOrder {
+				continue
+			}
+			artifacts[name] = filepath.Join(dir, entry.Name())
+		}
+	}
+	return artifacts, nil
+}
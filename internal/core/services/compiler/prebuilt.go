@@ -0,0 +1,67 @@
+package compiler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/binarypkg"
+	"github.com/hashload/boss/internal/core/services/timing"
+	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
+)
+
+// installPrebuilt extracts into the module folder of dep the binary package
+// its boss.json lists for the selected compiler, platform and configuration,
+// and returns the digest of the archive. Without a matching binary, or when
+// it cannot be used, it returns false and the dependency is compiled.
+func installPrebuilt(
+	dep domain.Dependency,
+	dependencyPackage *domain.Package,
+	ctx *buildContext,
+	config buildConfig,
+) (string, bool) {
+	selectedCompiler := ctx.selectedCompiler()
+	if len(dependencyPackage.Binaries) == 0 || selectedCompiler == nil {
+		return "", false
+	}
+	if config.Overrides != nil {
+		msg.Debug("Compiling %s from source: boss.json sets build options for it", dep.Name())
+		return "", false
+	}
+	platform := selectedCompiler.Arch
+	if platform == "" {
+		platform = consts.PlatformWin32.String()
+	}
+
+	binary, err := binarypkg.Select(dependencyPackage.Binaries, selectedCompiler.Version, platform, config.Name)
+	if errors.Is(err, binarypkg.ErrNoMatch) {
+		msg.Debug("No binary package of %s for %s %s %s", dep.Name(), selectedCompiler.Version, platform, config.Name)
+		return "", false
+	}
+	defer ctx.timeStep(timing.StepPrebuilt, dep.Name(), "")()
+
+	moduleDir := filepath.Join(env.GetModulesDir(), dep.Name())
+	data, err := binarypkg.Fetch(binary, moduleDir)
+	if err != nil {
+		msg.Warn("⚠️ Compiling %s from source: %v", dep.Name(), err)
+		return "", false
+	}
+	manifest, err := binarypkg.ReadManifest(data)
+	if err == nil && !manifest.Matches(selectedCompiler.Version, platform, config.Name) {
+		err = errors.New(binary.URL + " was built for another compiler, platform or configuration")
+	}
+	if err == nil {
+		_, err = binarypkg.Extract(data, moduleDir)
+	}
+	if err != nil {
+		for _, folder := range binarypkg.Folders {
+			_ = os.RemoveAll(filepath.Join(moduleDir, folder))
+		}
+		msg.Warn("⚠️ Compiling %s from source: %v", dep.Name(), err)
+		return "", false
+	}
+	return binarypkg.Digest(data), true
+}
@@ -2,6 +2,7 @@ package compiler
 
 import (
 	"github.com/hashload/boss/internal/core/services/tracker"
+	"github.com/hashload/boss/pkg/consts"
 	"github.com/pterm/pterm"
 )
 
@@ -89,6 +90,16 @@ func (bt *BuildTracker) SetSuccess(name string) {
 	bt.UpdateStatus(name, BuildStatusSuccess, "")
 }
 
+// SetRestored marks a package restored from the build cache.
+func (bt *BuildTracker) SetRestored(name string) {
+	bt.UpdateStatus(name, BuildStatusSuccess, consts.StatusMsgFromCache)
+}
+
+// SetPrebuilt marks a package installed from its binary package.
+func (bt *BuildTracker) SetPrebuilt(name string) {
+	bt.UpdateStatus(name, BuildStatusSuccess, consts.StatusMsgPrebuilt)
+}
+
 // SetFailed sets the status to failed with a message.
 func (bt *BuildTracker) SetFailed(name string, message string) {
 	bt.UpdateStatus(name, BuildStatusFailed, message)
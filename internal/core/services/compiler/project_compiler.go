@@ -9,5 +9,6 @@ type DefaultProjectCompiler struct{}
 
 // Compile compiles a dproj file.
 func (d *DefaultProjectCompiler) Compile(dprojPath string, dep *domain.Dependency, rootLock domain.PackageLock) bool {
-	return compile(dprojPath, dep, rootLock, nil, nil)
+	config := buildConfig{Name: domain.DefaultBuildConfig, Profile: domain.DefaultBuildConfig}
+	return compile(dprojPath, dep, rootLock, nil, nil, config)
 }
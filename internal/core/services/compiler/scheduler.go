@@ -0,0 +1,114 @@
+package compiler
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+// buildWorkers returns the number of dependencies compiled at the same time.
+func buildWorkers(jobs int) int {
+	if jobs > 0 {
+		return jobs
+	}
+	return runtime.NumCPU()
+}
+
+// buildDependencies maps every node to the other nodes of the build it must
+// wait for: those it depends on directly or through dependencies that are not
+// part of this build.
+func buildDependencies(graph *domain.GraphItem, nodes []domain.Node) map[string][]string {
+	selected := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		selected[node.Value] = true
+	}
+
+	dependencies := make(map[string][]string, len(nodes))
+	for i := range nodes {
+		seen := map[string]bool{nodes[i].Value: true}
+		stack := graph.DependsOn(&nodes[i])
+		for len(stack) > 0 {
+			next := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[next.Value] {
+				continue
+			}
+			seen[next.Value] = true
+			if selected[next.Value] {
+				dependencies[nodes[i].Value] = append(dependencies[nodes[i].Value], next.Value)
+			}
+			stack = append(stack, graph.DependsOn(next)...)
+		}
+	}
+	return dependencies
+}
+
+// runSchedule calls build for every node on up to workers goroutines. A node
+// starts as soon as every node it depends on is done; nodes that become ready
+// together start in their order in nodes, the topological build order. It
+// returns once every node is done, or with an error naming the nodes that
+// could never start because their dependencies form a cycle.
+func runSchedule(nodes []domain.Node, dependsOn map[string][]string, workers int, build func(domain.Node)) error {
+	index := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		index[node.Value] = i
+	}
+	pending := make([]int, len(nodes))
+	dependents := make([][]int, len(nodes))
+	for i, node := range nodes {
+		for _, dependency := range dependsOn[node.Value] {
+			if j, ok := index[dependency]; ok && j != i {
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	var ready []int
+	for i := range nodes {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	done := make(chan int)
+	running, finished := 0, 0
+	for finished < len(nodes) {
+		for running < max(workers, 1) && len(ready) > 0 {
+			next := ready[0]
+			ready = ready[1:]
+			running++
+			go func() {
+				build(nodes[next])
+				done <- next
+			}()
+		}
+		if running == 0 {
+			return unscheduledError(nodes, pending)
+		}
+
+		completed := <-done
+		running--
+		finished++
+		for _, dependent := range dependents[completed] {
+			if pending[dependent]--; pending[dependent] == 0 {
+				ready = append(ready, dependent)
+				slices.Sort(ready)
+			}
+		}
+	}
+	return nil
+}
+
+func unscheduledError(nodes []domain.Node, pending []int) error {
+	var names []string
+	for i, node := range nodes {
+		if pending[i] > 0 {
+			names = append(names, node.Dep.Name())
+		}
+	}
+	return fmt.Errorf("dependency cycle between %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,114 @@
+//nolint:testpackage // Testing internal functions
+package compiler
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+// testGraph builds a graph from "consumer -> dependency" edges.
+func testGraph(edges map[string][]string) (*domain.GraphItem, map[string]*domain.Node) {
+	graph := &domain.GraphItem{}
+	nodes := map[string]*domain.Node{}
+	node := func(name string) *domain.Node {
+		if nodes[name] == nil {
+			dep := domain.ParseDependency("github.com/test/"+name, "1.0.0")
+			nodes[name] = domain.NewNode(&dep)
+			graph.AddNode(nodes[name])
+		}
+		return nodes[name]
+	}
+	for consumer, dependencies := range edges {
+		for _, dependency := range dependencies {
+			graph.AddEdge(node(consumer), node(dependency))
+		}
+	}
+	return graph, nodes
+}
+
+func TestBuildDependencies_ThroughUnselectedNodes(t *testing.T) {
+	graph, nodes := testGraph(map[string][]string{
+		"app":  {"http"},
+		"http": {"core"},
+		"json": {"core"},
+	})
+	selected := []domain.Node{*nodes["core"], *nodes["json"], *nodes["app"]}
+
+	dependencies := buildDependencies(graph, selected)
+	if got := dependencies[nodes["app"].Value]; len(got) != 1 || got[0] != nodes["core"].Value {
+		t.Errorf("app should wait for core through http, got %v", got)
+	}
+	if got := dependencies[nodes["core"].Value]; len(got) != 0 {
+		t.Errorf("core depends on nothing, got %v", got)
+	}
+}
+
+func TestRunSchedule_RespectsDependenciesInParallel(t *testing.T) {
+	graph, nodes := testGraph(map[string][]string{
+		"app":  {"http", "json"},
+		"http": {"core"},
+		"json": {"core"},
+		"log":  {"core"},
+	})
+	order := []domain.Node{*nodes["core"], *nodes["http"], *nodes["json"], *nodes["log"], *nodes["app"]}
+	dependsOn := buildDependencies(graph, order)
+
+	var mu sync.Mutex
+	finished := map[string]bool{}
+	running, peak := 0, 0
+	err := runSchedule(order, dependsOn, 4, func(node domain.Node) {
+		mu.Lock()
+		for _, dependency := range dependsOn[node.Value] {
+			if !finished[dependency] {
+				t.Errorf("%s started before %s was built", node.Value, dependency)
+			}
+		}
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		finished[node.Value] = true
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("runSchedule() error = %v", err)
+	}
+	if len(finished) != len(order) {
+		t.Errorf("built %d of %d nodes", len(finished), len(order))
+	}
+	if peak < 2 {
+		t.Errorf("independent nodes should build in parallel, peak was %d", peak)
+	}
+}
+
+func TestRunSchedule_SingleWorkerKeepsOrder(t *testing.T) {
+	_, nodes := testGraph(map[string][]string{"b": {"a"}, "c": {"a"}})
+	order := []domain.Node{*nodes["a"], *nodes["c"], *nodes["b"]}
+
+	var built []string
+	err := runSchedule(order, map[string][]string{}, 1, func(node domain.Node) {
+		built = append(built, node.Value)
+	})
+	want := []string{order[0].Value, order[1].Value, order[2].Value}
+	if err != nil || strings.Join(built, ",") != strings.Join(want, ",") {
+		t.Errorf("runSchedule() built %v, %v; want %v", built, err, want)
+	}
+}
+
+func TestRunSchedule_Cycle(t *testing.T) {
+	graph, nodes := testGraph(map[string][]string{"a": {"b"}, "b": {"a"}})
+	order := []domain.Node{*nodes["a"], *nodes["b"]}
+
+	err := runSchedule(order, buildDependencies(graph, order), 2, func(domain.Node) {})
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("runSchedule() error = %v, want a cycle error", err)
+	}
+}
@@ -0,0 +1,87 @@
+package compiler
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashload/boss/internal/core/services/timing"
+	"github.com/hashload/boss/pkg/msg"
+)
+
+// summaryModules is the number of dependencies listed in the timing summary.
+const summaryModules = 10
+
+// timeStep starts timing a step of the build; nil contexts are not timed.
+func (c *buildContext) timeStep(step timing.Step, module, name string) func() {
+	if c == nil {
+		return func() {}
+	}
+	return c.timings.Start(step, module, name)
+}
+
+// ReportTimings prints where the time of an install or build went, slowest
+// dependencies first, and writes the Chrome trace to profile when set.
+// rootName names the projects of the root package.
+func ReportTimings(timings *timing.Recorder, profile, rootName string) {
+	modules := timings.Summary()
+	if len(modules) > 0 {
+		msg.Info("⏱️ Finished in %s (%s)", formatDuration(timings.Elapsed()), describeSteps(timings.StepTotals()))
+		width := 0
+		for _, module := range modules[:min(len(modules), summaryModules)] {
+			width = max(width, len(moduleLabel(module.Module, rootName)))
+		}
+		for _, module := range modules[:min(len(modules), summaryModules)] {
+			msg.Info("  %-*s %8s  %s", width, moduleLabel(module.Module, rootName),
+				formatDuration(module.Total), describeSteps(module.Steps))
+		}
+		if len(modules) > summaryModules {
+			msg.Info("  ... and %d more", len(modules)-summaryModules)
+		}
+	}
+
+	if profile == "" {
+		return
+	}
+	file, err := os.Create(profile) // #nosec G304 -- Writing the profile path chosen by the user
+	if err == nil {
+		err = timing.WriteTrace(file, timings, rootName)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		msg.Warn("⚠️ Failed to write %s: %v", profile, err)
+		return
+	}
+	msg.Info("📄 Profile written to %s", profile)
+}
+
+// describeSteps lists the time of each step, in the order the steps happen.
+func describeSteps(steps map[timing.Step]time.Duration) string {
+	parts := make([]string, 0, len(steps))
+	for _, step := range timing.Steps() {
+		if duration, ok := steps[step]; ok {
+			parts = append(parts, fmt.Sprintf("%s %s", step, formatDuration(duration)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func moduleLabel(module, rootName string) string {
+	if module != "" {
+		return module
+	}
+	if rootName != "" {
+		return rootName
+	}
+	return "(root)"
+}
+
+func formatDuration(duration time.Duration) string {
+	if duration < time.Second {
+		return duration.Round(time.Millisecond).String()
+	}
+	return duration.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,201 @@
+// Package depgraph describes the dependency graph of a project for export:
+// the root package and its installed dependencies with their locked versions
+// and status, and the version constraints of the edges between them. It
+// selects part of the graph and writes it as Graphviz DOT, Mermaid or JSON.
+package depgraph
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+// Status is the state of an installed dependency.
+type Status string
+
+const (
+	// StatusOK is a dependency installed and built as locked.
+	StatusOK Status = "ok"
+	// StatusChanged is a dependency the next install or build updates or
+	// compiles again.
+	StatusChanged Status = "changed"
+	// StatusFailed is a dependency whose latest build failed.
+	StatusFailed Status = "failed"
+	// StatusMissing is a dependency that is not installed.
+	StatusMissing Status = "missing"
+)
+
+// Node is the root package or a dependency.
+type Node struct {
+	// ID is the repository of a dependency, or the name of the root package.
+	ID         string `json:"id"`
+	Repository string `json:"repository,omitempty"`
+	// Version is the locked version of a dependency, or the version of the
+	// root package.
+	Version string `json:"version,omitempty"`
+	Status  Status `json:"status,omitempty"`
+	Root    bool   `json:"root,omitempty"`
+}
+
+// Edge is the dependency of From on To, both node IDs, with the version
+// constraint From declares.
+type Edge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Constraint string `json:"constraint,omitempty"`
+}
+
+// Graph is the exported dependency graph. Nodes start with the root package,
+// followed by the dependencies sorted by repository; edges are sorted too.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Options selects the part of the graph to export.
+type Options struct {
+	// Focus is a dependency, as its repository, a suffix of it or its module
+	// folder: only it and what it depends on are kept.
+	Focus string
+	// Reverse keeps the focus and what depends on it instead.
+	Reverse bool
+	// Depth limits the edges followed from the focus, or from the root
+	// package without one; 0 follows them all.
+	Depth int
+}
+
+// New returns the graph of pkg and of the dependencies in graph, as loaded by
+// compiler.LoadGraph, with the status of each dependency.
+func New(pkg *domain.Package, graph *domain.GraphItem, status func(domain.Dependency) Status) *Graph {
+	rootID := pkg.Name
+	if rootID == "" {
+		rootID = "root"
+	}
+	nodes := graph.Nodes()
+	// Dependencies are nodes by module folder, whatever the spelling of the
+	// repository each package declares them with.
+	ids := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		ids[node.Value] = node.Dep.Repository
+	}
+
+	result := &Graph{}
+	for _, dep := range pkg.GetParsedDependencies() {
+		result.Edges = append(result.Edges, Edge{
+			From:       rootID,
+			To:         ids[domain.NewNode(&dep).Value],
+			Constraint: dep.GetVersion(),
+		})
+	}
+	for _, node := range nodes {
+		result.Nodes = append(result.Nodes, Node{
+			ID:         node.Dep.Repository,
+			Repository: node.Dep.Repository,
+			Version:    pkg.Lock.GetInstalled(node.Dep).Version,
+			Status:     status(node.Dep),
+		})
+		for _, dependency := range graph.DependsOn(node) {
+			result.Edges = append(result.Edges, Edge{
+				From:       node.Dep.Repository,
+				To:         ids[dependency.Value],
+				Constraint: dependency.Dep.GetVersion(),
+			})
+		}
+	}
+
+	slices.SortFunc(result.Nodes, func(a, b Node) int { return cmp.Compare(a.ID, b.ID) })
+	result.Nodes = slices.Insert(result.Nodes, 0, Node{ID: rootID, Version: pkg.Version, Root: true})
+	result.sortEdges()
+	return result
+}
+
+// Select returns the part of the graph options select.
+func (g *Graph) Select(options Options) (*Graph, error) {
+	if options.Depth < 0 {
+		return nil, errors.New("the depth cannot be negative")
+	}
+	if options.Reverse && options.Focus == "" {
+		return nil, errors.New("reverse dependencies need a dependency to focus on")
+	}
+	if options.Focus == "" && options.Depth == 0 {
+		return g, nil
+	}
+
+	start := ""
+	if options.Focus == "" {
+		start = g.Nodes[0].ID
+	} else {
+		focus, err := g.Find(options.Focus)
+		if err != nil {
+			return nil, err
+		}
+		start = focus.ID
+	}
+
+	next := map[string][]string{}
+	for _, edge := range g.Edges {
+		if options.Reverse {
+			next[edge.To] = append(next[edge.To], edge.From)
+		} else {
+			next[edge.From] = append(next[edge.From], edge.To)
+		}
+	}
+
+	kept := map[string]bool{start: true}
+	layer := []string{start}
+	for depth := 0; len(layer) > 0 && (options.Depth == 0 || depth < options.Depth); depth++ {
+		var following []string
+		for _, id := range layer {
+			for _, neighbour := range next[id] {
+				if !kept[neighbour] {
+					kept[neighbour] = true
+					following = append(following, neighbour)
+				}
+			}
+		}
+		layer = following
+	}
+
+	selected := &Graph{}
+	for _, node := range g.Nodes {
+		if kept[node.ID] {
+			selected.Nodes = append(selected.Nodes, node)
+		}
+	}
+	for _, edge := range g.Edges {
+		if kept[edge.From] && kept[edge.To] {
+			selected.Edges = append(selected.Edges, edge)
+		}
+	}
+	return selected, nil
+}
+
+// Find returns the dependency name designates: its repository, a path
+// suffix of it, or its module folder.
+func (g *Graph) Find(name string) (Node, error) {
+	index := slices.IndexFunc(g.Nodes, func(node Node) bool { return !node.Root && matches(node, name) })
+	if index < 0 {
+		return Node{}, fmt.Errorf("%s is not a dependency of this project", name)
+	}
+	return g.Nodes[index], nil
+}
+
+func (g *Graph) sortEdges() {
+	slices.SortFunc(g.Edges, func(a, b Edge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+	g.Edges = slices.CompactFunc(g.Edges, func(a, b Edge) bool { return a.From == b.From && a.To == b.To })
+}
+
+// matches reports whether name designates the dependency of node: its
+// repository, a path suffix of it, or its module folder.
+func matches(node Node, name string) bool {
+	name = strings.ToLower(strings.Trim(name, "/"))
+	repository := strings.ToLower(node.Repository)
+	dep := domain.Dependency{Repository: node.Repository}
+	return name == repository || strings.HasSuffix(repository, "/"+name) || name == strings.ToLower(dep.Name())
+}
@@ -0,0 +1,240 @@
+package depgraph_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/buildlog"
+	"github.com/hashload/boss/internal/core/services/depgraph"
+)
+
+// sampleGraph returns the graph of app → web → core and app → core, with
+// web and other depending on each other.
+func sampleGraph() *depgraph.Graph {
+	pkg := &domain.Package{
+		Name:         "app",
+		Version:      "2.0.0",
+		Dependencies: map[string]string{"github.com/test/web": "^1.0.0", "github.com/test/core": "^3.0.0"},
+		Lock: domain.PackageLock{Installed: map[string]domain.LockedDependency{
+			"github.com/test/web":  {Name: "github_com_test_web", Version: "1.4.0"},
+			"github.com/test/core": {Name: "github_com_test_core", Version: "3.1.0"},
+		}},
+	}
+
+	graph := &domain.GraphItem{}
+	node := func(repository, version string) *domain.Node {
+		dep := domain.ParseDependency(repository, version)
+		n := domain.NewNode(&dep)
+		graph.AddNode(n)
+		return n
+	}
+	web := node("github.com/test/web", "^1.0.0")
+	node("github.com/test/core", "^3.0.0")
+	other := node("github.com/test/other", "^0.1.0")
+	graph.AddEdge(web, node("github.com/test/core", "~3.1.0"))
+	graph.AddEdge(other, node("github.com/test/web", "^1.2.0"))
+	graph.AddEdge(web, node("github.com/test/other", "^0.2.0"))
+
+	status := func(dep domain.Dependency) depgraph.Status {
+		if dep.Repository == "github.com/test/other" {
+			return depgraph.StatusMissing
+		}
+		return depgraph.StatusOK
+	}
+	return depgraph.New(pkg, graph, status)
+}
+
+func edgeList(graph *depgraph.Graph) []string {
+	var edges []string
+	for _, edge := range graph.Edges {
+		edges = append(edges, edge.From+" -> "+edge.To+" "+edge.Constraint)
+	}
+	return edges
+}
+
+func nodeList(graph *depgraph.Graph) []string {
+	var nodes []string
+	for _, node := range graph.Nodes {
+		nodes = append(nodes, node.ID)
+	}
+	return nodes
+}
+
+func TestNew(t *testing.T) {
+	graph := sampleGraph()
+
+	wantNodes := []string{"app", "github.com/test/core", "github.com/test/other", "github.com/test/web"}
+	if got := nodeList(graph); !slices.Equal(got, wantNodes) {
+		t.Errorf("nodes = %v, want %v", got, wantNodes)
+	}
+	if !graph.Nodes[0].Root || graph.Nodes[0].Version != "2.0.0" {
+		t.Errorf("root node = %+v", graph.Nodes[0])
+	}
+	if graph.Nodes[1].Version != "3.1.0" || graph.Nodes[1].Status != depgraph.StatusOK {
+		t.Errorf("core node = %+v", graph.Nodes[1])
+	}
+	if graph.Nodes[2].Status != depgraph.StatusMissing {
+		t.Errorf("other node = %+v", graph.Nodes[2])
+	}
+
+	wantEdges := []string{
+		"app -> github.com/test/core ^3.0.0",
+		"app -> github.com/test/web ^1.0.0",
+		"github.com/test/other -> github.com/test/web ^1.2.0",
+		"github.com/test/web -> github.com/test/core ~3.1.0",
+		"github.com/test/web -> github.com/test/other ^0.2.0",
+	}
+	if got := edgeList(graph); !slices.Equal(got, wantEdges) {
+		t.Errorf("edges = %q, want %q", got, wantEdges)
+	}
+}
+
+func TestGraph_Select(t *testing.T) {
+	tests := []struct {
+		name    string
+		options depgraph.Options
+		want    []string
+		wantErr bool
+	}{
+		{name: "all", options: depgraph.Options{},
+			want: []string{"app", "github.com/test/core", "github.com/test/other", "github.com/test/web"}},
+		{name: "depth from root", options: depgraph.Options{Depth: 1},
+			want: []string{"app", "github.com/test/core", "github.com/test/web"}},
+		{name: "focus", options: depgraph.Options{Focus: "test/other"},
+			want: []string{"github.com/test/core", "github.com/test/other", "github.com/test/web"}},
+		{name: "focus with depth", options: depgraph.Options{Focus: "github_com_test_other", Depth: 1},
+			want: []string{"github.com/test/other", "github.com/test/web"}},
+		{name: "reverse", options: depgraph.Options{Focus: "core", Reverse: true},
+			want: []string{"app", "github.com/test/core", "github.com/test/other", "github.com/test/web"}},
+		{name: "reverse with depth", options: depgraph.Options{Focus: "web", Reverse: true, Depth: 1},
+			want: []string{"app", "github.com/test/other", "github.com/test/web"}},
+		{name: "unknown focus", options: depgraph.Options{Focus: "missing"}, wantErr: true},
+		{name: "reverse without focus", options: depgraph.Options{Reverse: true}, wantErr: true},
+		{name: "negative depth", options: depgraph.Options{Depth: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := sampleGraph().Select(tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Select() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := nodeList(selected); !slices.Equal(got, tt.want) {
+				t.Errorf("Select() nodes = %v, want %v", got, tt.want)
+			}
+			for _, edge := range selected.Edges {
+				if !slices.Contains(tt.want, edge.From) || !slices.Contains(tt.want, edge.To) {
+					t.Errorf("Select() kept edge %+v to a node it left out", edge)
+				}
+			}
+		})
+	}
+}
+
+func TestGraph_Find(t *testing.T) {
+	graph := sampleGraph()
+	for _, name := range []string{"github.com/test/web", "test/web", "WEB"} {
+		if node, err := graph.Find(name); err != nil || node.ID != "github.com/test/web" {
+			t.Errorf("Find(%q) = %v, %v, want github.com/test/web", name, node.ID, err)
+		}
+	}
+	if _, err := graph.Find("app"); err == nil {
+		t.Error("Find(app) error = nil, want the root package not to be a dependency")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	graph, err := sampleGraph().Select(depgraph.Options{Focus: "other", Depth: 1})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+
+	var dot bytes.Buffer
+	if err = depgraph.WriteDOT(&dot, graph); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+	for _, want := range []string{
+		"digraph dependencies {",
+		`n0 [label="github.com/test/other\n(missing)", style=dashed, fontcolor=gray];`,
+		`n1 [label="github.com/test/web\n1.4.0"];`,
+		`n0 -> n1 [label="^1.2.0"];`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("WriteDOT() = %s, want it to contain %s", dot.String(), want)
+		}
+	}
+
+	var mermaid bytes.Buffer
+	if err = depgraph.WriteMermaid(&mermaid, graph); err != nil {
+		t.Fatalf("WriteMermaid() error = %v", err)
+	}
+	for _, want := range []string{
+		"graph LR\n",
+		`n0["github.com/test/other<br/>(missing)"]`,
+		`n0 -->|"^1.2.0"| n1`,
+		"class n0 missing",
+	} {
+		if !strings.Contains(mermaid.String(), want) {
+			t.Errorf("WriteMermaid() = %s, want it to contain %s", mermaid.String(), want)
+		}
+	}
+
+	var encoded bytes.Buffer
+	if err = depgraph.WriteJSON(&encoded, graph); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded depgraph.Graph
+	if err = json.Unmarshal(encoded.Bytes(), &decoded); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid JSON: %v", err)
+	}
+	if len(decoded.Nodes) != 2 || len(decoded.Edges) != 2 || decoded.Edges[0].Constraint != "^1.2.0" {
+		t.Errorf("WriteJSON() = %s", encoded.String())
+	}
+}
+
+func TestFailedBuilds(t *testing.T) {
+	logsDir := t.TempDir()
+	record := func(statuses map[string]buildlog.Status) {
+		t.Helper()
+		run, err := buildlog.Start(logsDir, buildlog.RunInfo{})
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		for repository, status := range statuses {
+			module := domain.Dependency{Repository: repository}
+			if _, err = run.LogPath(module.Name(), "Project"); err != nil {
+				t.Fatalf("LogPath() error = %v", err)
+			}
+			entry := buildlog.Entry{Module: module.Name(), Repository: repository, Project: "Project", Status: status}
+			if err = run.Record(entry); err != nil {
+				t.Fatalf("Record() error = %v", err)
+			}
+		}
+		if err = run.Finish(); err != nil {
+			t.Fatalf("Finish() error = %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	record(map[string]buildlog.Status{
+		"github.com/test/web":  buildlog.StatusFailed,
+		"github.com/test/core": buildlog.StatusFailed,
+	})
+	record(map[string]buildlog.Status{"github.com/test/Web": buildlog.StatusSuccess})
+
+	failed, err := depgraph.FailedBuilds(logsDir)
+	if err != nil {
+		t.Fatalf("FailedBuilds() error = %v", err)
+	}
+	if failed["github.com/test/web"] || !failed["github.com/test/core"] || len(failed) != 1 {
+		t.Errorf("FailedBuilds() = %v, want only github.com/test/core", failed)
+	}
+}
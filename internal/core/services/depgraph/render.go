@@ -0,0 +1,120 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteJSON writes the graph as indented JSON.
+func WriteJSON(w io.Writer, graph *Graph) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(graph)
+}
+
+// dotStyles are the Graphviz attributes of the nodes of each status.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var dotStyles = map[Status]string{
+	StatusChanged: `, style=filled, fillcolor="#fff3cd"`,
+	StatusFailed:  `, style=filled, fillcolor="#f8d7da", color="#dc3545"`,
+	StatusMissing: `, style=dashed, fontcolor=gray`,
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+func WriteDOT(w io.Writer, graph *Graph) error {
+	var out strings.Builder
+	out.WriteString("digraph dependencies {\n  rankdir=LR;\n  node [shape=box];\n")
+	ids := nodeIDs(graph)
+	for _, node := range graph.Nodes {
+		style := dotStyles[node.Status]
+		if node.Root {
+			style = ", style=bold"
+		}
+		fmt.Fprintf(&out, "  %s [label=%s%s];\n", ids[node.ID], dotQuote(nodeLabel(node, `\n`)), style)
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&out, "  %s -> %s", ids[edge.From], ids[edge.To])
+		if edge.Constraint != "" {
+			fmt.Fprintf(&out, " [label=%s]", dotQuote(edge.Constraint))
+		}
+		out.WriteString(";\n")
+	}
+	out.WriteString("}\n")
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// mermaidClasses are the Mermaid class definitions of the nodes of each
+// status.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var mermaidClasses = map[Status]string{
+	StatusChanged: "fill:#fff3cd,stroke:#ffc107",
+	StatusFailed:  "fill:#f8d7da,stroke:#dc3545",
+	StatusMissing: "stroke-dasharray:5 5,color:gray",
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart.
+func WriteMermaid(w io.Writer, graph *Graph) error {
+	var out strings.Builder
+	out.WriteString("graph LR\n")
+	ids := nodeIDs(graph)
+	classes := map[Status][]string{}
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(&out, "  %s[%s]\n", ids[node.ID], mermaidQuote(nodeLabel(node, "<br/>")))
+		if _, ok := mermaidClasses[node.Status]; ok {
+			classes[node.Status] = append(classes[node.Status], ids[node.ID])
+		}
+	}
+	for _, edge := range graph.Edges {
+		if edge.Constraint != "" {
+			fmt.Fprintf(&out, "  %s -->|%s| %s\n", ids[edge.From], mermaidQuote(edge.Constraint), ids[edge.To])
+		} else {
+			fmt.Fprintf(&out, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+		}
+	}
+	for _, status := range []Status{StatusChanged, StatusFailed, StatusMissing} {
+		if len(classes[status]) > 0 {
+			fmt.Fprintf(&out, "  classDef %s %s\n", status, mermaidClasses[status])
+			fmt.Fprintf(&out, "  class %s %s\n", strings.Join(classes[status], ","), status)
+		}
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// nodeIDs returns the identifiers of the nodes in DOT and Mermaid, which do
+// not accept repositories as they are.
+func nodeIDs(graph *Graph) map[string]string {
+	ids := make(map[string]string, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+	}
+	return ids
+}
+
+// nodeLabel returns the label of a node: its ID, then its version and status
+// on lines of their own, separated by newline.
+func nodeLabel(node Node, newline string) string {
+	label := node.ID
+	if node.Version != "" {
+		label += newline + node.Version
+	}
+	if node.Status != "" && node.Status != StatusOK {
+		label += newline + "(" + string(node.Status) + ")"
+	}
+	return label
+}
+
+func dotQuote(value string) string {
+	// The newlines of labels are already escaped as \n.
+	escaped := strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + escaped + `"`
+}
+
+func mermaidQuote(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,66 @@
+package depgraph
+
+import (
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/buildlog"
+	lockService "github.com/hashload/boss/internal/core/services/lock"
+)
+
+// LockStatus returns the status of dependencies as lock and the modules in
+// modulesDir tell: missing when not locked, changed when lockSvc finds their
+// files or artifacts out of date, failed when in failed.
+func LockStatus(
+	lock *domain.PackageLock,
+	lockSvc *lockService.LockService,
+	modulesDir string,
+	failed map[string]bool,
+) func(domain.Dependency) Status {
+	return func(dep domain.Dependency) Status {
+		locked, ok := lock.Installed[dep.GetKey()]
+		switch {
+		case !ok:
+			return StatusMissing
+		case failed[dep.GetKey()]:
+			return StatusFailed
+		case lockSvc.NeedUpdate(lock, dep, locked.Version, modulesDir):
+			return StatusChanged
+		default:
+			return StatusOK
+		}
+	}
+}
+
+// FailedBuilds returns the repositories, lower case, whose latest build logged
+// in logsDir failed.
+func FailedBuilds(logsDir string) (map[string]bool, error) {
+	runs, err := buildlog.Runs(logsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := map[string]bool{}
+	built := map[string]bool{}
+	for _, run := range runs {
+		entries, err := buildlog.Entries(logsDir, run.ID)
+		if err != nil {
+			return nil, err
+		}
+		var seen []string
+		for _, entry := range entries {
+			key := strings.ToLower(entry.Repository)
+			if entry.Repository == "" || built[key] {
+				continue
+			}
+			seen = append(seen, key)
+			if entry.Status == buildlog.StatusFailed {
+				failed[key] = true
+			}
+		}
+		for _, key := range seen {
+			built[key] = true
+		}
+	}
+	return failed, nil
+}
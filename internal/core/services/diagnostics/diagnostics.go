@@ -0,0 +1,178 @@
+// Package diagnostics turns compiler output into structured diagnostics. It
+// understands the messages of the Delphi compilers (dcc32, dcc64, ...), as
+// printed by MSBuild or the IDE, and of Free Pascal (fpc and lazbuild).
+package diagnostics
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity is the importance of a diagnostic.
+type Severity string
+
+const (
+	// SeverityError fails the compilation; fatal errors are errors too.
+	SeverityError Severity = "error"
+	// SeverityWarning flags code that compiles but is likely wrong.
+	SeverityWarning Severity = "warning"
+	// SeverityHint covers hints and FPC notes.
+	SeverityHint Severity = "hint"
+)
+
+// Diagnostic is one compiler message.
+type Diagnostic struct {
+	// Module is the dependency the project belongs to, empty for a project
+	// of the root package.
+	Module   string   `json:"module,omitempty"`
+	Project  string   `json:"project"`
+	Severity Severity `json:"severity"`
+	// File is the source file, absolute when the compiler gave enough to
+	// resolve it, and empty for a message that has no location.
+	File   string `json:"file,omitempty"`
+	Unit   string `json:"unit,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+	// Code is the compiler message code: E2003 for dcc, 5000 for FPC.
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// String formats the diagnostic the way compilers do.
+func (d Diagnostic) String() string {
+	var text strings.Builder
+	if d.File != "" {
+		text.WriteString(d.File)
+		if d.Line > 0 {
+			text.WriteString("(" + strconv.Itoa(d.Line))
+			if d.Column > 0 {
+				text.WriteString("," + strconv.Itoa(d.Column))
+			}
+			text.WriteString(")")
+		}
+		text.WriteString(": ")
+	}
+	text.WriteString(string(d.Severity))
+	if d.Code != "" {
+		text.WriteString(" " + d.Code)
+	}
+	text.WriteString(": " + d.Message)
+	return text.String()
+}
+
+// The message formats, tried in order on every line.
+//
+//nolint:gochecknoglobals // Compiled once, read-only
+var (
+	// MSBuild: Unit1.pas(12): error E2003: Undeclared identifier: 'x' [C:\p\Project.dproj]
+	// and its own errors, such as MSB3073.
+	msbuildPattern = regexp.MustCompile(`(?i)^\s*(.+?)\((\d+)(?:,(\d+))?\)\s*:\s*` +
+		`(error|warning|hint warning|hint|fatal)\s+([A-Z]+\d+)\s*:\s*(.*?)(?:\s+\[[^\]]+\])?\s*$`)
+	// IDE: [dcc32 Error] Unit1.pas(12): E2003 Undeclared identifier: 'x'
+	idePattern = regexp.MustCompile(`(?i)^\s*\[dcc\w*\s+(fatal error|error|warning|hint|fatal)\]\s*` +
+		`(?:(.+?)\((\d+)(?:,(\d+))?\)\s*:\s*)?(?:([EWHF]\d+)\s+)?(.*?)\s*$`)
+	// dcc and FPC: Unit1.pas(12) Error: E2003 Undeclared identifier: 'x'
+	//              unit1.pas(12,5) Error: (5000) Identifier not found "x"
+	compilerPattern = regexp.MustCompile(`(?i)^\s*(.+?)\((\d+)(?:,(\d+))?\)\s+` +
+		`(error|warning|hint|note|fatal)\s*:\s*(?:\((\d+)\)\s*)?(?:([EWHF]\d{4})\s+)?(.*?)\s*$`)
+	// A message without location: Fatal: F1026 File not found: 'x.dcu'
+	bareDiagnosticPattern = regexp.MustCompile(`(?i)^\s*(error|fatal)\s*:\s*(?:\((\d+)\)\s*)?` +
+		`(?:([EWHF]\d{4})\s+)?(.+?)\s*$`)
+	windowsAbsPath = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+)
+
+// Parse reads compiler output and returns its diagnostics, without the
+// repetitions MSBuild prints in its summary. Relative file names are resolved
+// against dir, the directory the compiler ran in.
+func Parse(r io.Reader, dir string) []Diagnostic {
+	var result []Diagnostic
+	seen := map[Diagnostic]bool{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		diagnostic, ok := parseLine(scanner.Text())
+		if !ok || seen[diagnostic] {
+			continue
+		}
+		seen[diagnostic] = true
+		if diagnostic.File != "" {
+			diagnostic.Unit = strings.TrimSuffix(baseName(diagnostic.File), filepath.Ext(baseName(diagnostic.File)))
+			if !filepath.IsAbs(diagnostic.File) && !windowsAbsPath.MatchString(diagnostic.File) {
+				diagnostic.File = filepath.Join(dir, diagnostic.File)
+			}
+		}
+		result = append(result, diagnostic)
+	}
+	return result
+}
+
+// ParseFile parses a build log; a log that cannot be read has no diagnostics.
+func ParseFile(path string) []Diagnostic {
+	file, err := os.Open(path) // #nosec G304 -- Reading a build log written by Boss
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = file.Close() }()
+	return Parse(file, filepath.Dir(path))
+}
+
+// baseName handles both separators, as logs written on Windows are parsed
+// everywhere.
+func baseName(path string) string {
+	return path[strings.LastIndexAny(path, `\/`)+1:]
+}
+
+func parseLine(line string) (Diagnostic, bool) {
+	if match := msbuildPattern.FindStringSubmatch(line); match != nil {
+		return Diagnostic{
+			File: match[1], Line: atoi(match[2]), Column: atoi(match[3]),
+			Severity: severity(match[4]), Code: match[5], Message: match[6],
+		}, true
+	}
+	if match := idePattern.FindStringSubmatch(line); match != nil && match[6] != "" {
+		return Diagnostic{
+			File: match[2], Line: atoi(match[3]), Column: atoi(match[4]),
+			Severity: severity(match[1]), Code: match[5], Message: match[6],
+		}, true
+	}
+	if match := compilerPattern.FindStringSubmatch(line); match != nil {
+		return Diagnostic{
+			File: match[1], Line: atoi(match[2]), Column: atoi(match[3]),
+			Severity: severity(match[4]), Code: firstOf(match[6], match[5]), Message: match[7],
+		}, true
+	}
+	if match := bareDiagnosticPattern.FindStringSubmatch(line); match != nil {
+		return Diagnostic{Severity: SeverityError, Code: firstOf(match[3], match[2]), Message: match[4]}, true
+	}
+	return Diagnostic{}, false
+}
+
+func severity(text string) Severity {
+	switch text = strings.ToLower(text); {
+	case strings.HasPrefix(text, "hint"), text == "note":
+		return SeverityHint
+	case text == "warning":
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
+
+func atoi(text string) int {
+	value, _ := strconv.Atoi(text)
+	return value
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
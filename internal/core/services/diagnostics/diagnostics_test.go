@@ -0,0 +1,138 @@
+package diagnostics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashload/boss/internal/core/services/diagnostics"
+)
+
+const msbuildLog = `Build started.
+Project "C:\src\Horse.dproj" on node 1 (default targets).
+C:\src\Horse.Core.pas(42): warning W1036: Variable 'I' might not have been initialized [C:\src\Horse.dproj]
+Horse.Utils.pas(7): hint warning H2164: Variable 'X' is declared but never used [C:\src\Horse.dproj]
+C:\src\Horse.Core.pas(120,8): error E2003: Undeclared identifier: 'Foo' [C:\src\Horse.dproj]
+Horse.dpk(35): fatal F2063: Could not compile used unit 'Horse.Core.pas' [C:\src\Horse.dproj]
+Build FAILED.
+C:\src\Horse.Core.pas(120,8): error E2003: Undeclared identifier: 'Foo' [C:\src\Horse.dproj]
+`
+
+func TestParse_MSBuild(t *testing.T) {
+	got := diagnostics.Parse(strings.NewReader(msbuildLog), "/build")
+	if len(got) != 4 {
+		t.Fatalf("Parse() returned %d diagnostics, want 4 (repetitions removed): %+v", len(got), got)
+	}
+
+	want := diagnostics.Diagnostic{
+		Severity: diagnostics.SeverityError, File: `C:\src\Horse.Core.pas`, Unit: "Horse.Core",
+		Line: 120, Column: 8, Code: "E2003", Message: "Undeclared identifier: 'Foo'",
+	}
+	if got[2] != want {
+		t.Errorf("error = %+v, want %+v", got[2], want)
+	}
+	if got[1].Severity != diagnostics.SeverityHint || got[1].File != filepath.Join("/build", "Horse.Utils.pas") {
+		t.Errorf("hint = %+v, want a hint resolved against the build directory", got[1])
+	}
+	if got[3].Severity != diagnostics.SeverityError || got[3].Code != "F2063" {
+		t.Errorf("fatal = %+v, want an error F2063", got[3])
+	}
+}
+
+func TestParse_IDEAndCommandLineFormats(t *testing.T) {
+	log := strings.Join([]string{
+		"[dcc32 Error] Unit1.pas(12): E2003 Undeclared identifier: 'x'",
+		"[dcc32 Fatal Error] F1026 File not found: 'Missing.dcu'",
+		"Unit2.pas(3) Warning: W1000 Symbol 'Old' is deprecated",
+		"Fatal: F2039 Could not create output file 'x.exe'",
+	}, "\n")
+
+	got := diagnostics.Parse(strings.NewReader(log), "")
+	if len(got) != 4 {
+		t.Fatalf("Parse() returned %d diagnostics, want 4: %+v", len(got), got)
+	}
+	if got[0].Code != "E2003" || got[0].Line != 12 || got[0].Unit != "Unit1" {
+		t.Errorf("IDE error = %+v", got[0])
+	}
+	if got[1].File != "" || got[1].Code != "F1026" || got[1].Severity != diagnostics.SeverityError {
+		t.Errorf("IDE fatal error = %+v", got[1])
+	}
+	if got[2].Severity != diagnostics.SeverityWarning || got[2].Code != "W1000" {
+		t.Errorf("dcc warning = %+v", got[2])
+	}
+	if got[3].Code != "F2039" || got[3].Message != "Could not create output file 'x.exe'" {
+		t.Errorf("bare fatal = %+v", got[3])
+	}
+}
+
+func TestParse_FPC(t *testing.T) {
+	log := strings.Join([]string{
+		"Compiling unit1.pas",
+		"unit1.pas(12,5) Error: (5000) Identifier not found \"Foo\"",
+		"unit1.pas(30,3) Note: (5025) Local variable \"i\" not used",
+		"unit1.pas(31,1) Fatal: There were 1 errors compiling module, stopping",
+		"Error: /usr/bin/ppcx64 returned an error exitcode",
+	}, "\n")
+
+	got := diagnostics.Parse(strings.NewReader(log), "/src")
+	if len(got) != 4 {
+		t.Fatalf("Parse() returned %d diagnostics, want 4: %+v", len(got), got)
+	}
+	if got[0].Code != "5000" || got[0].Column != 5 || got[0].Message != `Identifier not found "Foo"` {
+		t.Errorf("FPC error = %+v", got[0])
+	}
+	if got[1].Severity != diagnostics.SeverityHint {
+		t.Errorf("FPC note = %+v, want a hint", got[1])
+	}
+}
+
+func TestReport_WriteJSONAndSARIF(t *testing.T) {
+	report := diagnostics.NewReport()
+	parsed := diagnostics.Parse(strings.NewReader(msbuildLog), "/project/modules/horse")
+	report.Add("horse", "Horse.dproj", parsed)
+
+	if errors := diagnostics.Errors(report.Diagnostics(), 1); len(errors) != 1 || errors[0].Module != "horse" {
+		t.Errorf("Errors() = %+v", errors)
+	}
+
+	var out bytes.Buffer
+	if err := diagnostics.WriteJSON(&out, report); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var counts struct{ Errors, Warnings, Hints int }
+	if err := json.Unmarshal(out.Bytes(), &counts); err != nil || counts.Errors != 2 || counts.Warnings != 1 ||
+		counts.Hints != 1 {
+		t.Errorf("WriteJSON() counts = %+v, %v", counts, err)
+	}
+
+	out.Reset()
+	if err := diagnostics.WriteSARIF(&out, report, "/project"); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+	var sarif struct {
+		Runs []struct {
+			Results []struct {
+				Level     string
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+						Region           struct{ StartLine int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &sarif); err != nil {
+		t.Fatalf("WriteSARIF() wrote invalid JSON: %v", err)
+	}
+	results := sarif.Runs[0].Results
+	if len(results) != 4 || results[1].Level != "note" {
+		t.Fatalf("WriteSARIF() results = %+v", results)
+	}
+	if location := results[1].Locations[0].PhysicalLocation; location.ArtifactLocation.URI !=
+		"modules/horse/Horse.Utils.pas" || location.Region.StartLine != 7 {
+		t.Errorf("hint location = %+v, want a path relative to the project", location)
+	}
+}
@@ -0,0 +1,221 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "boss build"
+	toolInfoURI  = "https://github.com/HashLoad/boss"
+)
+
+// Report gathers the diagnostics of every project of a build. It is safe for
+// concurrent use; a nil *Report discards everything.
+type Report struct {
+	mu          sync.Mutex
+	diagnostics []Diagnostic
+}
+
+// NewReport creates an empty report.
+func NewReport() *Report {
+	return &Report{}
+}
+
+// Add records the diagnostics of a project of module (empty for the root
+// package) and returns them.
+func (r *Report) Add(module, project string, diagnostics []Diagnostic) []Diagnostic {
+	for i := range diagnostics {
+		diagnostics[i].Module = module
+		diagnostics[i].Project = project
+	}
+	if r == nil {
+		return diagnostics
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.diagnostics = append(r.diagnostics, diagnostics...)
+	return diagnostics
+}
+
+// Diagnostics returns everything recorded, in the order it was added.
+func (r *Report) Diagnostics() []Diagnostic {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Diagnostic(nil), r.diagnostics...)
+}
+
+// Count returns the number of diagnostics of a severity.
+func (r *Report) Count(severity Severity) int {
+	count := 0
+	for _, diagnostic := range r.Diagnostics() {
+		if diagnostic.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Errors returns the first limit errors of diagnostics, all of them when limit
+// is not positive.
+func Errors(diagnostics []Diagnostic, limit int) []Diagnostic {
+	var errors []Diagnostic
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity != SeverityError {
+			continue
+		}
+		if limit > 0 && len(errors) == limit {
+			break
+		}
+		errors = append(errors, diagnostic)
+	}
+	return errors
+}
+
+type jsonReport struct {
+	Errors      int          `json:"errors"`
+	Warnings    int          `json:"warnings"`
+	Hints       int          `json:"hints"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// WriteJSON writes the report as indented JSON, with a count per severity.
+func WriteJSON(w io.Writer, report *Report) error {
+	diagnostics := report.Diagnostics()
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonReport{
+		Errors:      report.Count(SeverityError),
+		Warnings:    report.Count(SeverityWarning),
+		Hints:       report.Count(SeverityHint),
+		Diagnostics: diagnostics,
+	})
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// WriteSARIF writes the report as a SARIF 2.1.0 log. Files under baseDir, the
+// project directory, are given relative to it so code scanning tools can map
+// them to the repository.
+func WriteSARIF(w io.Writer, report *Report, baseDir string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			InformationURI: toolInfoURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	seen := map[string]bool{}
+	for _, diagnostic := range report.Diagnostics() {
+		ruleID := diagnostic.Code
+		if ruleID == "" {
+			ruleID = "compiler-" + string(diagnostic.Severity)
+		}
+		if !seen[ruleID] {
+			seen[ruleID] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: ruleID})
+		}
+
+		result := sarifResult{
+			RuleID:  ruleID,
+			Level:   sarifLevel(diagnostic.Severity),
+			Message: sarifText{Text: diagnostic.Message},
+		}
+		if diagnostic.File != "" {
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: sarifURI(diagnostic.File, baseDir)}}
+			if diagnostic.Line > 0 {
+				location.Region = &sarifRegion{StartLine: diagnostic.Line, StartColumn: diagnostic.Column}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+func sarifURI(file, baseDir string) string {
+	if relative, err := filepath.Rel(baseDir, file); err == nil && !strings.HasPrefix(relative, "..") {
+		return filepath.ToSlash(relative)
+	}
+	return filepath.ToSlash(file)
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityHint:
+		return "note"
+	}
+	return "note"
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/hashload/boss/pkg/pkgmanager"
@@ -17,9 +18,12 @@ import (
 	git "github.com/hashload/boss/internal/adapters/secondary/git"
 	"github.com/hashload/boss/internal/adapters/secondary/repository"
 	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/collisions"
 	"github.com/hashload/boss/internal/core/services/compiler"
 	lockService "github.com/hashload/boss/internal/core/services/lock"
 	"github.com/hashload/boss/internal/core/services/paths"
+	"github.com/hashload/boss/internal/core/services/signature"
+	"github.com/hashload/boss/internal/core/services/timing"
 	"github.com/hashload/boss/internal/core/services/tracker"
 	"github.com/hashload/boss/pkg/consts"
 	"github.com/hashload/boss/pkg/env"
@@ -43,6 +47,8 @@ type installContext struct {
 	warnings         []string
 	depManager       *DependencyManager
 	requestedDeps    map[string]bool // Track which dependencies were explicitly requested
+	timings          *timing.Recorder
+	collisions       map[string]bool // Units, lower case, whose collisions were already reported
 }
 
 //nolint:lll // Function signature readability
@@ -71,8 +77,10 @@ func newInstallContext(config env.ConfigProvider, pkg *domain.Package, options I
 		modulesDir:       env.GetModulesDir(),
 		options:          options,
 		warnings:         make([]string, 0),
+		collisions:       make(map[string]bool),
 		depManager:       NewDefaultDependencyManager(config),
 		requestedDeps:    requestedDeps,
+		timings:          timing.NewRecorder(),
 	}
 }
 
@@ -125,6 +133,9 @@ func DoInstall(config env.ConfigProvider, options InstallOptions, pkg *domain.Pa
 	if len(options.Args) == 0 {
 		pkg.Lock.CleanRemoved(dependencies)
 	}
+	if err := installContext.checkSourceCollisions(); err != nil {
+		return err
+	}
 	if err := pkgmanager.SavePackageCurrent(pkg); err != nil {
 		msg.Warn("⚠️ Failed to save package: %v", err)
 	}
@@ -134,7 +145,21 @@ func DoInstall(config env.ConfigProvider, options InstallOptions, pkg *domain.Pa
 
 	librarypath.UpdateLibraryPath(pkg)
 
-	compiler.Build(pkg, options.Compiler, options.Platform)
+	buildErr := compiler.Build(pkg, compiler.BuildOptions{
+		Compiler:         options.Compiler,
+		Platform:         options.Platform,
+		Matrix:           options.Matrix,
+		Config:           options.Config,
+		Jobs:             options.Jobs,
+		NoCache:          options.NoCache,
+		DiagnosticsJSON:  options.DiagnosticsJSON,
+		DiagnosticsSARIF: options.DiagnosticsSARIF,
+		Timings:          installContext.timings,
+	})
+	compiler.ReportTimings(installContext.timings, options.Profile, pkg.Name)
+	if buildErr != nil {
+		return buildErr
+	}
 	if err := pkgmanager.SavePackageCurrent(pkg); err != nil {
 		msg.Warn("⚠️ Failed to save package: %v", err)
 	}
@@ -142,6 +167,10 @@ func DoInstall(config env.ConfigProvider, options InstallOptions, pkg *domain.Pa
 		msg.Warn("⚠️ Failed to save lock file: %v", err)
 	}
 
+	if err := installContext.checkCollisions(); err != nil {
+		return err
+	}
+
 	if len(installContext.warnings) > 0 {
 		msg.Warn("⚠️ Installation Warnings:")
 		for _, warning := range installContext.warnings {
@@ -157,6 +186,52 @@ func (ic *installContext) addWarning(warning string) {
 	ic.warnings = append(ic.warnings, warning)
 }
 
+// strict reports whether problems with dependencies fail the installation
+// instead of being reported as warnings.
+func (ic *installContext) strict() bool {
+	return ic.options.Strict || (ic.root.Toolchain != nil && ic.root.Toolchain.Strict)
+}
+
+// checkSourceCollisions reports the units whose sources more than one
+// installed dependency provides, before anything is built or saved.
+func (ic *installContext) checkSourceCollisions() error {
+	return ic.reportCollisions(collisions.ScanSources(env.GetModulesDir(), ic.root.Lock))
+}
+
+// checkCollisions reports the units provided by more than one installed
+// dependency once they are built, counting their compiled units, beyond those
+// checkSourceCollisions reported.
+func (ic *installContext) checkCollisions() error {
+	return ic.reportCollisions(collisions.Scan(env.GetModulesDir(), ic.root.Lock))
+}
+
+// reportCollisions reports the collisions not reported yet. In strict mode
+// they fail the installation, otherwise they are warnings.
+func (ic *installContext) reportCollisions(found []collisions.Collision) error {
+	var fresh []collisions.Collision
+	for _, collision := range found {
+		unit := strings.ToLower(collision.Unit)
+		if !ic.collisions[unit] {
+			ic.collisions[unit] = true
+			fresh = append(fresh, collision)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+	if !ic.strict() {
+		for _, collision := range fresh {
+			ic.addWarning(collision.String())
+		}
+		return nil
+	}
+
+	for _, collision := range fresh {
+		msg.Err("  - %s", collision)
+	}
+	return fmt.Errorf("❌ %d unit name collision(s) between dependencies", len(fresh))
+}
+
 // collectDependenciesToInstall collects dependencies to install based on args filter.
 // If args is empty, returns all dependencies. Otherwise, returns only specified ones.
 func collectDependenciesToInstall(pkg *domain.Package, args []string) []domain.Dependency {
@@ -335,7 +410,9 @@ func (ic *installContext) cloneDependency(dep domain.Dependency, depName string)
 		ic.reportStatus(depName, "cloning", "🧬 Cloning")
 	}
 
+	stop := ic.timings.Start(timing.StepFetch, depName, "")
 	err := GetDependencyWithProgress(dep, ic.progress)
+	stop()
 	if err != nil {
 		ic.progress.SetFailed(depName, err)
 		return err
@@ -370,9 +447,22 @@ func (ic *installContext) checkIfUpToDate(
 	}
 
 	currentRef := head.Name()
+	stop := ic.timings.Start(timing.StepHash, depName, "")
 	needsUpdate := ic.lockSvc.NeedUpdate(ic.rootLocked, dep, referenceName.Short(), ic.modulesDir)
+	stop()
 
 	if !needsUpdate && status.IsClean() && referenceName == currentRef {
+		// Locks written before commits were recorded get theirs on the next install.
+		if ic.rootLocked.GetInstalled(dep).Commit == "" {
+			ic.rootLocked.SetCommit(dep, head.Hash().String())
+		}
+		// The trust rules or keys may have changed since the lock was written.
+		signer, err := ic.verifySignature(dep, repository, head.Hash())
+		if err != nil {
+			ic.progress.SetFailed(depName, err)
+			return false, err
+		}
+		ic.rootLocked.SetSigner(dep, signer)
 		ic.reportSkipped(depName, consts.StatusMsgUpToDate)
 		return true, nil
 	}
@@ -388,11 +478,28 @@ func (ic *installContext) installDependency(
 ) error {
 	ic.reportStatus(depName, "installing", "🔥 Installing")
 
-	if err := ic.checkoutAndUpdate(dep, repository, referenceName); err != nil {
+	reference, err := repository.Reference(referenceName, true)
+	if err != nil {
+		ic.progress.SetFailed(depName, err)
+		return err
+	}
+	signer, err := ic.verifySignature(dep, repository, reference.Hash())
+	if err != nil {
+		ic.progress.SetFailed(depName, err)
+		return err
+	}
+
+	if err = ic.checkoutAndUpdate(dep, repository, referenceName); err != nil {
 		ic.progress.SetFailed(depName, err)
 		return err
 	}
 
+	if signer, err = ic.verifyCheckedOut(dep, repository, reference.Hash(), signer); err != nil {
+		ic.progress.SetFailed(depName, err)
+		return err
+	}
+	ic.rootLocked.SetSigner(dep, signer)
+
 	warning, err := ic.verifyDependencyCompatibility(dep)
 	if err != nil {
 		ic.progress.SetFailed(depName, err)
@@ -403,6 +510,82 @@ func (ic *installContext) installDependency(
 	return nil
 }
 
+// verifyCheckedOut checks the signature of HEAD again when checking out or
+// pulling moved it past the verified commit, and returns its signer.
+//
+//nolint:nilnil // No signer is not an error unless the policy requires one
+func (ic *installContext) verifyCheckedOut(
+	dep domain.Dependency,
+	repository *goGit.Repository,
+	verified plumbing.Hash,
+	signer *domain.LockedSigner,
+) (*domain.LockedSigner, error) {
+	head, err := git.HeadCommit(dep)
+	if err != nil {
+		rule, ruleErr := ic.trustRule(dep)
+		if ruleErr != nil {
+			return nil, ruleErr
+		}
+		if rule.Policy == env.SignatureOff {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to resolve the commit of %s to verify its signature: %w", dep.Name(), err)
+	}
+	if head == verified.String() {
+		return signer, nil
+	}
+	return ic.verifySignature(dep, repository, plumbing.NewHash(head))
+}
+
+// trustRule returns the signature rule of the dependency, from the project
+// and global trust settings.
+func (ic *installContext) trustRule(dep domain.Dependency) (signature.Rule, error) {
+	return signature.RuleFor(dep.Repository,
+		signature.Source{Config: ic.root.Trust, Dir: env.GetCurrentDir()},
+		signature.Source{Config: ic.config.GetTrust(), Dir: env.GetBossHome()})
+}
+
+// verifySignature checks the tag or commit at hash against the trust rule of
+// the dependency. It returns the signer, or nil when the rule is off or only
+// warns; under a "require" rule a missing or untrusted signature is an error.
+//
+//nolint:nilnil // No signer is not an error unless the policy requires one
+func (ic *installContext) verifySignature(
+	dep domain.Dependency,
+	repository *goGit.Repository,
+	hash plumbing.Hash,
+) (*domain.LockedSigner, error) {
+	rule, err := ic.trustRule(dep)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Policy == env.SignatureOff {
+		return nil, nil
+	}
+
+	ring, err := signature.LoadKeyRing(rule.Keys, rule.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("trust rule for %s: %w", rule.Scope, err)
+	}
+
+	signer, err := signature.Verify(repository, hash, ring)
+	if err == nil {
+		msg.Debug("  🔏 %s %s signed by %s %s", dep.Name(), signer.Object, signer.Format, signer.Fingerprint)
+		return signer, nil
+	}
+
+	if rule.Policy == env.SignatureRequire {
+		return nil, fmt.Errorf("signature verification failed for %s (trust rule for %s): %w",
+			dep.Repository, rule.Scope, err)
+	}
+	warnMsg := fmt.Sprintf("Signature verification failed: %s", err)
+	if !ic.progress.IsEnabled() {
+		msg.Warn("  ⚠️ " + warnMsg)
+	}
+	ic.addWarning(fmt.Sprintf("%s: %s", dep.Name(), warnMsg))
+	return nil, nil
+}
+
 func (ic *installContext) reportStatus(depName, progressStatus, infoPrefix string) {
 	if ic.progress.IsEnabled() {
 		switch progressStatus {
@@ -524,18 +707,20 @@ func (ic *installContext) checkoutAndUpdate(
 	if !ic.progress.IsEnabled() {
 		msg.Debug("  🔍 Checking out %s to %s", dep.Name(), referenceName.Short())
 	}
+	stop := ic.timings.Start(timing.StepCheckout, dep.Name(), "")
 	err := git.Checkout(ic.config, dep, referenceName)
-
-	ic.lockSvc.AddDependency(ic.rootLocked, dep, referenceName.Short(), ic.modulesDir)
-
+	stop()
 	if err != nil {
+		ic.addManifest(dep, referenceName)
 		return err
 	}
 
 	if !ic.progress.IsEnabled() {
 		msg.Debug("  📥 Pulling latest changes for %s", dep.Name())
 	}
+	stop = ic.timings.Start(timing.StepFetch, dep.Name(), "")
 	err = git.Pull(ic.config, dep)
+	stop()
 
 	if err != nil && !errors.Is(err, goGit.NoErrAlreadyUpToDate) {
 		warnMsg := fmt.Sprintf("Error on pull from dependency %s\n%s", dep.Repository, err)
@@ -553,9 +738,33 @@ func (ic *installContext) checkoutAndUpdate(
 		}
 	}
 
+	// The file manifest is taken once pulling and normalizing are done, so it
+	// matches what is on disk.
+	stop = ic.timings.Start(timing.StepHash, dep.Name(), "")
+	ic.addManifest(dep, referenceName)
+	stop()
+
+	if commit, err := git.HeadCommit(dep); err == nil {
+		ic.rootLocked.SetCommit(dep, commit)
+	} else {
+		msg.Debug("Failed to resolve the commit of %s: %v", dep.Name(), err)
+	}
+
 	return nil
 }
 
+// addManifest records the dependency in the lock with the file manifest of
+// its module, warning when the module cannot be hashed.
+func (ic *installContext) addManifest(dep domain.Dependency, referenceName plumbing.ReferenceName) {
+	if err := ic.lockSvc.AddDependency(ic.rootLocked, dep, referenceName.Short(), ic.modulesDir); err != nil {
+		warnMsg := fmt.Sprintf("No file manifest recorded, 'boss verify' cannot check it: %s", err)
+		if !ic.progress.IsEnabled() {
+			msg.Warn("  ⚠️ " + warnMsg)
+		}
+		ic.addWarning(fmt.Sprintf("%s: %s", dep.Name(), warnMsg))
+	}
+}
+
 func (ic *installContext) getVersion(
 	dep domain.Dependency,
 	repository *goGit.Repository,
@@ -630,6 +839,26 @@ func (ic *installContext) getVersionSemantic(
 	return bestReference
 }
 
+// targetPlatforms returns the platforms the dependencies are built for: those
+// of engines.platforms with --matrix, then those of --platform, separated by
+// commas, then toolchain.platform.
+func (ic *installContext) targetPlatforms() []string {
+	if ic.options.Matrix && ic.root.Engines != nil {
+		return ic.root.Engines.Platforms
+	}
+	platforms := ic.options.Platform
+	if platforms == "" && ic.root.Toolchain != nil {
+		platforms = ic.root.Toolchain.Platform
+	}
+	var targets []string
+	for platform := range strings.SplitSeq(platforms, ",") {
+		if platform = strings.TrimSpace(platform); platform != "" {
+			targets = append(targets, platform)
+		}
+	}
+	return targets
+}
+
 func (ic *installContext) verifyDependencyCompatibility(dep domain.Dependency) (string, error) {
 	depPath := filepath.Join(ic.modulesDir, dep.Name())
 	depPkg, err := pkgmanager.LoadPackageOther(filepath.Join(depPath, "boss.json"))
@@ -641,30 +870,24 @@ func (ic *installContext) verifyDependencyCompatibility(dep domain.Dependency) (
 		return "", nil
 	}
 
-	targetPlatform := ic.options.Platform
-	if targetPlatform == "" && ic.root.Toolchain != nil {
-		targetPlatform = ic.root.Toolchain.Platform
+	targetPlatform := ""
+	for _, platform := range ic.targetPlatforms() {
+		if !slices.ContainsFunc(depPkg.Engines.Platforms, func(p string) bool {
+			return strings.EqualFold(p, platform)
+		}) {
+			targetPlatform = platform
+			break
+		}
 	}
 
 	if targetPlatform == "" {
 		return "", nil
 	}
 
-	for _, p := range depPkg.Engines.Platforms {
-		if strings.EqualFold(p, targetPlatform) {
-			return "", nil
-		}
-	}
-
 	//nolint:lll // Error message readability
 	errorMessage := fmt.Sprintf("Dependency '%s' does not support platform '%s'. Supported: %v", dep.Name(), targetPlatform, depPkg.Engines.Platforms)
 
-	isStrict := ic.options.Strict
-	if !isStrict && ic.root.Toolchain != nil {
-		isStrict = ic.root.Toolchain.Strict
-	}
-
-	if isStrict {
+	if ic.strict() {
 		return "", errors.New(errorMessage)
 	}
 	return errorMessage, nil
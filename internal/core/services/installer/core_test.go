@@ -2,9 +2,16 @@
 package installer
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/hashload/boss/internal/adapters/secondary/filesystem"
+	"github.com/hashload/boss/internal/adapters/secondary/repository"
 	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/packages"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/pkgmanager"
 )
 
 func TestCollectAllDependencies(t *testing.T) {
@@ -69,6 +76,45 @@ func TestAddWarning(t *testing.T) {
 	}
 }
 
+// TestCheckCollisions_Strict tests that a collision between compiled units
+// only, found once the dependencies are built, fails a strict install.
+func TestCheckCollisions_Strict(t *testing.T) {
+	t.Chdir(t.TempDir())
+	fs := filesystem.NewOSFileSystem()
+	pkgmanager.SetInstance(packages.NewPackageService(
+		repository.NewFilePackageRepository(fs),
+		repository.NewFileLockRepository(fs),
+	))
+	for module, unit := range map[string]string{"alpha": "Alpha", "beta": "Beta"} {
+		dir := filepath.Join(env.GetModulesDir(), module)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, unit+".pas"), []byte("unit "+unit+";"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	shared := domain.DependencyArtifacts{Dcu: []string{"Shared.dcu"}}
+	pkg := &domain.Package{Lock: domain.PackageLock{Installed: map[string]domain.LockedDependency{
+		"github.com/test/alpha": {Name: "alpha", Artifacts: shared},
+		"github.com/test/beta":  {Name: "beta", Artifacts: shared},
+	}}}
+
+	for _, strict := range []bool{false, true} {
+		ctx := &installContext{root: pkg, options: InstallOptions{Strict: strict}, collisions: map[string]bool{}}
+		if err := ctx.checkSourceCollisions(); err != nil {
+			t.Fatalf("checkSourceCollisions() strict=%v error = %v", strict, err)
+		}
+		err := ctx.checkCollisions()
+		if strict && err == nil {
+			t.Error("checkCollisions() strict error = nil, want the collision of Shared")
+		}
+		if !strict && (err != nil || len(ctx.warnings) != 1) {
+			t.Errorf("checkCollisions() error = %v, warnings = %v, want one warning", err, ctx.warnings)
+		}
+	}
+}
+
 func TestCollectDependenciesToInstall_WithFilter(t *testing.T) {
 	pkg := &domain.Package{
 		Dependencies: map[string]string{
@@ -20,8 +20,19 @@ type InstallOptions struct {
 	NoSave        bool
 	Compiler      string
 	Platform      string
-	Strict        bool
-	ForceUpdate   []string
+	// Matrix builds every platform of engines.platforms.
+	Matrix  bool
+	Config  string
+	Jobs    int
+	NoCache bool
+	// DiagnosticsJSON and DiagnosticsSARIF are the files the compiler
+	// diagnostics of the build are written to.
+	DiagnosticsJSON  string
+	DiagnosticsSARIF string
+	// Profile is the file a Chrome trace of the install is written to.
+	Profile     string
+	Strict      bool
+	ForceUpdate []string
 }
 
 // createLockService creates a new lock service instance.
@@ -0,0 +1,197 @@
+// Package licenses detects the licenses of installed modules and checks them
+// against the allow/deny policy declared in the project's boss.json.
+package licenses
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashload/boss/pkg/consts"
+)
+
+// NoAssertion is the SPDX value for a license file that could not be identified.
+const NoAssertion = "NOASSERTION"
+
+// SourceManifest is the Source of a license declared in the module's boss.json.
+const SourceManifest = consts.FilePackage
+
+// maxLicenseFileSize bounds how much of a license file is read; license texts
+// are a few kilobytes, the identifying phrases are near the top.
+const maxLicenseFileSize = 64 << 10
+
+// licenseFilePattern matches LICENSE, LICENCE, COPYING and COPYRIGHT files,
+// optionally with an extension or a suffix (LICENSE.md, LICENSE-MIT, COPYING.LESSER).
+var licenseFilePattern = regexp.MustCompile(`(?i)^(un)?(license|licence|copying|copyright)([.\-_][\w.\-]+)?$`)
+
+// Info is the license detected for one module.
+type Info struct {
+	// Expression is an SPDX license expression, NoAssertion when a license
+	// file exists but was not recognised, or empty when nothing was found.
+	Expression string `json:"license"`
+	// Source is boss.json when the module declares its license, the license
+	// file name when it was identified from a file, or empty.
+	Source string `json:"source,omitempty"`
+	// Files lists the license files found in the module root.
+	Files []string `json:"files,omitempty"`
+}
+
+// Known reports whether a usable SPDX expression was found.
+func (i Info) Known() bool {
+	return i.Expression != "" && i.Expression != NoAssertion
+}
+
+// Detect determines the license of the module in dir. A license declared in
+// the module's boss.json wins over one identified from a license file.
+func Detect(dir string) Info {
+	info := Info{Files: licenseFiles(dir)}
+
+	if declared := declaredLicense(filepath.Join(dir, consts.FilePackage)); declared != "" {
+		info.Expression = declared
+		info.Source = SourceManifest
+		return info
+	}
+
+	for _, name := range info.Files {
+		data, err := readHead(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if id := Identify(string(data)); id != "" {
+			info.Expression = id
+			info.Source = name
+			return info
+		}
+	}
+
+	if len(info.Files) > 0 {
+		info.Expression = NoAssertion
+		info.Source = info.Files[0]
+	}
+	return info
+}
+
+// declaredLicense reads the license field of a boss.json file.
+func declaredLicense(path string) string {
+	data, err := os.ReadFile(path) // #nosec G304 -- Reading boss.json of an installed module
+	if err != nil {
+		return ""
+	}
+	var manifest struct {
+		License string `json:"license"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(manifest.License)
+}
+
+// licenseFiles lists the license files in the root of dir, sorted so that
+// the plain LICENSE file comes before variants such as LICENSE.THIRDPARTY.
+func licenseFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && licenseFilePattern.MatchString(entry.Name()) {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if len(files[i]) != len(files[j]) {
+			return len(files[i]) < len(files[j])
+		}
+		return files[i] < files[j]
+	})
+	return files
+}
+
+func readHead(path string) ([]byte, error) {
+	file, err := os.Open(path) // #nosec G304 -- Reading a license file of an installed module
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	return io.ReadAll(io.LimitReader(file, maxLicenseFileSize))
+}
+
+// licenseSignature identifies a license by phrases that all have to appear
+// in its normalised text.
+type licenseSignature struct {
+	id      string
+	phrases []string
+}
+
+// signatures is ordered from the most to the least specific. The GPL family
+// is matched on the title line ("gnu general public license version 3"),
+// because each of those texts mentions its siblings by name further down.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var signatures = []licenseSignature{
+	{id: "AGPL-3.0-or-later", phrases: []string{"gnu affero general public license version 3"}},
+	{id: "LGPL-3.0-or-later", phrases: []string{"gnu lesser general public license version 3"}},
+	{id: "LGPL-2.1-or-later", phrases: []string{"gnu lesser general public license version 2.1"}},
+	{id: "GPL-3.0-or-later", phrases: []string{"gnu general public license version 3"}},
+	{id: "GPL-2.0-or-later", phrases: []string{"gnu general public license version 2"}},
+	{id: "Apache-2.0", phrases: []string{"apache license", "version 2.0"}},
+	{id: "MPL-2.0", phrases: []string{"mozilla public license version 2.0"}},
+	{id: "MPL-1.1", phrases: []string{"mozilla public license version 1.1"}},
+	{id: "EPL-2.0", phrases: []string{"eclipse public license - v 2.0"}},
+	{id: "BSL-1.0", phrases: []string{"boost software license"}},
+	{id: "Unlicense", phrases: []string{"free and unencumbered software released into the public domain"}},
+	{id: "Zlib", phrases: []string{"altered source versions must be plainly marked as such"}},
+	{id: "ISC", phrases: []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{id: "BSD-3-Clause", phrases: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{id: "BSD-2-Clause", phrases: []string{"redistribution and use in source and binary forms"}},
+	{id: "MIT", phrases: []string{"permission is hereby granted, free of charge"}},
+}
+
+// Identify returns the SPDX identifier of a license text, or an empty string
+// when the text matches none of the known licenses.
+func Identify(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	for _, signature := range signatures {
+		if matchesAll(normalized, signature.phrases) {
+			return signature.id
+		}
+	}
+	return ""
+}
+
+func matchesAll(text string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if !strings.Contains(text, phrase) {
+			return false
+		}
+	}
+	return true
+}
+
+// ModuleDir returns the directory of an installed module. Lock keys are
+// lowercased while the folder keeps the case of boss.json, so a
+// case-insensitive match is used when the exact folder does not exist.
+func ModuleDir(modulesDir, folder string) string {
+	exact := filepath.Join(modulesDir, folder)
+	if _, err := os.Stat(exact); err == nil {
+		return exact
+	}
+
+	entries, err := os.ReadDir(modulesDir)
+	if err != nil {
+		return exact
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.EqualFold(entry.Name(), folder) {
+			return filepath.Join(modulesDir, entry.Name())
+		}
+	}
+	return exact
+}
@@ -0,0 +1,155 @@
+//nolint:testpackage // Testing internal implementation details
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+const mitText = `MIT License
+
+Copyright (c) 2024 HashLoad
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	tests := map[string]string{
+		mitText: "MIT",
+		"                 Apache License\n           Version 2.0, January 2004": "Apache-2.0",
+		"GNU LESSER GENERAL PUBLIC LICENSE\n Version 3, 29 June 2007":           "LGPL-3.0-or-later",
+		"GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007\n" +
+			"use the GNU Lesser General Public License instead": "GPL-3.0-or-later",
+		"Mozilla Public License Version 2.0\n==================================": "MPL-2.0",
+		"Redistribution and use in source and binary forms, with or without\n" +
+			"Neither the name of the copyright holder": "BSD-3-Clause",
+		"All rights reserved. Do not copy.": "",
+	}
+	for text, want := range tests {
+		if got := Identify(text); got != want {
+			t.Errorf("Identify(%.40q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestDetect_PrefersDeclaredLicense(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "LICENSE"), mitText)
+	writeFile(t, filepath.Join(dir, "boss.json"), `{"name": "horse", "license": "MIT OR Apache-2.0"}`)
+
+	info := Detect(dir)
+	if info.Expression != "MIT OR Apache-2.0" || info.Source != SourceManifest {
+		t.Errorf("Detect() = %+v, want the boss.json expression", info)
+	}
+	if len(info.Files) != 1 || info.Files[0] != "LICENSE" {
+		t.Errorf("Files = %v", info.Files)
+	}
+}
+
+func TestDetect_FromLicenseFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "LICENSE.md"), mitText)
+	writeFile(t, filepath.Join(dir, "README.md"), "GNU GENERAL PUBLIC LICENSE Version 3")
+
+	info := Detect(dir)
+	if info.Expression != "MIT" || info.Source != "LICENSE.md" {
+		t.Errorf("Detect() = %+v", info)
+	}
+}
+
+func TestDetect_UnrecognisedAndMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "COPYING"), "Proprietary. All rights reserved.")
+
+	if info := Detect(dir); info.Expression != NoAssertion || info.Known() {
+		t.Errorf("Detect() = %+v, want NOASSERTION", info)
+	}
+	if info := Detect(t.TempDir()); info.Expression != "" || info.Known() {
+		t.Errorf("Detect() on an empty module = %+v", info)
+	}
+}
+
+func TestModuleDir_CaseInsensitive(t *testing.T) {
+	modules := t.TempDir()
+	if err := os.Mkdir(filepath.Join(modules, "github_com_HashLoad_horse"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ModuleDir(modules, "github_com_hashload_horse")
+	if filepath.Base(got) != "github_com_HashLoad_horse" {
+		t.Errorf("ModuleDir() = %s", got)
+	}
+}
+
+func TestParseExpression(t *testing.T) {
+	node, err := ParseExpression("(MIT OR Apache-2.0) AND GPL-2.0-only WITH Classpath-exception-2.0")
+	if err != nil {
+		t.Fatalf("ParseExpression() error = %v", err)
+	}
+	if node.Operator != "AND" || len(node.Operands) != 2 {
+		t.Fatalf("unexpected tree %+v", node)
+	}
+	if ids := node.IDs(); len(ids) != 3 || ids[2] != "GPL-2.0-only" {
+		t.Errorf("IDs() = %v", ids)
+	}
+	if node.Operands[1].Exception != "Classpath-exception-2.0" {
+		t.Errorf("exception = %q", node.Operands[1].Exception)
+	}
+
+	for _, invalid := range []string{"MIT OR", "(MIT", "MIT AND AND BSD", "MIT WITH", "MIT/BSD"} {
+		if _, parseErr := ParseExpression(invalid); parseErr == nil {
+			t.Errorf("ParseExpression(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	policy := &domain.PackageLicensePolicy{
+		Allow: []string{"MIT", "Apache-2.0", "BSD-3-Clause"},
+		Deny:  []string{"GPL-3.0-or-later"},
+	}
+	tests := map[string]Status{
+		"MIT":                               StatusAllowed,
+		"mit":                               StatusAllowed,
+		"GPL-3.0-or-later OR MIT":           StatusAllowed,
+		"GPL-3.0-or-later AND MIT":          StatusDenied,
+		"GPL-3.0-or-later":                  StatusDenied,
+		"MPL-2.0":                           StatusNotAllowed,
+		"MIT AND MPL-2.0":                   StatusNotAllowed,
+		"(MIT OR MPL-2.0) AND BSD-3-Clause": StatusAllowed,
+		"":                                  StatusUnknown,
+		NoAssertion:                         StatusUnknown,
+		"MIT OR":                            StatusInvalid,
+	}
+	for expression, want := range tests {
+		if got := Check(expression, policy); got != want {
+			t.Errorf("Check(%q) = %s, want %s", expression, got, want)
+		}
+	}
+}
+
+func TestStatus_Violation(t *testing.T) {
+	denyOnly := &domain.PackageLicensePolicy{Deny: []string{"GPL-3.0-or-later"}}
+	if StatusUnknown.Violation(denyOnly) || StatusUnknown.Violation(nil) {
+		t.Error("unknown license must not break a deny-only policy")
+	}
+	if !StatusUnknown.Violation(&domain.PackageLicensePolicy{Allow: []string{"MIT"}}) {
+		t.Error("unknown license must break a policy with an allow list")
+	}
+	if Check("GPL-3.0-or-later", nil) != StatusAllowed {
+		t.Error("without a policy every valid license is allowed")
+	}
+}
@@ -0,0 +1,238 @@
+package licenses
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+// Status is the outcome of checking a license against the policy.
+type Status string
+
+// Policy outcomes.
+const (
+	// StatusAllowed means the license satisfies the policy.
+	StatusAllowed Status = "allowed"
+	// StatusDenied means every choice of the expression uses a denied license.
+	StatusDenied Status = "denied"
+	// StatusNotAllowed means an allow list exists and the license is not on it.
+	StatusNotAllowed Status = "not-allowed"
+	// StatusUnknown means no license could be determined.
+	StatusUnknown Status = "unknown"
+	// StatusInvalid means the declared expression is not valid SPDX.
+	StatusInvalid Status = "invalid"
+)
+
+// Violation reports whether the status breaks the policy. An unknown license
+// only breaks a policy that has an allow list: with a deny list alone there is
+// nothing to say it is denied.
+func (s Status) Violation(policy *domain.PackageLicensePolicy) bool {
+	switch s {
+	case StatusAllowed:
+		return false
+	case StatusUnknown:
+		return policy != nil && len(policy.Allow) > 0
+	case StatusDenied, StatusNotAllowed, StatusInvalid:
+		return true
+	}
+	return false
+}
+
+// Check evaluates an SPDX expression against the policy. For "A OR B" one
+// acceptable choice is enough; for "A AND B" both have to be acceptable.
+func Check(expression string, policy *domain.PackageLicensePolicy) Status {
+	if expression == "" || expression == NoAssertion {
+		return StatusUnknown
+	}
+
+	node, err := ParseExpression(expression)
+	if err != nil {
+		return StatusInvalid
+	}
+	if policy == nil || (len(policy.Allow) == 0 && len(policy.Deny) == 0) {
+		return StatusAllowed
+	}
+
+	if node.satisfies(func(id string) bool { return !listed(policy.Deny, id) }) {
+		if len(policy.Allow) == 0 || node.satisfies(func(id string) bool {
+			return listed(policy.Allow, id) && !listed(policy.Deny, id)
+		}) {
+			return StatusAllowed
+		}
+		return StatusNotAllowed
+	}
+	return StatusDenied
+}
+
+// listed matches license identifiers case-insensitively, as SPDX requires.
+func listed(list []string, id string) bool {
+	for _, entry := range list {
+		if strings.EqualFold(strings.TrimSpace(entry), id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expression is a parsed SPDX license expression.
+type Expression struct {
+	// License is set on leaves; Exception holds a "WITH" exception.
+	License   string
+	Exception string
+	// Operator is "AND" or "OR" on inner nodes.
+	Operator string
+	Operands []*Expression
+}
+
+// IDs returns the license identifiers used in the expression.
+func (e *Expression) IDs() []string {
+	if e.License != "" {
+		return []string{e.License}
+	}
+	var ids []string
+	for _, operand := range e.Operands {
+		ids = append(ids, operand.IDs()...)
+	}
+	return ids
+}
+
+func (e *Expression) satisfies(acceptable func(string) bool) bool {
+	if e.License != "" {
+		return acceptable(e.License)
+	}
+	for _, operand := range e.Operands {
+		ok := operand.satisfies(acceptable)
+		if e.Operator == "OR" && ok {
+			return true
+		}
+		if e.Operator == "AND" && !ok {
+			return false
+		}
+	}
+	return e.Operator == "AND"
+}
+
+// ParseExpression parses an SPDX license expression such as
+// "(MIT OR Apache-2.0) AND GPL-2.0-only WITH Classpath-exception-2.0".
+func ParseExpression(expression string) (*Expression, error) {
+	p := &expressionParser{tokens: tokenize(expression)}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in license expression", p.tokens[p.pos])
+	}
+	return node, nil
+}
+
+func tokenize(expression string) []string {
+	spaced := strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+	return strings.Fields(spaced)
+}
+
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *expressionParser) parseOr() (*Expression, error) {
+	return p.parseBinary("OR", p.parseAnd)
+}
+
+func (p *expressionParser) parseAnd() (*Expression, error) {
+	return p.parseBinary("AND", p.parseWith)
+}
+
+func (p *expressionParser) parseBinary(operator string, operand func() (*Expression, error)) (*Expression, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	node := &Expression{Operator: operator, Operands: []*Expression{first}}
+	for strings.EqualFold(p.peek(), operator) {
+		p.pos++
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		node.Operands = append(node.Operands, next)
+	}
+	if len(node.Operands) == 1 {
+		return first, nil
+	}
+	return node, nil
+}
+
+func (p *expressionParser) parseWith() (*Expression, error) {
+	node, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(p.peek(), "WITH") {
+		return node, nil
+	}
+	if node.License == "" {
+		return nil, errors.New("WITH must follow a license identifier")
+	}
+	p.pos++
+	exception := p.peek()
+	if !isIdentifier(exception) {
+		return nil, errors.New("missing exception after WITH")
+	}
+	p.pos++
+	node.Exception = exception
+	return node, nil
+}
+
+func (p *expressionParser) parseAtom() (*Expression, error) {
+	token := p.peek()
+	switch {
+	case token == "(":
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("missing closing parenthesis in license expression")
+		}
+		p.pos++
+		return node, nil
+	case isIdentifier(token):
+		p.pos++
+		return &Expression{License: token}, nil
+	case token == "":
+		return nil, errors.New("incomplete license expression")
+	}
+	return nil, fmt.Errorf("unexpected %q in license expression", token)
+}
+
+// isIdentifier accepts SPDX identifiers and LicenseRef-/DocumentRef- references:
+// letters, digits, "-", "." and an optional trailing "+".
+func isIdentifier(token string) bool {
+	if token == "" || token == "(" || token == ")" {
+		return false
+	}
+	switch strings.ToUpper(token) {
+	case "AND", "OR", "WITH":
+		return false
+	}
+	for i, r := range token {
+		valid := r == '-' || r == '.' || r == ':' || (r == '+' && i == len(token)-1) ||
+			(r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !valid {
+			return false
+		}
+	}
+	return true
+}
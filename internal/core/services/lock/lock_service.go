@@ -3,12 +3,17 @@
 package lock
 
 import (
+	"fmt"
 	"path/filepath"
+	"sort"
+	"sync"
 
 	"github.com/hashload/boss/internal/core/domain"
 	"github.com/hashload/boss/internal/core/ports"
 	"github.com/hashload/boss/internal/infra"
 	"github.com/hashload/boss/pkg/consts"
+	"github.com/hashload/boss/pkg/env"
+	"github.com/hashload/boss/pkg/msg"
 	"github.com/hashload/boss/utils"
 )
 
@@ -19,6 +24,9 @@ import (
 type LockService struct {
 	repo ports.LockRepository
 	fs   infra.FileSystem
+
+	indexOnce sync.Once
+	index     *utils.HashIndex
 }
 
 // NewLockService creates a new lock service.
@@ -29,10 +37,34 @@ func NewLockService(repo ports.LockRepository, fs infra.FileSystem) *LockService
 	}
 }
 
-// Save persists the lock file.
+// Save persists the lock file, and the hash index when modules were hashed.
 func (s *LockService) Save(lock *domain.PackageLock, packageDir string) error {
 	lockPath := filepath.Join(packageDir, consts.FilePackageLock)
-	return s.repo.Save(lock, lockPath)
+	if err := s.repo.Save(lock, lockPath); err != nil {
+		return err
+	}
+	s.saveHashIndex()
+	return nil
+}
+
+// hashFiles returns the file manifest of a module directory. Files unchanged
+// since a previous run are taken from the hash index in the Boss cache.
+func (s *LockService) hashFiles(dir string) (domain.FileManifest, error) {
+	s.indexOnce.Do(func() {
+		s.index = utils.LoadHashIndex(filepath.Join(env.GetCacheDir(), consts.FileHashIndex))
+	})
+	return s.index.HashFiles(dir)
+}
+
+// saveHashIndex persists the hash index. It is only a cache, so a failure
+// costs a full rehash next time and is not reported as an error.
+func (s *LockService) saveHashIndex() {
+	if s.index == nil {
+		return
+	}
+	if err := s.index.Save(); err != nil {
+		msg.Debug("Failed to save the hash index: %v", err)
+	}
 }
 
 // NeedUpdate checks if a dependency needs to be updated.
@@ -49,9 +81,11 @@ func (s *LockService) NeedUpdate(lock *domain.PackageLock, dep domain.Dependency
 		return true
 	}
 
-	// Check if hash changed (files were modified)
-	currentHash := utils.HashDir(depDir)
-	if locked.Hash != currentHash {
+	// Check if hash changed (files were modified). Locks written before the
+	// file manifest carry an MD5 hash that never matches, so such modules are
+	// refreshed once.
+	current, err := s.hashFiles(depDir)
+	if err != nil || locked.Hash != current.Digest() {
 		return true
 	}
 
@@ -68,10 +102,20 @@ func (s *LockService) NeedUpdate(lock *domain.PackageLock, dep domain.Dependency
 	return false
 }
 
-// AddDependency adds a dependency to the lock with computed hash.
-func (s *LockService) AddDependency(lock *domain.PackageLock, dep domain.Dependency, version, modulesDir string) {
+// AddDependency adds a dependency to the lock with the file manifest of its
+// module directory. When the module cannot be hashed, the dependency is still
+// added but without a manifest, which 'boss verify' reports, and the error is
+// returned.
+func (s *LockService) AddDependency(lock *domain.PackageLock, dep domain.Dependency, version, modulesDir string) error {
 	depDir := filepath.Join(modulesDir, dep.Name())
-	hash := utils.HashDir(depDir)
+	manifest, hashErr := s.hashFiles(depDir)
+	hash := ""
+	if hashErr != nil {
+		manifest = nil
+		hashErr = fmt.Errorf("failed to hash %s: %w", depDir, hashErr)
+	} else {
+		hash = manifest.Digest()
+	}
 
 	key := dep.GetKey()
 	if existing, ok := lock.Installed[key]; !ok {
@@ -79,6 +123,7 @@ func (s *LockService) AddDependency(lock *domain.PackageLock, dep domain.Depende
 			Name:    dep.Name(),
 			Version: version,
 			Hash:    hash,
+			Files:   manifest,
 			Changed: true,
 			Artifacts: domain.DependencyArtifacts{
 				Bin: []string{},
@@ -90,24 +135,42 @@ func (s *LockService) AddDependency(lock *domain.PackageLock, dep domain.Depende
 	} else {
 		existing.Version = version
 		existing.Hash = hash
+		existing.Files = manifest
 		lock.Installed[key] = existing
 	}
+	return hashErr
 }
 
-// checkArtifacts verifies that all artifacts exist on disk.
+// checkArtifacts verifies that all artifacts exist on disk, those of
+// single-platform builds and those of every platform of multi-platform ones.
 func (s *LockService) checkArtifacts(locked domain.LockedDependency, modulesDir string) bool {
+	if !s.checkPlatformArtifacts(locked.ArtifactsFor(""), modulesDir, "") {
+		return false
+	}
+	for platform, artifacts := range locked.Platforms {
+		if !s.checkPlatformArtifacts(artifacts, modulesDir, platform) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *LockService) checkPlatformArtifacts(
+	built domain.PlatformArtifacts,
+	modulesDir, platform string,
+) bool {
 	checks := []struct {
 		folder    string
 		artifacts []string
 	}{
-		{consts.BplFolder, locked.Artifacts.Bpl},
-		{consts.BinFolder, locked.Artifacts.Bin},
-		{consts.DcpFolder, locked.Artifacts.Dcp},
-		{consts.DcuFolder, locked.Artifacts.Dcu},
+		{consts.BplFolder, built.Artifacts.Bpl},
+		{consts.BinFolder, built.Artifacts.Bin},
+		{consts.DcpFolder, built.Artifacts.Dcp},
+		{consts.DcuFolder, built.Artifacts.Dcu},
 	}
 
 	for _, check := range checks {
-		dir := filepath.Join(modulesDir, check.folder)
+		dir := domain.PlatformArtifactDir(modulesDir, check.folder, platform, built.Config)
 		for _, artifact := range check.artifacts {
 			artifactPath := filepath.Join(dir, artifact)
 			if !s.fs.Exists(artifactPath) {
@@ -118,3 +181,54 @@ func (s *LockService) checkArtifacts(locked domain.LockedDependency, modulesDir
 
 	return true
 }
+
+// ModuleReport is the outcome of checking one installed module against the
+// manifest recorded in the lock.
+type ModuleReport struct {
+	Repository string `json:"repository"`
+	Module     string `json:"module"`
+	// Missing is set when the module directory does not exist.
+	Missing bool `json:"missing,omitempty"`
+	// NoManifest is set for lock entries written before file manifests.
+	NoManifest bool `json:"noManifest,omitempty"`
+	domain.ManifestChanges
+}
+
+// Clean reports whether the module matches the lock.
+func (r ModuleReport) Clean() bool {
+	return !r.Missing && !r.NoManifest && r.Empty()
+}
+
+// Verify compares every installed module with the file manifest recorded in
+// the lock. Reports are sorted by repository. The hash index is saved, as
+// Verify does not write the lock.
+func (s *LockService) Verify(lock *domain.PackageLock, modulesDir string) ([]ModuleReport, error) {
+	repositories := make([]string, 0, len(lock.Installed))
+	for repository := range lock.Installed {
+		repositories = append(repositories, repository)
+	}
+	sort.Strings(repositories)
+
+	reports := make([]ModuleReport, 0, len(repositories))
+	for _, repository := range repositories {
+		locked := lock.Installed[repository]
+		report := ModuleReport{Repository: repository, Module: locked.Name}
+		depDir := filepath.Join(modulesDir, locked.Name)
+
+		switch {
+		case !s.fs.Exists(depDir):
+			report.Missing = true
+		case locked.Files == nil:
+			report.NoManifest = true
+		default:
+			current, err := s.hashFiles(depDir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash %s: %w", depDir, err)
+			}
+			report.ManifestChanges = locked.Files.Compare(current)
+		}
+		reports = append(reports, report)
+	}
+	s.saveHashIndex()
+	return reports, nil
+}
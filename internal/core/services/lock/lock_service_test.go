@@ -5,6 +5,8 @@ import (
 	"errors"
 	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/hashload/boss/internal/core/domain"
@@ -170,10 +172,17 @@ func TestLockService_AddDependency_CreatesNewEntry(t *testing.T) {
 
 	dep := domain.ParseDependency("github.com/test/repo", "1.0.0")
 
-	service.AddDependency(lock, dep, "1.0.0", "/modules")
+	err := service.AddDependency(lock, dep, "1.0.0", "/modules")
+	if err == nil {
+		t.Error("expected an error for a module that cannot be hashed")
+	}
 
-	if _, ok := lock.Installed["github.com/test/repo"]; !ok {
-		t.Error("expected dependency to be added to lock")
+	installed, ok := lock.Installed["github.com/test/repo"]
+	if !ok {
+		t.Fatal("expected dependency to be added to lock")
+	}
+	if installed.Hash != "" || installed.Files != nil {
+		t.Errorf("expected no manifest for a module that cannot be hashed, got %q %v", installed.Hash, installed.Files)
 	}
 }
 
@@ -194,12 +203,15 @@ func TestLockService_AddDependency_UpdatesExistingEntry(t *testing.T) {
 
 	dep := domain.ParseDependency("github.com/test/repo", "2.0.0")
 
-	service.AddDependency(lock, dep, "2.0.0", "/modules")
+	_ = service.AddDependency(lock, dep, "2.0.0", "/modules")
 
 	installed := lock.Installed["github.com/test/repo"]
 	if installed.Version != "2.0.0" {
 		t.Errorf("expected version 2.0.0, got %s", installed.Version)
 	}
+	if installed.Hash != "" {
+		t.Errorf("expected the old hash to be cleared, got %s", installed.Hash)
+	}
 }
 
 func TestLockService_Save(t *testing.T) {
@@ -223,3 +235,51 @@ func TestLockService_Save(t *testing.T) {
 		t.Error("expected lock to be saved in repository")
 	}
 }
+
+func TestLockService_Verify(t *testing.T) {
+	t.Setenv("BOSS_HOME", t.TempDir())
+	modulesDir := t.TempDir()
+	moduleDir := filepath.Join(modulesDir, "github_com_test_repo")
+	if err := os.MkdirAll(filepath.Join(moduleDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"boss.json": "{}", "Horse.pas": "unit Horse;", ".git/HEAD": "x"} {
+		if err := os.WriteFile(filepath.Join(moduleDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := NewMockFileSystem()
+	fs.AddDir(moduleDir)
+	service := NewLockService(NewMockLockRepository(), fs)
+	lock := &domain.PackageLock{Installed: map[string]domain.LockedDependency{}}
+	if err := service.AddDependency(lock, domain.ParseDependency("github.com/test/repo", "1.0.0"), "1.0.0",
+		modulesDir); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+	lock.Installed["github.com/test/missing"] = domain.LockedDependency{Name: "missing"}
+
+	reports, err := service.Verify(lock, modulesDir)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(reports) != 2 || !reports[0].Missing || !reports[1].Clean() {
+		t.Fatalf("Verify() of a fresh install = %+v", reports)
+	}
+
+	_ = os.WriteFile(filepath.Join(moduleDir, "Horse.pas"), []byte("unit Horse; // patched"), 0600)
+	_ = os.Remove(filepath.Join(moduleDir, "boss.json"))
+	_ = os.WriteFile(filepath.Join(moduleDir, "Extra.pas"), []byte("unit Extra;"), 0600)
+	_ = os.WriteFile(filepath.Join(moduleDir, ".git", "HEAD"), []byte("y"), 0600)
+
+	reports, _ = service.Verify(lock, modulesDir)
+	changes := reports[1].ManifestChanges
+	if !reflect.DeepEqual(changes, domain.ManifestChanges{
+		Modified: []string{"Horse.pas"}, Added: []string{"Extra.pas"}, Deleted: []string{"boss.json"},
+	}) {
+		t.Errorf("Verify() changes = %+v", changes)
+	}
+	if !service.NeedUpdate(lock, domain.ParseDependency("github.com/test/repo", "1.0.0"), "1.0.0", modulesDir) {
+		t.Error("NeedUpdate() should report a module edited by hand")
+	}
+}
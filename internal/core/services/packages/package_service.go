@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashload/boss/internal/core/domain"
 	"github.com/hashload/boss/internal/core/ports"
+	"github.com/hashload/boss/pkg/consts"
 	"github.com/hashload/boss/pkg/env"
 )
 
@@ -94,5 +95,5 @@ func (s *PackageService) loadOrCreateLock(packagePath string) domain.PackageLock
 // getLockPath returns the lock file path for a given package path.
 func (s *PackageService) getLockPath(packagePath string) string {
 	dir := filepath.Dir(packagePath)
-	return filepath.Join(dir, "boss.lock")
+	return filepath.Join(dir, consts.FilePackageLock)
 }
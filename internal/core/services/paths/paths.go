@@ -46,6 +46,9 @@ func EnsureCleanModulesDir(dependencies []domain.Dependency, lock domain.Package
 			cleanArtifacts(filepath.Join(cacheDir, info.Name()), lock)
 			continue
 		}
+		if info.Name() == consts.FolderBuildLogs {
+			continue
+		}
 
 		if cleanAll && !utils.Contains(dependenciesNames, info.Name()) {
 		remove:
@@ -95,6 +98,8 @@ func cleanArtifacts(dir string, lock domain.PackageLock) {
 	artifactList := lock.GetArtifactList()
 	for _, infoArtifact := range fileInfos {
 		if infoArtifact.IsDir() {
+			// The artifacts of a non-default build configuration.
+			cleanArtifacts(filepath.Join(dir, infoArtifact.Name()), lock)
 			continue
 		}
 		if !utils.Contains(artifactList, infoArtifact.Name()) {
@@ -109,6 +109,11 @@ func TestEnsureCleanModulesDir_RemovesOldDependencies(t *testing.T) {
 	if err := os.MkdirAll(currentDepDir, 0755); err != nil {
 		t.Fatalf("Failed to create current dependency dir: %v", err)
 	}
+	// Create the build logs directory that should be kept
+	logsDir := filepath.Join(modulesDir, consts.FolderBuildLogs)
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatalf("Failed to create build logs dir: %v", err)
+	}
 	lock := domain.PackageLock{
 		Installed: map[string]domain.LockedDependency{},
 	}
@@ -125,6 +130,11 @@ func TestEnsureCleanModulesDir_RemovesOldDependencies(t *testing.T) {
 	if _, err := os.Stat(currentDepDir); os.IsNotExist(err) {
 		t.Error("EnsureCleanModulesDir() should keep current dependency directories")
 	}
+
+	// Verify build logs were kept
+	if _, err := os.Stat(logsDir); os.IsNotExist(err) {
+		t.Error("EnsureCleanModulesDir() should keep the build logs")
+	}
 }
 
 func TestEnsureCleanModulesDir_KeepOldDependenciesOnSelective(t *testing.T) {
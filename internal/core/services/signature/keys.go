@@ -0,0 +1,125 @@
+package signature
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/mitchellh/go-homedir"
+	"golang.org/x/crypto/ssh"
+)
+
+const pgpPublicKeyHeader = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+
+// KeyRing holds the keys trusted by a rule.
+type KeyRing struct {
+	openPGP openpgp.EntityList
+	ssh     []sshKey
+}
+
+type sshKey struct {
+	key ssh.PublicKey
+	// identity is the key comment, or the principal of an allowed_signers line.
+	identity string
+}
+
+// Len returns the number of trusted keys.
+func (k *KeyRing) Len() int {
+	return len(k.openPGP) + len(k.ssh)
+}
+
+// LoadKeyRing parses the keys of a rule. Each entry is an armored OpenPGP
+// public key, an SSH public key line, or the path to a file holding either;
+// relative paths are resolved against dir.
+func LoadKeyRing(keys []string, dir string) (*KeyRing, error) {
+	ring := &KeyRing{}
+	for _, entry := range keys {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		origin := "inline key"
+		if !strings.Contains(entry, pgpPublicKeyHeader) && !isSSHKeyLine(entry) {
+			content, err := readKeyFile(entry, dir)
+			if err != nil {
+				return nil, err
+			}
+			origin, entry = entry, content
+		}
+		if err := ring.add(entry); err != nil {
+			return nil, fmt.Errorf("%s: %w", origin, err)
+		}
+	}
+	return ring, nil
+}
+
+func readKeyFile(path, dir string) (string, error) {
+	expanded, err := homedir.Expand(path)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(expanded) {
+		expanded = filepath.Join(dir, expanded)
+	}
+	data, err := os.ReadFile(expanded) // #nosec G304 -- Reading a key file named in the trust configuration
+	if err != nil {
+		return "", fmt.Errorf("failed to read trusted key: %w", err)
+	}
+	return string(data), nil
+}
+
+func (k *KeyRing) add(content string) error {
+	if strings.Contains(content, pgpPublicKeyHeader) {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(content))
+		if err != nil {
+			return fmt.Errorf("invalid OpenPGP public key: %w", err)
+		}
+		k.openPGP = append(k.openPGP, entities...)
+		return nil
+	}
+
+	found := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := parseSSHKeyLine(line)
+		if err != nil {
+			return err
+		}
+		k.ssh = append(k.ssh, key)
+		found = true
+	}
+	if !found {
+		return errors.New("no OpenPGP or SSH public key found")
+	}
+	return nil
+}
+
+// isSSHKeyLine reports whether an entry is an SSH public key rather than a path.
+func isSSHKeyLine(entry string) bool {
+	_, err := parseSSHKeyLine(entry)
+	return err == nil
+}
+
+// parseSSHKeyLine accepts authorized_keys lines ("ssh-ed25519 AAAA... comment")
+// and allowed_signers lines ("dev@example.com [options] ssh-ed25519 AAAA...").
+func parseSSHKeyLine(line string) (sshKey, error) {
+	fields := strings.Fields(line)
+	if len(fields) >= 2 {
+		if key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(fields[0] + " " + fields[1])); err == nil {
+			return sshKey{key: key, identity: strings.Join(fields[2:], " ")}, nil
+		}
+		// The principals and options of an allowed_signers line precede the key.
+		if key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(strings.Join(fields[1:], " "))); err == nil {
+			return sshKey{key: key, identity: fields[0]}, nil
+		}
+	}
+	return sshKey{}, fmt.Errorf("invalid SSH public key %q", line)
+}
@@ -0,0 +1,78 @@
+// Package signature verifies that the tag or commit a dependency resolves to
+// is signed by a trusted OpenPGP or SSH key, as configured in the "trust"
+// section of boss.json or of the global configuration.
+package signature
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/pkg/env"
+)
+
+// Source is a trust configuration and the directory its key paths are
+// relative to.
+type Source struct {
+	Config *env.TrustConfig
+	Dir    string
+}
+
+// Rule is the trust rule that applies to one dependency.
+type Rule struct {
+	Policy env.SignaturePolicy
+	Keys   []string
+	// Dir resolves the key paths of the configuration that declared the rule.
+	Dir string
+	// Scope is the dependency or host the rule was declared for.
+	Scope string
+}
+
+// RuleFor returns the rule that applies to a repository. Dependency rules win
+// over host rules, and within each kind the sources are consulted in order,
+// so the project configuration should come before the global one. Without a
+// matching rule the policy is off.
+func RuleFor(repository string, sources ...Source) (Rule, error) {
+	key := domain.RepositoryKey(repository)
+	host, _, _ := strings.Cut(key, "/")
+
+	for _, byDependency := range []bool{true, false} {
+		for _, source := range sources {
+			if source.Config == nil {
+				continue
+			}
+			rules, scope := source.Config.Hosts, host
+			if byDependency {
+				rules, scope = source.Config.Dependencies, key
+			}
+			if rule := lookupRule(rules, scope); rule != nil {
+				return newRule(rule, source.Dir, scope)
+			}
+		}
+	}
+	return Rule{Policy: env.SignatureOff}, nil
+}
+
+// lookupRule finds a rule whose key normalizes to scope, so "hashload/horse"
+// and "github.com/HashLoad/horse" name the same dependency.
+func lookupRule(rules map[string]*env.TrustRule, scope string) *env.TrustRule {
+	for name, rule := range rules {
+		if rule != nil && (domain.RepositoryKey(name) == scope || strings.EqualFold(name, scope)) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func newRule(rule *env.TrustRule, dir, scope string) (Rule, error) {
+	policy := env.SignaturePolicy(strings.ToLower(string(rule.Policy)))
+	switch policy {
+	case "":
+		policy = env.SignatureRequire
+	case env.SignatureOff, env.SignatureWarn, env.SignatureRequire:
+	default:
+		return Rule{}, fmt.Errorf("trust rule for %s: unknown policy %q (use %s, %s or %s)",
+			scope, rule.Policy, env.SignatureRequire, env.SignatureWarn, env.SignatureOff)
+	}
+	return Rule{Policy: policy, Keys: rule.Keys, Dir: dir, Scope: scope}, nil
+}
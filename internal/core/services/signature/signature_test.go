@@ -0,0 +1,241 @@
+//nolint:testpackage // Testing internal implementation details
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/go-git/go-billy/v5/memfs"
+	goGit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/hashload/boss/pkg/env"
+	"golang.org/x/crypto/ssh"
+)
+
+func newPGPKey(t *testing.T) (*openpgp.Entity, string) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Release Bot", "", "release@example.com",
+		&packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	writer, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = entity.Serialize(writer); err != nil {
+		t.Fatal(err)
+	}
+	_ = writer.Close()
+	return entity, buf.String()
+}
+
+// sshTestSigner signs git objects the way "git -c gpg.format=ssh" does.
+type sshTestSigner struct {
+	signer ssh.Signer
+}
+
+func newSSHKey(t *testing.T) (sshTestSigner, string) {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))) + " dev@example.com"
+	return sshTestSigner{signer: signer}, line
+}
+
+func (s sshTestSigner) Sign(message io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(message)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha512.Sum512(data)
+	signed := append([]byte(sshSigMagic), ssh.Marshal(sshSignedData{
+		Namespace: gitNamespace, HashAlgorithm: "sha512", Hash: digest[:],
+	})...)
+	signature, err := s.signer.Sign(rand.Reader, signed)
+	if err != nil {
+		return nil, err
+	}
+	blob := append([]byte(sshSigMagic), ssh.Marshal(sshSigBlob{
+		Version: sshSigVersion, PublicKey: s.signer.PublicKey().Marshal(), Namespace: gitNamespace,
+		HashAlgorithm: "sha512", Signature: ssh.Marshal(signature),
+	})...)
+	return []byte(sshSignatureHeader + "\n" + base64.StdEncoding.EncodeToString(blob) + "\n" +
+		sshSignatureFooter + "\n"), nil
+}
+
+func newRepository(t *testing.T) (*goGit.Repository, *goGit.Worktree) {
+	t.Helper()
+	repository, err := goGit.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := worktree.Filesystem.Create("boss.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.Write([]byte(`{"name": "lib"}`))
+	_ = file.Close()
+	if _, err = worktree.Add("boss.json"); err != nil {
+		t.Fatal(err)
+	}
+	return repository, worktree
+}
+
+func testAuthor() *object.Signature {
+	return &object.Signature{Name: "Dev", Email: "dev@example.com", When: time.Unix(1700000000, 0)}
+}
+
+func commit(t *testing.T, worktree *goGit.Worktree, options goGit.CommitOptions) plumbing.Hash {
+	t.Helper()
+	options.Author = testAuthor()
+	hash, err := worktree.Commit("release", &options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestVerify_OpenPGPSignedCommit(t *testing.T) {
+	entity, armored := newPGPKey(t)
+	repository, worktree := newRepository(t)
+	hash := commit(t, worktree, goGit.CommitOptions{SignKey: entity})
+
+	ring, err := LoadKeyRing([]string{armored}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := Verify(repository, hash, ring)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if signer.Object != ObjectCommit || signer.Format != FormatOpenPGP || signer.Identity == "" ||
+		signer.Fingerprint != fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint) {
+		t.Errorf("unexpected signer %+v", signer)
+	}
+
+	_, other := newPGPKey(t)
+	untrusted, _ := LoadKeyRing([]string{other}, "")
+	if _, err = Verify(repository, hash, untrusted); !errors.Is(err, ErrUntrusted) {
+		t.Errorf("Verify() with another key error = %v, want ErrUntrusted", err)
+	}
+}
+
+func TestVerify_SSHSignedCommit(t *testing.T) {
+	sshSigner, line := newSSHKey(t)
+	repository, worktree := newRepository(t)
+	hash := commit(t, worktree, goGit.CommitOptions{Signer: sshSigner})
+
+	keyFile := filepath.Join(t.TempDir(), "allowed_signers")
+	if err := os.WriteFile(keyFile, []byte("# release keys\nrelease@example.com "+line+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ring, err := LoadKeyRing([]string{filepath.Base(keyFile)}, filepath.Dir(keyFile))
+	if err != nil || ring.Len() != 1 {
+		t.Fatalf("LoadKeyRing() = %v, %v", ring, err)
+	}
+
+	signer, err := Verify(repository, hash, ring)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if signer.Format != FormatSSH || signer.Identity != "release@example.com" ||
+		signer.Fingerprint != ssh.FingerprintSHA256(sshSigner.signer.PublicKey()) {
+		t.Errorf("unexpected signer %+v", signer)
+	}
+}
+
+func TestVerify_SignedTag(t *testing.T) {
+	entity, armored := newPGPKey(t)
+	repository, worktree := newRepository(t)
+	hash := commit(t, worktree, goGit.CommitOptions{})
+	tag, err := repository.CreateTag("v1.0.0", hash, &goGit.CreateTagOptions{
+		Tagger: testAuthor(), Message: "v1.0.0", SignKey: entity,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ring, _ := LoadKeyRing([]string{armored}, "")
+	signer, err := Verify(repository, tag.Hash(), ring)
+	if err != nil || signer.Object != ObjectTag {
+		t.Fatalf("Verify() = %+v, %v", signer, err)
+	}
+
+	if _, err = Verify(repository, hash, ring); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("Verify() of the unsigned commit error = %v, want ErrUnsigned", err)
+	}
+}
+
+func TestLoadKeyRing_Errors(t *testing.T) {
+	if _, err := LoadKeyRing([]string{"missing.pub"}, t.TempDir()); err == nil {
+		t.Error("a missing key file should fail")
+	}
+	path := filepath.Join(t.TempDir(), "bad.pub")
+	_ = os.WriteFile(path, []byte("not a key\n"), 0600)
+	if _, err := LoadKeyRing([]string{path}, ""); err == nil {
+		t.Error("a file without keys should fail")
+	}
+}
+
+func TestRuleFor(t *testing.T) {
+	project := &env.TrustConfig{
+		Hosts:        map[string]*env.TrustRule{"github.com": {Keys: []string{"project.asc"}}},
+		Dependencies: map[string]*env.TrustRule{"hashload/jhonson": {Policy: env.SignatureOff}},
+	}
+	global := &env.TrustConfig{
+		Hosts:        map[string]*env.TrustRule{"gitlab.com": {Policy: "warn", Keys: []string{"global.asc"}}},
+		Dependencies: map[string]*env.TrustRule{"github.com/hashload/horse": {Policy: "warn"}},
+	}
+	sources := []Source{{Config: project, Dir: "project"}, {Config: global, Dir: "home"}}
+
+	tests := []struct {
+		repository string
+		policy     env.SignaturePolicy
+		dir        string
+	}{
+		{"github.com/HashLoad/horse", env.SignatureWarn, "home"},
+		{"hashload/jhonson", env.SignatureOff, "project"},
+		{"hashload/dataset-serialize", env.SignatureRequire, "project"},
+		{"gitlab.com/acme/lib", env.SignatureWarn, "home"},
+		{"bitbucket.org/acme/lib", env.SignatureOff, ""},
+	}
+	for _, tt := range tests {
+		rule, err := RuleFor(tt.repository, sources...)
+		if err != nil || rule.Policy != tt.policy || rule.Dir != tt.dir {
+			t.Errorf("RuleFor(%s) = %+v, %v; want policy %s from %q", tt.repository, rule, err, tt.policy, tt.dir)
+		}
+	}
+
+	bad := &env.TrustConfig{Hosts: map[string]*env.TrustRule{"github.com": {Policy: "sometimes"}}}
+	if _, err := RuleFor("hashload/horse", Source{Config: bad}); err == nil {
+		t.Error("an unknown policy should fail")
+	}
+}
@@ -0,0 +1,125 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// The SSH signature format is described in OpenSSH's PROTOCOL.sshsig.
+const (
+	sshSignatureHeader = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureFooter = "-----END SSH SIGNATURE-----"
+	sshSigMagic        = "SSHSIG"
+	sshSigVersion      = 1
+	// gitNamespace is the namespace git signs commits and tags in.
+	gitNamespace = "git"
+)
+
+type sshSigBlob struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// isSSHSignature reports whether an armored signature is an SSH signature.
+func isSSHSignature(armored string) bool {
+	return strings.Contains(armored, sshSignatureHeader)
+}
+
+// verifySSHSignature checks an armored git SSH signature over message and
+// returns the trusted key that made it.
+func verifySSHSignature(armored string, message []byte, trusted []sshKey) (sshKey, error) {
+	blob, err := decodeSSHSignature(armored)
+	if err != nil {
+		return sshKey{}, err
+	}
+	if blob.Namespace != gitNamespace {
+		return sshKey{}, fmt.Errorf("SSH signature namespace is %q, not %q", blob.Namespace, gitNamespace)
+	}
+
+	signer, err := ssh.ParsePublicKey(blob.PublicKey)
+	if err != nil {
+		return sshKey{}, fmt.Errorf("invalid SSH signature key: %w", err)
+	}
+	var key *sshKey
+	for i := range trusted {
+		if bytes.Equal(trusted[i].key.Marshal(), signer.Marshal()) {
+			key = &trusted[i]
+			break
+		}
+	}
+	if key == nil {
+		return sshKey{}, fmt.Errorf("%w: SSH key %s", ErrUntrusted, ssh.FingerprintSHA256(signer))
+	}
+
+	var digest hash.Hash
+	switch blob.HashAlgorithm {
+	case "sha256":
+		digest = sha256.New()
+	case "sha512":
+		digest = sha512.New()
+	default:
+		return sshKey{}, fmt.Errorf("unsupported SSH signature hash %q", blob.HashAlgorithm)
+	}
+	digest.Write(message)
+
+	signature := &ssh.Signature{}
+	if err = ssh.Unmarshal(blob.Signature, signature); err != nil {
+		return sshKey{}, fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	signed := append([]byte(sshSigMagic), ssh.Marshal(sshSignedData{
+		Namespace: blob.Namespace, Reserved: blob.Reserved, HashAlgorithm: blob.HashAlgorithm, Hash: digest.Sum(nil),
+	})...)
+	if err = signer.Verify(signed, signature); err != nil {
+		return sshKey{}, fmt.Errorf("%w: %w", ErrBadSignature, err)
+	}
+	return *key, nil
+}
+
+func decodeSSHSignature(armored string) (*sshSigBlob, error) {
+	body := strings.TrimSpace(armored)
+	body, ok := strings.CutPrefix(body, sshSignatureHeader)
+	if !ok {
+		return nil, errors.New("not an SSH signature")
+	}
+	body, ok = strings.CutSuffix(strings.TrimSpace(body), sshSignatureFooter)
+	if !ok {
+		return nil, errors.New("truncated SSH signature")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH signature encoding: %w", err)
+	}
+	rest, ok := bytes.CutPrefix(raw, []byte(sshSigMagic))
+	if !ok {
+		return nil, errors.New("invalid SSH signature preamble")
+	}
+
+	blob := &sshSigBlob{}
+	if err = ssh.Unmarshal(rest, blob); err != nil {
+		return nil, fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	if blob.Version != sshSigVersion {
+		return nil, fmt.Errorf("unsupported SSH signature version %d", blob.Version)
+	}
+	return blob, nil
+}
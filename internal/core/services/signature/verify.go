@@ -0,0 +1,132 @@
+package signature
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	pgpErrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	goGit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/hashload/boss/internal/core/domain"
+	"golang.org/x/crypto/ssh"
+)
+
+const pgpSignatureHeader = "-----BEGIN PGP SIGNATURE-----"
+
+var (
+	// ErrUnsigned is returned when neither the tag nor the commit is signed.
+	ErrUnsigned = errors.New("not signed")
+	// ErrUntrusted is returned when the signing key is not in the key ring.
+	ErrUntrusted = errors.New("signed by an untrusted key")
+	// ErrBadSignature is returned when the signature does not match the object.
+	ErrBadSignature = errors.New("invalid signature")
+)
+
+// Formats of the signatures Verify accepts.
+const (
+	FormatOpenPGP = "openpgp"
+	FormatSSH     = "ssh"
+)
+
+// Signed objects, as recorded in the lock.
+const (
+	ObjectTag    = "tag"
+	ObjectCommit = "commit"
+)
+
+type signedObject interface {
+	EncodeWithoutSignature(o plumbing.EncodedObject) error
+}
+
+// Verify checks that the object at hash was signed by a key of the ring. An
+// annotated tag is accepted when the tag itself or the commit it points to
+// carries a trusted signature; any other reference needs a signed commit.
+func Verify(repository *goGit.Repository, hash plumbing.Hash, ring *KeyRing) (*domain.LockedSigner, error) {
+	var tagErr error
+	if tag, err := repository.TagObject(hash); err == nil {
+		if tag.PGPSignature != "" {
+			signer, verifyErr := verifyObject(tag, tag.PGPSignature, ring)
+			if verifyErr == nil {
+				signer.Object = ObjectTag
+				return signer, nil
+			}
+			tagErr = verifyErr
+		}
+		commit, commitErr := tag.Commit()
+		if commitErr != nil {
+			return nil, fmt.Errorf("tag %s does not point to a commit: %w", tag.Name, commitErr)
+		}
+		hash = commit.Hash
+	}
+
+	commit, err := repository.CommitObject(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	commitErr := ErrUnsigned
+	if commit.PGPSignature != "" {
+		signer, verifyErr := verifyObject(commit, commit.PGPSignature, ring)
+		if verifyErr == nil {
+			signer.Object = ObjectCommit
+			return signer, nil
+		}
+		commitErr = verifyErr
+	}
+
+	if tagErr != nil && errors.Is(commitErr, ErrUnsigned) {
+		return nil, fmt.Errorf("tag %w", tagErr)
+	}
+	return nil, fmt.Errorf("commit %s %w", hash.String()[:7], commitErr)
+}
+
+func verifyObject(object signedObject, armored string, ring *KeyRing) (*domain.LockedSigner, error) {
+	encoded := &plumbing.MemoryObject{}
+	if err := object.EncodeWithoutSignature(encoded); err != nil {
+		return nil, err
+	}
+	reader, err := encoded.Reader()
+	if err != nil {
+		return nil, err
+	}
+	message, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case isSSHSignature(armored):
+		key, verifyErr := verifySSHSignature(armored, message, ring.ssh)
+		if verifyErr != nil {
+			return nil, verifyErr
+		}
+		return &domain.LockedSigner{
+			Format: FormatSSH, Fingerprint: ssh.FingerprintSHA256(key.key), Identity: key.identity,
+		}, nil
+	case strings.Contains(armored, pgpSignatureHeader):
+		return verifyOpenPGP(armored, message, ring.openPGP)
+	default:
+		return nil, fmt.Errorf("%w: unsupported signature format", ErrBadSignature)
+	}
+}
+
+func verifyOpenPGP(armored string, message []byte, keyring openpgp.EntityList) (*domain.LockedSigner, error) {
+	entity, err := openpgp.CheckArmoredDetachedSignature(
+		keyring, bytes.NewReader(message), strings.NewReader(armored), nil)
+	if errors.Is(err, pgpErrors.ErrUnknownIssuer) {
+		return nil, fmt.Errorf("%w: OpenPGP key not in the trusted keys", ErrUntrusted)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBadSignature, err)
+	}
+
+	signer := &domain.LockedSigner{Format: FormatOpenPGP, Fingerprint: fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)}
+	if identity := entity.PrimaryIdentity(); identity != nil {
+		signer.Identity = identity.Name
+	}
+	return signer, nil
+}
@@ -0,0 +1,193 @@
+// Package timing records how long each step of an install or build takes, per
+// dependency, to summarize where the time went and to export it as a Chrome
+// trace.
+package timing
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Step is a kind of work done for a dependency.
+type Step string
+
+const (
+	// StepFetch clones or fetches the repository.
+	StepFetch Step = "fetch"
+	// StepCheckout checks out the selected version.
+	StepCheckout Step = "checkout"
+	// StepHash hashes the sources, for the lock file or the build cache.
+	StepHash Step = "hash"
+	// StepPrebuilt installs a binary package.
+	StepPrebuilt Step = "prebuilt"
+	// StepCache restores from or saves to the build cache.
+	StepCache Step = "cache"
+	// StepCompile compiles a project.
+	StepCompile Step = "compile"
+	// StepArtifacts moves the compiled artifacts to the shared folders.
+	StepArtifacts Step = "artifacts"
+)
+
+// Span is one step done for a dependency.
+type Span struct {
+	Step Step
+	// Module is the dependency, empty for the root package.
+	Module string
+	// Name details the step, such as the project compiled.
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+}
+
+// Recorder gathers the spans of an install or build. It is safe for concurrent
+// use; a nil *Recorder records nothing.
+type Recorder struct {
+	mu      sync.Mutex
+	started time.Time
+	spans   []Span
+}
+
+// NewRecorder creates a recorder; trace timestamps are relative to now.
+func NewRecorder() *Recorder {
+	return &Recorder{started: time.Now()}
+}
+
+// Start begins a span and returns the function that ends it.
+func (r *Recorder) Start(step Step, module, name string) func() {
+	if r == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		span := Span{Step: step, Module: module, Name: name, Start: start, Duration: time.Since(start)}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.spans = append(r.spans, span)
+	}
+}
+
+// Spans returns the ended spans in the order they ended.
+func (r *Recorder) Spans() []Span {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.spans)
+}
+
+// Elapsed returns the time since the recorder was created.
+func (r *Recorder) Elapsed() time.Duration {
+	if r == nil {
+		return 0
+	}
+	return time.Since(r.started)
+}
+
+// ModuleTime is the time spent on a dependency, in total and per step.
+type ModuleTime struct {
+	Module string
+	Total  time.Duration
+	Steps  map[Step]time.Duration
+}
+
+// Summary returns the time spent on each dependency, slowest first.
+func (r *Recorder) Summary() []ModuleTime {
+	index := map[string]int{}
+	var modules []ModuleTime
+	for _, span := range r.Spans() {
+		i, ok := index[span.Module]
+		if !ok {
+			i = len(modules)
+			index[span.Module] = i
+			modules = append(modules, ModuleTime{Module: span.Module, Steps: map[Step]time.Duration{}})
+		}
+		modules[i].Total += span.Duration
+		modules[i].Steps[span.Step] += span.Duration
+	}
+	slices.SortStableFunc(modules, func(a, b ModuleTime) int {
+		if order := cmp.Compare(b.Total, a.Total); order != 0 {
+			return order
+		}
+		return cmp.Compare(a.Module, b.Module)
+	})
+	return modules
+}
+
+// StepTotals returns the time spent on each step over every dependency.
+func (r *Recorder) StepTotals() map[Step]time.Duration {
+	totals := map[Step]time.Duration{}
+	for _, span := range r.Spans() {
+		totals[span.Step] += span.Duration
+	}
+	return totals
+}
+
+// Steps lists the steps in the order they happen.
+func Steps() []Step {
+	return []Step{StepFetch, StepCheckout, StepHash, StepPrebuilt, StepCache, StepCompile, StepArtifacts}
+}
+
+// traceEvent is an event of the Chrome trace event format, understood by
+// chrome://tracing, Perfetto and speedscope.
+type traceEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat,omitempty"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`
+	Duration  int64             `json:"dur,omitempty"`
+	Process   int               `json:"pid"`
+	Thread    int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// WriteTrace writes the spans as a Chrome trace, one track per dependency, so
+// the dependencies compiled in parallel are shown side by side.
+func WriteTrace(w io.Writer, r *Recorder, rootName string) error {
+	events := []traceEvent{}
+	threads := map[string]int{}
+	var started time.Time
+	if r != nil {
+		started = r.started
+	}
+	for _, span := range r.Spans() {
+		thread, ok := threads[span.Module]
+		if !ok {
+			thread = len(threads) + 1
+			threads[span.Module] = thread
+			track := span.Module
+			if track == "" {
+				track = rootName
+			}
+			events = append(events, traceEvent{Name: "thread_name", Phase: "M", Process: 1, Thread: thread,
+				Args: map[string]string{"name": track}})
+		}
+
+		name := string(span.Step)
+		if span.Name != "" {
+			name += " " + span.Name
+		}
+		events = append(events, traceEvent{
+			Name:      name,
+			Category:  string(span.Step),
+			Phase:     "X",
+			Timestamp: span.Start.Sub(started).Microseconds(),
+			Duration:  max(span.Duration.Microseconds(), 1),
+			Process:   1,
+			Thread:    thread,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(traceFile{TraceEvents: events, DisplayTimeUnit: "ms"})
+}
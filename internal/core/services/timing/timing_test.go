@@ -0,0 +1,90 @@
+package timing_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashload/boss/internal/core/services/timing"
+)
+
+func TestRecorder_Summary(t *testing.T) {
+	recorder := timing.NewRecorder()
+	stopHorse := recorder.Start(timing.StepFetch, "horse", "")
+	stopRoot := recorder.Start(timing.StepCompile, "", "App.dproj")
+	time.Sleep(5 * time.Millisecond)
+	stopRoot()
+	stopJhonson := recorder.Start(timing.StepCompile, "jhonson", "Jhonson.dproj")
+	time.Sleep(15 * time.Millisecond)
+	stopJhonson()
+	stopHorse()
+	recorder.Start(timing.StepCompile, "horse", "Horse.dproj")()
+
+	summary := recorder.Summary()
+	if len(summary) != 3 {
+		t.Fatalf("Summary() = %+v, want 3 modules", summary)
+	}
+	if summary[0].Module != "horse" || summary[2].Module != "" {
+		t.Errorf("Summary() order = %s, %s, %s; want the slowest first", summary[0].Module, summary[1].Module,
+			summary[2].Module)
+	}
+	if len(summary[0].Steps) != 2 || summary[0].Steps[timing.StepFetch] < 20*time.Millisecond {
+		t.Errorf("horse steps = %v, want fetch and compile", summary[0].Steps)
+	}
+	if totals := recorder.StepTotals(); totals[timing.StepCompile] < 20*time.Millisecond {
+		t.Errorf("StepTotals() = %v", totals)
+	}
+}
+
+func TestRecorder_NilRecordsNothing(t *testing.T) {
+	var recorder *timing.Recorder
+	recorder.Start(timing.StepHash, "horse", "")()
+	if len(recorder.Summary()) != 0 {
+		t.Error("a nil recorder should record nothing")
+	}
+}
+
+func TestWriteTrace(t *testing.T) {
+	recorder := timing.NewRecorder()
+	recorder.Start(timing.StepFetch, "horse", "")()
+	recorder.Start(timing.StepCompile, "horse", "Horse.dproj")()
+	recorder.Start(timing.StepCompile, "", "App.dproj")()
+
+	var out bytes.Buffer
+	if err := timing.WriteTrace(&out, recorder, "my-app"); err != nil {
+		t.Fatalf("WriteTrace() error = %v", err)
+	}
+	var trace struct {
+		TraceEvents []struct {
+			Name string
+			Ph   string
+			Tid  int
+			Dur  int64
+			Args map[string]string
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &trace); err != nil {
+		t.Fatalf("WriteTrace() wrote invalid JSON: %v", err)
+	}
+
+	tracks := map[int]string{}
+	spans := map[string]int{}
+	for _, event := range trace.TraceEvents {
+		switch event.Ph {
+		case "M":
+			tracks[event.Tid] = event.Args["name"]
+		case "X":
+			spans[event.Name] = event.Tid
+			if event.Dur <= 0 {
+				t.Errorf("span %s has no duration", event.Name)
+			}
+		}
+	}
+	if tracks[spans["compile Horse.dproj"]] != "horse" || tracks[spans["compile App.dproj"]] != "my-app" {
+		t.Errorf("tracks = %v, spans = %v; want one track per module", tracks, spans)
+	}
+	if spans["fetch"] != spans["compile Horse.dproj"] {
+		t.Error("the steps of a module should share its track")
+	}
+}
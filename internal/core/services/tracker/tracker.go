@@ -111,13 +111,17 @@ func (bt *BaseTracker[S]) Stop() {
 }
 
 // UpdateStatus updates the status of an item.
+// It is safe to call from concurrent goroutines, such as parallel builds.
 func (bt *BaseTracker[S]) UpdateStatus(name string, status S, message string) {
-	if !bt.enabled || bt.stopped {
+	if !bt.enabled {
 		return
 	}
 
 	bt.mu.Lock()
 	defer bt.mu.Unlock()
+	if bt.stopped {
+		return
+	}
 
 	progress, exists := bt.items[name]
 	if !exists {
@@ -132,12 +136,15 @@ func (bt *BaseTracker[S]) UpdateStatus(name string, status S, message string) {
 
 // AddItem dynamically adds a new item to the tracker.
 func (bt *BaseTracker[S]) AddItem(name string) {
-	if !bt.enabled || bt.stopped {
+	if !bt.enabled {
 		return
 	}
 
 	bt.mu.Lock()
 	defer bt.mu.Unlock()
+	if bt.stopped {
+		return
+	}
 
 	if _, exists := bt.items[name]; exists {
 		return
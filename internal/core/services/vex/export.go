@@ -0,0 +1,235 @@
+package vex
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/hashload/boss/internal/core/domain"
+)
+
+const (
+	openVexContext = "https://openvex.dev/ns/v0.2.0"
+	toolName       = "boss"
+)
+
+// Product is the software the statements are about.
+type Product struct {
+	Name    string
+	Version string
+	// ID identifies the product in the documents, normally its purl.
+	ID string
+	// Components holds the dependencies of the product by domain.RepositoryKey.
+	Components map[string]Component
+}
+
+// Component is a dependency the statements can refer to.
+type Component struct {
+	Name    string
+	Version string
+	Purl    string
+}
+
+// component returns the dependency a statement is about. A dependency that
+// is not part of the product is identified by the statement's own text.
+func (p Product) component(repository string) Component {
+	if component, ok := p.Components[domain.RepositoryKey(repository)]; ok && component.Purl != "" {
+		return component
+	}
+	return Component{Name: repository, Purl: repository}
+}
+
+// Options carries the document metadata.
+type Options struct {
+	// DocumentID is a UUID identifying the generated document.
+	DocumentID string
+	Author     string
+	Timestamp  time.Time
+}
+
+type openVexDocument struct {
+	Context    string             `json:"@context"`
+	ID         string             `json:"@id"`
+	Author     string             `json:"author"`
+	Timestamp  string             `json:"timestamp"`
+	Version    int                `json:"version"`
+	Tooling    string             `json:"tooling"`
+	Statements []openVexStatement `json:"statements"`
+}
+
+type openVexStatement struct {
+	Vulnerability   openVexVulnerability `json:"vulnerability"`
+	Timestamp       string               `json:"timestamp"`
+	Products        []openVexProduct     `json:"products"`
+	Status          Status               `json:"status"`
+	Justification   Justification        `json:"justification,omitempty"`
+	ImpactStatement string               `json:"impact_statement,omitempty"`
+	ActionStatement string               `json:"action_statement,omitempty"`
+}
+
+type openVexVulnerability struct {
+	Name string `json:"name"`
+}
+
+type openVexProduct struct {
+	ID            string           `json:"@id"`
+	Subcomponents []openVexProduct `json:"subcomponents,omitempty"`
+}
+
+// WriteOpenVEX writes the statements as an OpenVEX v0.2.0 document. The
+// product is the subject of every statement; the dependency that carries the
+// vulnerability is its subcomponent.
+func WriteOpenVEX(w io.Writer, product Product, statements []Statement, options Options) error {
+	doc := openVexDocument{
+		Context:    openVexContext,
+		ID:         "urn:uuid:" + options.DocumentID,
+		Author:     options.Author,
+		Timestamp:  options.Timestamp.UTC().Format(time.RFC3339),
+		Version:    1,
+		Tooling:    toolName,
+		Statements: make([]openVexStatement, 0, len(statements)),
+	}
+
+	for _, statement := range statements {
+		doc.Statements = append(doc.Statements, openVexStatement{
+			Vulnerability: openVexVulnerability{Name: statement.Vulnerability},
+			Timestamp:     statement.Timestamp.UTC().Format(time.RFC3339),
+			Products: []openVexProduct{{
+				ID:            product.ID,
+				Subcomponents: []openVexProduct{{ID: product.component(statement.Component).Purl}},
+			}},
+			Status:          statement.Status,
+			Justification:   statement.Justification,
+			ImpactStatement: statement.Impact,
+			ActionStatement: statement.Action,
+		})
+	}
+
+	return writeIndented(w, doc)
+}
+
+type cdxVexDocument struct {
+	BomFormat       string             `json:"bomFormat"`
+	SpecVersion     string             `json:"specVersion"`
+	SerialNumber    string             `json:"serialNumber"`
+	Version         int                `json:"version"`
+	Metadata        cdxVexMetadata     `json:"metadata"`
+	Components      []cdxVexComponent  `json:"components"`
+	Vulnerabilities []cdxVulnerability `json:"vulnerabilities"`
+}
+
+type cdxVexMetadata struct {
+	Timestamp string          `json:"timestamp"`
+	Component cdxVexComponent `json:"component"`
+}
+
+type cdxVexComponent struct {
+	BomRef  string `json:"bom-ref"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Purl    string `json:"purl,omitempty"`
+}
+
+type cdxVulnerability struct {
+	ID       string      `json:"id"`
+	Analysis cdxAnalysis `json:"analysis"`
+	Affects  []cdxAffect `json:"affects"`
+}
+
+type cdxAnalysis struct {
+	State         string `json:"state"`
+	Justification string `json:"justification,omitempty"`
+	Detail        string `json:"detail,omitempty"`
+	LastUpdated   string `json:"lastUpdated,omitempty"`
+}
+
+type cdxAffect struct {
+	Ref string `json:"ref"`
+}
+
+// cdxStates maps the OpenVEX statuses to CycloneDX impact analysis states.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var cdxStates = map[Status]string{
+	StatusNotAffected:        "not_affected",
+	StatusAffected:           "exploitable",
+	StatusFixed:              "resolved",
+	StatusUnderInvestigation: "in_triage",
+}
+
+// cdxJustifications maps the OpenVEX justifications to the closest CycloneDX
+// ones, following the CISA "Minimum Requirements for VEX" mapping.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var cdxJustifications = map[Justification]string{
+	JustificationComponentNotPresent:           "code_not_present",
+	JustificationVulnerableCodeNotPresent:      "code_not_present",
+	JustificationVulnerableCodeNotInExecute:    "code_not_reachable",
+	JustificationVulnerableCodeNotControllable: "requires_environment",
+	JustificationInlineMitigations:             "protected_by_mitigating_control",
+}
+
+// WriteCycloneDX writes the statements as a standalone CycloneDX 1.5 VEX
+// document. The affected dependencies are listed as components so every
+// "affects" reference resolves inside the document.
+func WriteCycloneDX(w io.Writer, product Product, statements []Statement, options Options) error {
+	doc := cdxVexDocument{
+		BomFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + options.DocumentID,
+		Version:      1,
+		Metadata: cdxVexMetadata{
+			Timestamp: options.Timestamp.UTC().Format(time.RFC3339),
+			Component: cdxVexComponent{
+				BomRef: product.ID, Type: "application", Name: product.Name, Version: product.Version, Purl: product.ID,
+			},
+		},
+		Components:      []cdxVexComponent{},
+		Vulnerabilities: make([]cdxVulnerability, 0, len(statements)),
+	}
+
+	listed := map[string]bool{}
+	for _, statement := range statements {
+		component := product.component(statement.Component)
+		if !listed[component.Purl] {
+			listed[component.Purl] = true
+			doc.Components = append(doc.Components, cdxVexComponent{
+				BomRef: component.Purl, Type: "library", Name: component.Name,
+				Version: component.Version, Purl: purlOrEmpty(component),
+			})
+		}
+
+		detail := statement.Impact
+		if statement.Status == StatusAffected {
+			detail = statement.Action
+		}
+		doc.Vulnerabilities = append(doc.Vulnerabilities, cdxVulnerability{
+			ID: statement.Vulnerability,
+			Analysis: cdxAnalysis{
+				State:         cdxStates[statement.Status],
+				Justification: cdxJustifications[statement.Justification],
+				Detail:        detail,
+				LastUpdated:   statement.Timestamp.UTC().Format(time.RFC3339),
+			},
+			Affects: []cdxAffect{{Ref: component.Purl}},
+		})
+	}
+
+	return writeIndented(w, doc)
+}
+
+// purlOrEmpty drops the identifier of a dependency that is not part of the
+// product: it is the statement's text, not a package URL.
+func purlOrEmpty(component Component) string {
+	if component.Purl == component.Name {
+		return ""
+	}
+	return component.Purl
+}
+
+func writeIndented(w io.Writer, payload any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(payload)
+}
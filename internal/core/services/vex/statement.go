@@ -0,0 +1,245 @@
+// Package vex keeps the VEX (Vulnerability Exploitability eXchange) statements
+// of a project: whether each vulnerability reported in a dependency affects
+// the product, and why. Statements live in boss-vex.json and are published as
+// CycloneDX VEX or OpenVEX documents.
+package vex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashload/boss/internal/core/domain"
+	"github.com/hashload/boss/internal/core/services/audit"
+)
+
+// Status is the exploitability of a vulnerability in the product.
+type Status string
+
+// Statuses, as defined by OpenVEX.
+const (
+	// StatusNotAffected means the product is not affected; a justification or
+	// an impact statement explains why.
+	StatusNotAffected Status = "not_affected"
+	// StatusAffected means the product is affected; an action statement tells
+	// users what to do.
+	StatusAffected Status = "affected"
+	// StatusFixed means the product contains a fix for the vulnerability.
+	StatusFixed Status = "fixed"
+	// StatusUnderInvestigation means it is not yet known whether the product
+	// is affected. Such statements do not count as triaged.
+	StatusUnderInvestigation Status = "under_investigation"
+)
+
+// Justification explains why a product is not affected.
+type Justification string
+
+// Justifications, as defined by OpenVEX.
+const (
+	JustificationComponentNotPresent           Justification = "component_not_present"
+	JustificationVulnerableCodeNotPresent      Justification = "vulnerable_code_not_present"
+	JustificationVulnerableCodeNotInExecute    Justification = "vulnerable_code_not_in_execute_path"
+	JustificationVulnerableCodeNotControllable Justification = "vulnerable_code_cannot_be_controlled_by_adversary"
+	JustificationInlineMitigations             Justification = "inline_mitigations_already_exist"
+)
+
+//nolint:gochecknoglobals // Read-only lookup tables
+var (
+	statuses       = []Status{StatusNotAffected, StatusAffected, StatusFixed, StatusUnderInvestigation}
+	justifications = []Justification{
+		JustificationComponentNotPresent, JustificationVulnerableCodeNotPresent,
+		JustificationVulnerableCodeNotInExecute, JustificationVulnerableCodeNotControllable,
+		JustificationInlineMitigations,
+	}
+)
+
+// ParseStatus validates a status name.
+func ParseStatus(value string) (Status, error) {
+	for _, status := range statuses {
+		if strings.EqualFold(value, string(status)) {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("unknown VEX status %q (use %s)", value, joinValues(statuses))
+}
+
+// ParseJustification validates a justification name.
+func ParseJustification(value string) (Justification, error) {
+	for _, justification := range justifications {
+		if strings.EqualFold(value, string(justification)) {
+			return justification, nil
+		}
+	}
+	return "", fmt.Errorf("unknown VEX justification %q (use %s)", value, joinValues(justifications))
+}
+
+func joinValues[T ~string](values []T) string {
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = string(value)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Statement records the exploitability of one vulnerability in one dependency.
+type Statement struct {
+	// Vulnerability is the advisory ID or one of its aliases (a CVE).
+	Vulnerability string `json:"vulnerability"`
+	// Component is the dependency repository, as in boss-lock.json.
+	Component     string        `json:"component"`
+	Status        Status        `json:"status"`
+	Justification Justification `json:"justification,omitempty"`
+	// Impact explains how the product avoids the vulnerability.
+	Impact string `json:"impact,omitempty"`
+	// Action tells users of an affected product what to do.
+	Action    string    `json:"action,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Validate applies the OpenVEX rules: a not_affected statement needs a
+// justification or an impact statement, an affected one an action statement,
+// and only not_affected takes a justification.
+func (s Statement) Validate() error {
+	if strings.TrimSpace(s.Vulnerability) == "" || strings.TrimSpace(s.Component) == "" {
+		return errors.New("a statement needs a vulnerability and a component")
+	}
+	if _, err := ParseStatus(string(s.Status)); err != nil {
+		return err
+	}
+	if s.Justification != "" {
+		if _, err := ParseJustification(string(s.Justification)); err != nil {
+			return err
+		}
+		if s.Status != StatusNotAffected {
+			return fmt.Errorf("a justification only applies to %s statements", StatusNotAffected)
+		}
+	}
+	switch s.Status {
+	case StatusNotAffected:
+		if s.Justification == "" && strings.TrimSpace(s.Impact) == "" {
+			return fmt.Errorf("a %s statement needs a justification or an impact statement", s.Status)
+		}
+	case StatusAffected:
+		if strings.TrimSpace(s.Action) == "" {
+			return fmt.Errorf("an %s statement needs an action statement", s.Status)
+		}
+	case StatusFixed, StatusUnderInvestigation:
+	}
+	return nil
+}
+
+// Matches reports whether the statement is about the finding: same
+// dependency, and the vulnerability is the advisory ID or one of its aliases.
+func (s Statement) Matches(finding audit.Finding) bool {
+	if domain.RepositoryKey(s.Component) != domain.RepositoryKey(finding.Repository) {
+		return false
+	}
+	for _, id := range append([]string{finding.AdvisoryID}, finding.Aliases...) {
+		if strings.EqualFold(id, s.Vulnerability) {
+			return true
+		}
+	}
+	return false
+}
+
+// Document is the content of boss-vex.json.
+type Document struct {
+	Statements []Statement `json:"statements"`
+}
+
+// Load reads the statements file. A missing file is an empty document.
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- Reading the project's VEX statements
+	if errors.Is(err, os.ErrNotExist) {
+		return &Document{Statements: []Statement{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+	if err = json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	for i, statement := range doc.Statements {
+		if err = statement.Validate(); err != nil {
+			return nil, fmt.Errorf("statement %d (%s in %s): %w", i+1, statement.Vulnerability, statement.Component, err)
+		}
+	}
+	return doc, nil
+}
+
+// Save writes the statements, sorted by component and vulnerability so the
+// file diffs cleanly.
+func (d *Document) Save(path string) error {
+	sort.Slice(d.Statements, func(i, j int) bool {
+		a, b := d.Statements[i], d.Statements[j]
+		if a.Component != b.Component {
+			return a.Component < b.Component
+		}
+		return a.Vulnerability < b.Vulnerability
+	})
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Set adds the statement or replaces the one about the same vulnerability in
+// the same component.
+func (d *Document) Set(statement Statement) {
+	if statement.Timestamp.IsZero() {
+		statement.Timestamp = time.Now().UTC().Truncate(time.Second)
+	}
+	for i, existing := range d.Statements {
+		if existing.same(statement.Vulnerability, statement.Component) {
+			d.Statements[i] = statement
+			return
+		}
+	}
+	d.Statements = append(d.Statements, statement)
+}
+
+// Remove deletes the statement about a vulnerability in a component and
+// reports whether there was one.
+func (d *Document) Remove(vulnerability, component string) bool {
+	for i, existing := range d.Statements {
+		if existing.same(vulnerability, component) {
+			d.Statements = append(d.Statements[:i], d.Statements[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s Statement) same(vulnerability, component string) bool {
+	return strings.EqualFold(s.Vulnerability, vulnerability) && domain.RepositoryKey(s.Component) == domain.RepositoryKey(component)
+}
+
+// Lookup returns the statement about a finding.
+func (d *Document) Lookup(finding audit.Finding) (Statement, bool) {
+	for _, statement := range d.Statements {
+		if statement.Matches(finding) {
+			return statement, true
+		}
+	}
+	return Statement{}, false
+}
+
+// Untriaged returns the findings without a statement, or whose statement is
+// still under investigation.
+func (d *Document) Untriaged(findings []audit.Finding) []audit.Finding {
+	var untriaged []audit.Finding
+	for _, finding := range findings {
+		if statement, ok := d.Lookup(finding); !ok || statement.Status == StatusUnderInvestigation {
+			untriaged = append(untriaged, finding)
+		}
+	}
+	return untriaged
+}
@@ -0,0 +1,172 @@
+//nolint:testpackage // Testing internal implementation details
+package vex
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashload/boss/internal/core/services/audit"
+)
+
+func TestStatementValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement Statement
+		wantErr   bool
+	}{
+		{"not affected with justification", Statement{
+			Vulnerability: "CVE-1", Component: "hashload/horse", Status: StatusNotAffected,
+			Justification: JustificationVulnerableCodeNotInExecute,
+		}, false},
+		{"not affected with impact", Statement{
+			Vulnerability: "CVE-1", Component: "hashload/horse", Status: StatusNotAffected, Impact: "unused",
+		}, false},
+		{"not affected without reason", Statement{
+			Vulnerability: "CVE-1", Component: "hashload/horse", Status: StatusNotAffected,
+		}, true},
+		{"affected without action", Statement{
+			Vulnerability: "CVE-1", Component: "hashload/horse", Status: StatusAffected,
+		}, true},
+		{"justification on fixed", Statement{
+			Vulnerability: "CVE-1", Component: "hashload/horse", Status: StatusFixed,
+			Justification: JustificationComponentNotPresent,
+		}, true},
+		{"unknown status", Statement{Vulnerability: "CVE-1", Component: "hashload/horse", Status: "ignored"}, true},
+		{"missing component", Statement{Vulnerability: "CVE-1", Status: StatusFixed}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.statement.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDocument_SetRemoveAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "boss-vex.json")
+
+	doc, err := Load(path)
+	if err != nil || len(doc.Statements) != 0 {
+		t.Fatalf("Load() of a missing file = %+v, %v", doc, err)
+	}
+
+	doc.Set(Statement{Vulnerability: "CVE-1", Component: "hashload/horse", Status: StatusUnderInvestigation})
+	doc.Set(Statement{Vulnerability: "cve-1", Component: "github.com/HashLoad/horse", Status: StatusFixed})
+	doc.Set(Statement{Vulnerability: "CVE-2", Component: "hashload/jhonson", Status: StatusFixed})
+	if len(doc.Statements) != 2 || doc.Statements[0].Status != StatusFixed {
+		t.Fatalf("Set() should replace the statement about the same vulnerability: %+v", doc.Statements)
+	}
+	if doc.Statements[0].Timestamp.IsZero() {
+		t.Error("Set() should stamp the statement")
+	}
+	if err = doc.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil || len(reloaded.Statements) != 2 {
+		t.Fatalf("Load() = %+v, %v", reloaded, err)
+	}
+	if !reloaded.Remove("CVE-2", "github.com/hashload/jhonson") || reloaded.Remove("CVE-2", "hashload/jhonson") {
+		t.Error("Remove() should delete the statement once")
+	}
+}
+
+func TestDocument_Untriaged(t *testing.T) {
+	findings := []audit.Finding{
+		{Repository: "github.com/hashload/horse", AdvisoryID: "BOSS-2024-0001", Aliases: []string{"CVE-2024-0001"}},
+		{Repository: "hashload/jhonson", AdvisoryID: "BOSS-2024-0002"},
+		{Repository: "hashload/dataset", AdvisoryID: "BOSS-2024-0003"},
+	}
+	doc := &Document{Statements: []Statement{
+		{Vulnerability: "CVE-2024-0001", Component: "hashload/horse", Status: StatusNotAffected, Impact: "unused"},
+		{Vulnerability: "BOSS-2024-0002", Component: "hashload/jhonson", Status: StatusUnderInvestigation},
+	}}
+
+	untriaged := doc.Untriaged(findings)
+	if len(untriaged) != 2 || untriaged[0].AdvisoryID != "BOSS-2024-0002" || untriaged[1].AdvisoryID != "BOSS-2024-0003" {
+		t.Errorf("Untriaged() = %+v", untriaged)
+	}
+}
+
+func testProduct() Product {
+	return Product{
+		Name: "app", Version: "1.0.0", ID: "pkg:generic/app@1.0.0",
+		Components: map[string]Component{
+			"github.com/hashload/horse": {Name: "hashload/horse", Version: "v3.0.0", Purl: "pkg:github/hashload/horse@v3.0.0"},
+		},
+	}
+}
+
+func testStatements() []Statement {
+	stamp := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []Statement{
+		{Vulnerability: "CVE-1", Component: "hashload/horse", Status: StatusNotAffected,
+			Justification: JustificationVulnerableCodeNotInExecute, Timestamp: stamp},
+		{Vulnerability: "CVE-2", Component: "acme/unlocked", Status: StatusAffected, Action: "Upgrade", Timestamp: stamp},
+	}
+}
+
+func TestWriteOpenVEX(t *testing.T) {
+	var buf bytes.Buffer
+	options := Options{DocumentID: "1234", Author: "app", Timestamp: time.Now()}
+	if err := WriteOpenVEX(&buf, testProduct(), testStatements(), options); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc openVexDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.Context != openVexContext || doc.ID != "urn:uuid:1234" || len(doc.Statements) != 2 {
+		t.Fatalf("unexpected document %+v", doc)
+	}
+	first := doc.Statements[0]
+	if first.Products[0].ID != "pkg:generic/app@1.0.0" ||
+		first.Products[0].Subcomponents[0].ID != "pkg:github/hashload/horse@v3.0.0" {
+		t.Errorf("unexpected products %+v", first.Products)
+	}
+	if first.Justification != JustificationVulnerableCodeNotInExecute {
+		t.Errorf("justification = %q", first.Justification)
+	}
+	if doc.Statements[1].ActionStatement != "Upgrade" {
+		t.Errorf("action statement = %q", doc.Statements[1].ActionStatement)
+	}
+}
+
+func TestWriteCycloneDX(t *testing.T) {
+	var buf bytes.Buffer
+	options := Options{DocumentID: "1234", Author: "app", Timestamp: time.Now()}
+	if err := WriteCycloneDX(&buf, testProduct(), testStatements(), options); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc cdxVexDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(doc.Components) != 2 || len(doc.Vulnerabilities) != 2 {
+		t.Fatalf("unexpected document %+v", doc)
+	}
+	refs := map[string]bool{}
+	for _, component := range doc.Components {
+		refs[component.BomRef] = true
+	}
+	for _, vulnerability := range doc.Vulnerabilities {
+		if !refs[vulnerability.Affects[0].Ref] {
+			t.Errorf("%s affects an unknown ref %q", vulnerability.ID, vulnerability.Affects[0].Ref)
+		}
+	}
+	first := doc.Vulnerabilities[0].Analysis
+	if first.State != "not_affected" || first.Justification != "code_not_reachable" {
+		t.Errorf("unexpected analysis %+v", first)
+	}
+	if second := doc.Vulnerabilities[1].Analysis; second.State != "exploitable" || second.Detail != "Upgrade" {
+		t.Errorf("unexpected analysis %+v", second)
+	}
+}
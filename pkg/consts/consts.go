@@ -7,7 +7,9 @@ import "path/filepath"
 const (
 	FilePackage        = "boss.json"
 	FilePackageLock    = "boss-lock.json"
+	FileVex            = "boss-vex.json"
 	FileBplOrder       = "bpl_order.txt"
+	FileHashIndex      = "hash-index.json"
 	FileExtensionBpl   = ".bpl"
 	FileExtensionDcp   = ".dcp"
 	FileExtensionDpk   = ".dpk"
@@ -27,6 +29,13 @@ const (
 
 	FolderBossHome = ".boss"
 
+	FolderAdvisories = "advisories"
+
+	FolderBuildCache = "build"
+
+	// FolderBuildLogs holds the logs of every build, inside modules.
+	FolderBuildLogs = ".logs"
+
 	BinFolder string = ".bin"
 	BplFolder string = ".bpl"
 	DcpFolder string = ".dcp"
@@ -70,6 +79,8 @@ const (
 	StatusMsgNoProjects       = "no projects"
 	StatusMsgNoBossJSON       = "no boss.json"
 	StatusMsgBuildError       = "build error"
+	StatusMsgFromCache        = "from cache"
+	StatusMsgPrebuilt         = "prebuilt"
 	StatusMsgAlreadyUpToDate  = "boss is already up to date"
 
 	GitBranchMain   = "main"
@@ -0,0 +1,30 @@
+package env
+
+import (
+	"path/filepath"
+
+	"github.com/hashload/boss/pkg/consts"
+)
+
+// BuildCacheConfig configures the cache of compiled dependency artifacts,
+// kept in the "build_cache" section of the global configuration. The local
+// cache is on unless Disabled; URL adds a shared HTTP cache, written to only
+// when Push is set.
+type BuildCacheConfig struct {
+	Disabled bool   `json:"disabled,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Push     bool   `json:"push,omitempty"`
+}
+
+// GetBuildCache returns the build cache configuration, never nil.
+func (c *Configuration) GetBuildCache() *BuildCacheConfig {
+	if c.BuildCache == nil {
+		c.BuildCache = &BuildCacheConfig{}
+	}
+	return c.BuildCache
+}
+
+// GetBuildCacheDir returns the directory of the local build cache.
+func GetBuildCacheDir() string {
+	return filepath.Join(GetCacheDir(), consts.FolderBuildCache)
+}
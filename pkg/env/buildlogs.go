@@ -0,0 +1,52 @@
+package env
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/hashload/boss/pkg/consts"
+)
+
+const (
+	// DefaultBuildLogsKeepRuns is the number of build runs whose logs are kept.
+	DefaultBuildLogsKeepRuns = 20
+	// DefaultBuildLogsMaxAgeDays is the number of days build logs are kept.
+	DefaultBuildLogsMaxAgeDays = 30
+)
+
+// BuildLogsConfig is the retention of build logs, kept in the "build_logs"
+// section of the global configuration. Zero values mean the defaults.
+type BuildLogsConfig struct {
+	KeepRuns   int `json:"keep_runs,omitempty"`
+	MaxAgeDays int `json:"max_age_days,omitempty"`
+}
+
+// GetBuildLogs returns the build log retention, never nil.
+func (c *Configuration) GetBuildLogs() *BuildLogsConfig {
+	if c.BuildLogs == nil {
+		c.BuildLogs = &BuildLogsConfig{}
+	}
+	return c.BuildLogs
+}
+
+// Keep returns the number of runs whose logs are kept.
+func (c *BuildLogsConfig) Keep() int {
+	if c.KeepRuns <= 0 {
+		return DefaultBuildLogsKeepRuns
+	}
+	return c.KeepRuns
+}
+
+// MaxAge returns how long the logs of a run are kept.
+func (c *BuildLogsConfig) MaxAge() time.Duration {
+	days := c.MaxAgeDays
+	if days <= 0 {
+		days = DefaultBuildLogsMaxAgeDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// GetBuildLogsDir returns the directory of the build logs of the project.
+func GetBuildLogsDir() string {
+	return filepath.Join(GetModulesDir(), consts.FolderBuildLogs)
+}
@@ -95,6 +95,12 @@ func GetCacheDir() string {
 	return filepath.Join(GetBossHome(), "cache")
 }
 
+// GetAdvisoryDir returns the directory holding the local OSV advisory database
+// used by 'boss audit'. It lives outside the cache so cache purges keep it.
+func GetAdvisoryDir() string {
+	return filepath.Join(GetBossHome(), consts.FolderAdvisories)
+}
+
 // GetBossHome returns the Boss home directory.
 func GetBossHome() string {
 	homeDir := os.Getenv("BOSS_HOME")
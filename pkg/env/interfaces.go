@@ -18,6 +18,7 @@ type ConfigProvider interface {
 	GetLastPurge() time.Time
 	GetLastInternalUpdate() time.Time
 	GetConfigVersion() int64
+	GetTrust() *TrustConfig
 	SetLastPurge(t time.Time)
 	SetLastInternalUpdate(t time.Time)
 	SetConfigVersion(version int64)
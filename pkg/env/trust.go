@@ -0,0 +1,38 @@
+package env
+
+// SignaturePolicy says what happens when the tag or commit a dependency
+// resolves to is not signed by a trusted key.
+type SignaturePolicy string
+
+const (
+	// SignatureOff skips verification.
+	SignatureOff SignaturePolicy = "off"
+	// SignatureWarn installs the dependency and reports the failure.
+	SignatureWarn SignaturePolicy = "warn"
+	// SignatureRequire fails the installation. It is the default for a rule
+	// that lists keys.
+	SignatureRequire SignaturePolicy = "require"
+)
+
+// TrustRule lists the keys trusted to sign the releases of a host or of a
+// single dependency.
+type TrustRule struct {
+	Policy SignaturePolicy `json:"policy,omitempty"`
+	// Keys holds OpenPGP public keys (armored) and SSH public keys
+	// (authorized_keys or allowed_signers lines), inline or as paths to files.
+	Keys []string `json:"keys,omitempty"`
+}
+
+// TrustConfig is the signature trust configuration, kept in the "trust"
+// section of boss.json or of the global configuration. Dependency rules are
+// keyed by repository ("github.com/hashload/horse"), host rules by host
+// ("github.com").
+type TrustConfig struct {
+	Hosts        map[string]*TrustRule `json:"hosts,omitempty"`
+	Dependencies map[string]*TrustRule `json:"dependencies,omitempty"`
+}
+
+// GetTrust returns the global signature trust configuration.
+func (c *Configuration) GetTrust() *TrustConfig {
+	return c.Trust
+}
@@ -2,47 +2,191 @@
 package utils
 
 import (
-	//nolint:gosec,nolintlint // MD5 is used for hash comparison
-	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/hashload/boss/pkg/msg"
+	"github.com/hashload/boss/pkg/consts"
 )
 
-// hashByte calculates the MD5 hash of a byte slice.
-func hashByte(contentPtr *[]byte) string {
-	contents := *contentPtr
-	//nolint:gosec,nolintlint // MD5 is used for hash comparison
-	hasher := md5.New()
-	hasher.Write(contents)
-	return hex.EncodeToString(hasher.Sum(nil))
-}
-
-// HashDir calculates the MD5 hash of a directory's contents.
-func HashDir(dir string) string {
-	var err error
-	var finalHash = "b:"
-	err = filepath.Walk(dir, func(path string, _ os.FileInfo, err error) error {
-		if err != nil && !os.IsNotExist(err) {
-			msg.Warn("⚠️ Failed to read file %s", path)
-			return nil
+// manifestIgnoredDirs are VCS metadata and IDE history folders: they change
+// without the module's sources changing.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var manifestIgnoredDirs = map[string]bool{
+	".git":       true,
+	".svn":       true,
+	".hg":        true,
+	"__history":  true,
+	"__recovery": true,
+}
+
+// manifestOutputDirs are the folders at the root of a module that Boss builds
+// it into, and restores the build cache to.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var manifestOutputDirs = map[string]bool{
+	consts.BplFolder: true,
+	consts.DcpFolder: true,
+	consts.DcuFolder: true,
+	consts.BinFolder: true,
+}
+
+// manifestIgnoredExts are IDE and linker by-products written next to the
+// sources. Compiled units and packages are not among them: vendors ship some
+// precompiled, and those must not change unnoticed.
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var manifestIgnoredExts = map[string]bool{
+	".drc":        true,
+	".map":        true,
+	".tds":        true,
+	".rsm":        true,
+	".identcache": true,
+	".local":      true,
+	".stat":       true,
+	".compiled":   true,
+}
+
+// IgnoredInManifest reports whether a path relative to a module directory is
+// left out of the module's file manifest.
+func IgnoredInManifest(relPath string) bool {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	if len(parts) > 1 && manifestOutputDirs[parts[0]] {
+		return true
+	}
+	for _, dir := range parts[:len(parts)-1] {
+		if manifestIgnoredDirs[dir] {
+			return true
 		}
+	}
+	return manifestIgnoredExts[strings.ToLower(filepath.Ext(relPath))]
+}
 
-		if os.IsNotExist(err) {
+// HashFiles returns the SHA-256 of every regular file under dir, keyed by the
+// slash-separated path relative to dir. VCS metadata and build outputs are
+// skipped (see IgnoredInManifest).
+func HashFiles(dir string) (map[string]string, error) {
+	return hashFiles(dir, nil)
+}
+
+// manifestFile is a file found while walking a module directory.
+type manifestFile struct {
+	rel  string
+	path string
+	stat fileStat
+}
+
+// hashFiles walks dir and hashes its files in parallel, taking the digest of
+// files whose size and modification time are unchanged from index.
+func hashFiles(dir string, index *HashIndex) (map[string]string, error) {
+	var found []manifestFile
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if manifestIgnoredDirs[entry.Name()] || manifestOutputDirs[rel] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || IgnoredInManifest(rel) {
 			return nil
 		}
 
-		fileBytes, _ := os.ReadFile(path) // #nosec G304,G122 -- Reading files from controlled directory structure for hashing
-		fileHash := hashByte(&fileBytes)
-		finalHash += fileHash
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		found = append(found, manifestFile{
+			rel:  filepath.ToSlash(rel),
+			path: path,
+			stat: fileStat{Size: info.Size(), ModTime: info.ModTime().UnixNano()},
+		})
 		return nil
 	})
 	if err != nil {
-		msg.Die("❌ Failed to hash directory: %v", err)
+		return nil, err
+	}
+
+	started := time.Now()
+	files := make(map[string]string, len(found))
+	var stale []manifestFile
+	cached := index.lookup(dir)
+	for _, file := range found {
+		if entry, ok := cached[file.rel]; ok && entry.fileStat == file.stat {
+			files[file.rel] = entry.Hash
+		} else {
+			stale = append(stale, file)
+		}
+	}
+
+	digests, err := hashInParallel(stale)
+	if err != nil {
+		return nil, err
+	}
+	for i, file := range stale {
+		files[file.rel] = digests[i]
+	}
+
+	index.store(dir, found, files, started)
+	return files, nil
+}
+
+// hashInParallel hashes files on one worker per CPU and returns the digests in
+// the order of files.
+func hashInParallel(files []manifestFile) ([]string, error) {
+	digests := make([]string, len(files))
+	errs := make([]error, len(files))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), len(files)) {
+		wg.Go(func() {
+			for i := range jobs {
+				digests[i], errs[i] = HashFile(files[i].path)
+			}
+		})
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return digests, nil
+}
+
+// HashFile returns the hex SHA-256 of a file's content.
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path) // #nosec G304 -- Hashing files of an installed module
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	hasher := sha256.New()
+	if _, err = io.Copy(hasher, file); err != nil {
+		return "", err
 	}
-	c := []byte(finalHash)
-	m := hashByte(&c)
-	return m
+	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// hashIndexVersion is bumped whenever the digests stored in the index change
+// meaning, so an index written by another version is discarded.
+const hashIndexVersion = 1
+
+// racyWindow keeps files modified this close to the moment they were hashed
+// out of the index: on file systems with coarse timestamps a second write in
+// the same tick leaves size and modification time unchanged.
+const racyWindow = 2 * time.Second
+
+// fileStat is what decides whether a file changed since it was hashed.
+type fileStat struct {
+	Size    int64 `json:"size"`
+	ModTime int64 `json:"mtime"`
+}
+
+type hashIndexEntry struct {
+	fileStat
+
+	Hash string `json:"hash"`
+}
+
+type hashIndexFile struct {
+	Version int                                  `json:"version"`
+	Dirs    map[string]map[string]hashIndexEntry `json:"dirs"`
+}
+
+// HashIndex remembers the SHA-256 of the files of each hashed directory
+// together with their size and modification time, so HashFiles only reads
+// the files that changed since the previous run. The digests it returns are
+// the same as a full rehash.
+type HashIndex struct {
+	path  string
+	mu    sync.Mutex
+	dirs  map[string]map[string]hashIndexEntry
+	dirty bool
+}
+
+// LoadHashIndex reads the index stored at path. A missing, unreadable or
+// outdated index yields an empty one: it is only a cache.
+func LoadHashIndex(path string) *HashIndex {
+	index := &HashIndex{path: path, dirs: map[string]map[string]hashIndexEntry{}}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- Reading the hash index in the Boss cache
+	if err != nil {
+		return index
+	}
+	var stored hashIndexFile
+	if json.Unmarshal(data, &stored) == nil && stored.Version == hashIndexVersion && stored.Dirs != nil {
+		index.dirs = stored.Dirs
+	}
+	return index
+}
+
+// HashFiles works like the package-level HashFiles but takes the digest of
+// unchanged files from the index. The index is updated with the result; call
+// Save to persist it.
+func (idx *HashIndex) HashFiles(dir string) (map[string]string, error) {
+	return hashFiles(dir, idx)
+}
+
+// Save writes the index back if it changed.
+func (idx *HashIndex) Save() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(hashIndexFile{Version: hashIndexVersion, Dirs: idx.dirs})
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(idx.path), 0755); err != nil {
+		return err
+	}
+	// Written aside and renamed so a concurrent reader never sees half a file.
+	tmp := idx.path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, idx.path); err != nil {
+		return err
+	}
+	idx.dirty = false
+	return nil
+}
+
+func indexKey(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return filepath.Clean(dir)
+}
+
+func (idx *HashIndex) lookup(dir string) map[string]hashIndexEntry {
+	if idx == nil {
+		return nil
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return idx.dirs[indexKey(dir)]
+}
+
+// store replaces the entries of dir, which also drops the files that no
+// longer exist.
+func (idx *HashIndex) store(dir string, found []manifestFile, digests map[string]string, hashedAt time.Time) {
+	if idx == nil {
+		return
+	}
+	racy := hashedAt.Add(-racyWindow).UnixNano()
+	entries := make(map[string]hashIndexEntry, len(found))
+	for _, file := range found {
+		if file.stat.ModTime < racy {
+			entries[file.rel] = hashIndexEntry{fileStat: file.stat, Hash: digests[file.rel]}
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	key := indexKey(dir)
+	if previous, ok := idx.dirs[key]; ok && sameEntries(previous, entries) {
+		return
+	}
+	idx.dirs[key] = entries
+	idx.dirty = true
+}
+
+func sameEntries(a, b map[string]hashIndexEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, entry := range a {
+		if b[path] != entry {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package utils_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashload/boss/utils"
+)
+
+// writeAged writes a file with a modification time outside the racy window,
+// so the index keeps it.
+func writeAged(t *testing.T, path, content string, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	when := time.Now().Add(-age)
+	if err := os.Chtimes(path, when, when); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHashIndex_MatchesFullRehash(t *testing.T) {
+	dir := t.TempDir()
+	for i, name := range []string{"a.pas", "src/b.pas", "src/c.inc", "d.dcu"} {
+		writeAged(t, filepath.Join(dir, name), name, time.Duration(i+1)*time.Hour)
+	}
+	writeAged(t, filepath.Join(dir, "fresh.pas"), "just written", 0)
+
+	indexPath := filepath.Join(t.TempDir(), "cache", "hash-index.json")
+	index := utils.LoadHashIndex(indexPath)
+	first, err := index.HashFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = index.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	full, _ := utils.HashFiles(dir)
+	second, err := utils.LoadHashIndex(indexPath).HashFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, full) || !reflect.DeepEqual(second, full) {
+		t.Errorf("indexed hashes differ from a full rehash:\n%v\n%v\n%v", first, second, full)
+	}
+}
+
+func TestHashIndex_SkipsUnchangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Horse.pas")
+	writeAged(t, path, "unit A;", time.Hour)
+
+	index := utils.LoadHashIndex(filepath.Join(t.TempDir(), "hash-index.json"))
+	before, _ := index.HashFiles(dir)
+
+	// Same size and modification time: the index answers without reading.
+	info, _ := os.Stat(path)
+	_ = os.WriteFile(path, []byte("unit B;"), 0644)
+	_ = os.Chtimes(path, info.ModTime(), info.ModTime())
+	if cached, _ := index.HashFiles(dir); cached["Horse.pas"] != before["Horse.pas"] {
+		t.Error("an unchanged stat should reuse the indexed hash")
+	}
+
+	// A new modification time forces a rehash.
+	writeAged(t, path, "unit B;", 30*time.Minute)
+	after, _ := index.HashFiles(dir)
+	full, _ := utils.HashFiles(dir)
+	if after["Horse.pas"] == before["Horse.pas"] || after["Horse.pas"] != full["Horse.pas"] {
+		t.Error("a modified file should be rehashed")
+	}
+}
+
+func TestHashIndex_RecentFilesAreNotIndexed(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Horse.pas")
+	writeAged(t, path, "unit A;", 0)
+
+	index := utils.LoadHashIndex(filepath.Join(t.TempDir(), "hash-index.json"))
+	_, _ = index.HashFiles(dir)
+
+	// Rewritten within the same timestamp tick: only a rehash notices.
+	info, _ := os.Stat(path)
+	_ = os.WriteFile(path, []byte("unit B;"), 0644)
+	_ = os.Chtimes(path, info.ModTime(), info.ModTime())
+	got, _ := index.HashFiles(dir)
+	full, _ := utils.HashFiles(dir)
+	if got["Horse.pas"] != full["Horse.pas"] {
+		t.Error("a file modified within the racy window must not come from the index")
+	}
+}
+
+func TestLoadHashIndex_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hash-index.json")
+	_ = os.WriteFile(path, []byte("{not json"), 0644)
+
+	dir := t.TempDir()
+	setupDir(t, dir, "content")
+	files, err := utils.LoadHashIndex(path).HashFiles(dir)
+	if err != nil || len(files) != 1 {
+		t.Errorf("HashFiles() with a corrupt index = %v, %v", files, err)
+	}
+}
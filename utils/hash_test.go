@@ -8,105 +8,105 @@ import (
 	"github.com/hashload/boss/utils"
 )
 
-func TestHashDir_EmptyDirectory(t *testing.T) {
-	tempDir := t.TempDir()
-	emptyDir := filepath.Join(tempDir, "empty")
-
-	err := os.MkdirAll(emptyDir, 0755)
+func TestHashFiles_EmptyDirectory(t *testing.T) {
+	files, err := utils.HashFiles(t.TempDir())
 	if err != nil {
-		t.Fatalf("Failed to create empty dir: %v", err)
+		t.Fatalf("HashFiles() error = %v", err)
 	}
-
-	hash := utils.HashDir(emptyDir)
-	if hash == "" {
-		t.Error("HashDir returned empty string for empty directory")
+	if len(files) != 0 {
+		t.Errorf("HashFiles() of an empty directory = %v, want no entries", files)
 	}
 }
 
-func TestHashDir_SingleFile(t *testing.T) {
-	tempDir := t.TempDir()
-	singleFileDir := filepath.Join(tempDir, "single")
-
-	err := os.MkdirAll(singleFileDir, 0755)
-	if err != nil {
-		t.Fatalf("Failed to create dir: %v", err)
-	}
+func TestHashFiles_SingleFile(t *testing.T) {
+	dir := t.TempDir()
+	setupDir(t, dir, "hello world")
 
-	filePath := filepath.Join(singleFileDir, "test.txt")
-	err = os.WriteFile(filePath, []byte("hello world"), 0644)
+	files, err := utils.HashFiles(dir)
 	if err != nil {
-		t.Fatalf("Failed to create file: %v", err)
-	}
-
-	hash := utils.HashDir(singleFileDir)
-	if hash == "" {
-		t.Error("HashDir returned empty string")
+		t.Fatalf("HashFiles() error = %v", err)
 	}
-	if len(hash) != 32 {
-		t.Errorf("HashDir returned invalid hash length: got %d, want 32", len(hash))
+	want := "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	if files["file.txt"] != want {
+		t.Errorf("HashFiles()[file.txt] = %q, want %q", files["file.txt"], want)
 	}
 }
 
-func TestHashDir_SameContentSameHash(t *testing.T) {
+func TestHashFiles_SameContentSameHash(t *testing.T) {
 	tempDir := t.TempDir()
 	dir1 := filepath.Join(tempDir, "dir1")
 	dir2 := filepath.Join(tempDir, "dir2")
+	setupDir(t, dir1, "same content")
+	setupDir(t, dir2, "same content")
 
-	for _, dir := range []string{dir1, dir2} {
-		err := os.MkdirAll(dir, 0755)
-		if err != nil {
-			t.Fatalf("Failed to create dir: %v", err)
-		}
-		err = os.WriteFile(filepath.Join(dir, "file.txt"), []byte("same content"), 0644)
-		if err != nil {
-			t.Fatalf("Failed to create file: %v", err)
-		}
-	}
-
-	hash1 := utils.HashDir(dir1)
-	hash2 := utils.HashDir(dir2)
-
-	if hash1 != hash2 {
-		t.Errorf("Same content should produce same hash: got %s and %s", hash1, hash2)
+	files1, _ := utils.HashFiles(dir1)
+	files2, _ := utils.HashFiles(dir2)
+	if files1["file.txt"] != files2["file.txt"] {
+		t.Errorf("Same content should produce same hash: got %s and %s", files1["file.txt"], files2["file.txt"])
 	}
 }
 
-func TestHashDir_DifferentContentDifferentHash(t *testing.T) {
+func TestHashFiles_DifferentContentDifferentHash(t *testing.T) {
 	tempDir := t.TempDir()
 	dir1 := filepath.Join(tempDir, "diff1")
 	dir2 := filepath.Join(tempDir, "diff2")
-
 	setupDir(t, dir1, "content A")
 	setupDir(t, dir2, "content B")
 
-	hash1 := utils.HashDir(dir1)
-	hash2 := utils.HashDir(dir2)
-
-	if hash1 == hash2 {
+	files1, _ := utils.HashFiles(dir1)
+	files2, _ := utils.HashFiles(dir2)
+	if files1["file.txt"] == files2["file.txt"] {
 		t.Error("Different content should produce different hash")
 	}
 }
 
-func TestHashDir_NestedDirectories(t *testing.T) {
-	tempDir := t.TempDir()
-	nestedDir := filepath.Join(tempDir, "nested", "sub1", "sub2")
-
-	err := os.MkdirAll(nestedDir, 0755)
-	if err != nil {
-		t.Fatalf("Failed to create nested dir: %v", err)
+func TestHashFiles_NestedAndIgnored(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{
+		"src/sub/deep.pas",
+		".git/HEAD",
+		".git/objects/ab/cdef",
+		"src/__history/deep.pas.~1~",
+		"src/sub/deep.identcache",
+		".dcu/Horse.dcu",
+		".bpl/Release/Horse.bpl",
+	} {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(path), 0644); err != nil {
+			t.Fatal(err)
+		}
 	}
 
-	err = os.WriteFile(filepath.Join(nestedDir, "deep.txt"), []byte("deep file"), 0644)
+	files, err := utils.HashFiles(dir)
 	if err != nil {
-		t.Fatalf("Failed to create file: %v", err)
+		t.Fatalf("HashFiles() error = %v", err)
 	}
+	if len(files) != 1 || files["src/sub/deep.pas"] == "" {
+		t.Errorf("HashFiles() = %v, want only src/sub/deep.pas", files)
+	}
+}
 
-	hash := utils.HashDir(filepath.Join(tempDir, "nested"))
-	if hash == "" {
-		t.Error("HashDir returned empty string for nested directory")
+func TestIgnoredInManifest(t *testing.T) {
+	tests := map[string]bool{
+		"Source/Horse.pas":           false,
+		"boss.json":                  false,
+		"Source/Horse.DCU":           false,
+		"lib/Win32/Horse.bpl":        false,
+		".dcu/Horse.dcu":             true,
+		".bin/Win64/Release/App.exe": true,
+		"Source/.dcu/Horse.dcu":      false,
+		".git/config":                true,
+		"Source/__recovery/a.pas":    true,
+		"Samples/Project.identcache": true,
+		"git/notes.txt":              false,
 	}
-	if len(hash) != 32 {
-		t.Errorf("HashDir returned invalid hash length: got %d, want 32", len(hash))
+	for path, want := range tests {
+		if got := utils.IgnoredInManifest(path); got != want {
+			t.Errorf("IgnoredInManifest(%q) = %v, want %v", path, got, want)
+		}
 	}
 }
 